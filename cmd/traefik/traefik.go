@@ -30,6 +30,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
@@ -268,9 +269,24 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
 	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, observabilityMgr, roundTripperManager, acmeHTTPHandler)
 
+	// GeoIP
+
+	var geoIPDatabase *geoip.Database
+	if staticConfiguration.GeoIP != nil {
+		geoIPDatabase, err = geoip.NewDatabase(staticConfiguration.GeoIP.Databases)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load GeoIP databases: %w", err)
+		}
+
+		err = geoIPDatabase.Watch(routinesPool)
+		if err != nil {
+			return nil, fmt.Errorf("unable to watch GeoIP databases: %w", err)
+		}
+	}
+
 	// Router factory
 
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, observabilityMgr, pluginBuilder, dialerManager)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, observabilityMgr, pluginBuilder, dialerManager, geoIPDatabase)
 
 	// Watcher
 
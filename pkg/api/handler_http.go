@@ -7,10 +7,12 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cache"
 	"github.com/traefik/traefik/v3/pkg/tls"
 )
 
@@ -34,19 +36,21 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus   map[string]string    `json:"serverStatus,omitempty"`
+	EjectedServers map[string]time.Time `json:"ejectedServers,omitempty"`
+	Name           string               `json:"name,omitempty"`
+	Provider       string               `json:"provider,omitempty"`
+	Type           string               `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
 	return serviceRepresentation{
-		ServiceInfo:  si,
-		Name:         name,
-		Provider:     getProviderName(name),
-		ServerStatus: si.GetAllStatus(),
-		Type:         strings.ToLower(extractType(si.Service)),
+		ServiceInfo:    si,
+		Name:           name,
+		Provider:       getProviderName(name),
+		ServerStatus:   si.GetAllStatus(),
+		EjectedServers: si.GetAllEjections(),
+		Type:           strings.ToLower(extractType(si.Service)),
 	}
 }
 
@@ -237,6 +241,41 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) purgeMiddlewareCache(rw http.ResponseWriter, request *http.Request) {
+	scapedMiddlewareID := mux.Vars(request)["middlewareID"]
+
+	middlewareID, err := url.PathUnescape(scapedMiddlewareID)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("unable to decode middlewareID %q: %s", scapedMiddlewareID, err), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok || middleware.Cache == nil {
+		writeError(rw, fmt.Sprintf("cache middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	purgeURL := request.URL.Query().Get("url")
+	if purgeURL != "" {
+		u, err := url.Parse(purgeURL)
+		if err != nil || !u.IsAbs() || u.Host == "" {
+			writeError(rw, fmt.Sprintf("invalid URL %q: must be absolute", purgeURL), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := cache.Purge(middlewareID, purgeURL); err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 func keepRouter(name string, item *runtime.RouterInfo, criterion *searchCriterion) bool {
 	if criterion == nil {
 		return true
@@ -17,6 +17,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cache"
 )
 
 func Bool(v bool) *bool { return &v }
@@ -1048,6 +1049,106 @@ func TestHandler_HTTP(t *testing.T) {
 	}
 }
 
+func TestHandler_purgeMiddlewareCache(t *testing.T) {
+	var calls int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	cacheHandler, err := cache.New(context.Background(), next, dynamic.Cache{}, "cache@myprovider")
+	require.NoError(t, err)
+
+	rtConf := &runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"cache@myprovider": {
+				Middleware: &dynamic.Middleware{
+					Cache: &dynamic.Cache{},
+				},
+			},
+			"auth@myprovider": {
+				Middleware: &dynamic.Middleware{
+					BasicAuth: &dynamic.BasicAuth{
+						Users: []string{"admin:admin"},
+					},
+				},
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc          string
+		path          string
+		expectedCode  int
+		expectedCalls int
+	}{
+		{
+			desc:          "middleware does not exist",
+			path:          "/api/http/middlewares/foo@myprovider/cache",
+			expectedCode:  http.StatusNotFound,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "not a cache middleware",
+			path:          "/api/http/middlewares/auth@myprovider/cache",
+			expectedCode:  http.StatusNotFound,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "relative URL",
+			path:          "/api/http/middlewares/cache@myprovider/cache?url=" + url.QueryEscape("/foo"),
+			expectedCode:  http.StatusBadRequest,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "purge another URL",
+			path:          "/api/http/middlewares/cache@myprovider/cache?url=" + url.QueryEscape("http://example.com/bar"),
+			expectedCode:  http.StatusNoContent,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "purge URL",
+			path:          "/api/http/middlewares/cache@myprovider/cache?url=" + url.QueryEscape("http://example.com/foo"),
+			expectedCode:  http.StatusNoContent,
+			expectedCalls: 2,
+		},
+		{
+			desc:          "purge all",
+			path:          "/api/http/middlewares/cache@myprovider/cache",
+			expectedCode:  http.StatusNoContent,
+			expectedCalls: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		require.NoError(t, cache.Purge("cache@myprovider", ""))
+		calls = 0
+
+		// Stores the response, and serves it again.
+		for range 2 {
+			cacheHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil))
+		}
+
+		req, err := http.NewRequest(http.MethodDelete, server.URL+test.path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, test.expectedCode, resp.StatusCode, test.desc)
+
+		cacheHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil))
+
+		assert.Equal(t, test.expectedCalls, calls, test.desc)
+	}
+}
+
 func generateHTTPRouters(nbRouters int) map[string]*runtime.RouterInfo {
 	routers := make(map[string]*runtime.RouterInfo, nbRouters)
 	for i := 0; i < nbRouters; i++ {
@@ -15,6 +15,22 @@ const (
 	// DefaultHealthCheckTimeout is the default value for the ServerHealthCheck timeout.
 	DefaultHealthCheckTimeout = ptypes.Duration(5 * time.Second)
 
+	// DefaultPassiveHealthCheckConsecutiveFailures is the default value for the PassiveServerHealthCheck consecutiveFailures.
+	DefaultPassiveHealthCheckConsecutiveFailures = 5
+	// DefaultPassiveHealthCheckBaseEjectionTime is the default value for the PassiveServerHealthCheck baseEjectionTime.
+	DefaultPassiveHealthCheckBaseEjectionTime = ptypes.Duration(30 * time.Second)
+	// DefaultPassiveHealthCheckMaxEjectionTime is the default value for the PassiveServerHealthCheck maxEjectionTime.
+	DefaultPassiveHealthCheckMaxEjectionTime = ptypes.Duration(300 * time.Second)
+	// DefaultPassiveHealthCheckMaxEjectionPercent is the default value for the PassiveServerHealthCheck maxEjectionPercent.
+	DefaultPassiveHealthCheckMaxEjectionPercent = 50
+
+	// DefaultSlowStartDuration is the default value for the SlowStart duration.
+	DefaultSlowStartDuration = ptypes.Duration(30 * time.Second)
+	// DefaultSlowStartAggression is the default value for the SlowStart aggression.
+	DefaultSlowStartAggression = 1.0
+	// DefaultSlowStartMinWeightPercent is the default value for the SlowStart minWeightPercent.
+	DefaultSlowStartMinWeightPercent = 10
+
 	// DefaultPassHostHeader is the default value for the ServersLoadBalancer passHostHeader.
 	DefaultPassHostHeader = true
 
@@ -22,6 +38,23 @@ const (
 	DefaultFlushInterval = ptypes.Duration(100 * time.Millisecond)
 )
 
+// BalancerStrategy is the load-balancing strategy of a ServersLoadBalancer.
+type BalancerStrategy string
+
+const (
+	// BalancerStrategyWRR is the weighted round-robin strategy.
+	BalancerStrategyWRR BalancerStrategy = "wrr"
+	// BalancerStrategyLeastConn is the least-connections strategy,
+	// which selects the server with the fewest in-flight requests relative to its weight.
+	BalancerStrategyLeastConn BalancerStrategy = "leastconn"
+	// BalancerStrategyP2C is the power of two random choices strategy,
+	// which selects, between two randomly drawn servers, the one with the fewest in-flight requests relative to its weight.
+	BalancerStrategyP2C BalancerStrategy = "p2c"
+	// BalancerStrategyConsistentHash is the consistent hashing strategy,
+	// which selects the server by hashing a key extracted from the request onto a ring of servers.
+	BalancerStrategyConsistentHash BalancerStrategy = "chash"
+)
+
 // +k8s:deepcopy-gen=true
 
 // HTTPConfiguration contains all the HTTP configuration parameters.
@@ -139,9 +172,32 @@ func (w *WRRService) SetDefaults() {
 // +k8s:deepcopy-gen=true
 
 // Sticky holds the sticky configuration.
+// Cookie, Header and ClientIP are mutually exclusive.
 type Sticky struct {
 	// Cookie defines the sticky cookie configuration.
 	Cookie *Cookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Header defines the sticky configuration based on the value of a request header.
+	Header *StickyHeader `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	// ClientIP defines the sticky configuration based on the client IP.
+	ClientIP *StickyClientIP `json:"clientIP,omitempty" toml:"clientIP,omitempty" yaml:"clientIP,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StickyHeader holds the sticky configuration based on the value of a request header.
+// The requests carrying the same header value are forwarded to the same server.
+type StickyHeader struct {
+	// Name defines the name of the request header.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StickyClientIP holds the sticky configuration based on the client IP.
+// The requests coming from the same client IP are forwarded to the same server.
+type StickyClientIP struct {
+	// IPStrategy defines how the client IP is retrieved from the request.
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -161,6 +217,11 @@ type Cookie struct {
 	// When set to a negative number, the cookie expires immediately.
 	// When set to zero, the cookie never expires.
 	MaxAge int `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+	// Secret defines the secret used to sign the cookie value,
+	// so that clients cannot forge a cookie to target a specific server.
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty" loggable:"false"`
+	// Encrypt defines whether the cookie value is encrypted with the secret, instead of being signed.
+	Encrypt bool `json:"encrypt,omitempty" toml:"encrypt,omitempty" yaml:"encrypt,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -169,11 +230,24 @@ type Cookie struct {
 type ServersLoadBalancer struct {
 	Sticky  *Sticky  `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Servers []Server `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// Strategy defines the load-balancing strategy between the servers.
+	// It can be one of wrr (weighted round-robin), leastconn (least connections), p2c (power of two random choices),
+	// or chash (consistent hashing).
+	// Default: wrr
+	Strategy BalancerStrategy `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// ConsistentHash defines the request key hashed by the chash strategy.
+	ConsistentHash *ConsistentHash `json:"consistentHash,omitempty" toml:"consistentHash,omitempty" yaml:"consistentHash,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	// HealthCheck enables regular active checks of the responsiveness of the
 	// children servers of this load-balancer. To propagate status changes (e.g. all
 	// servers of this service are down) upwards, HealthCheck must also be enabled on
 	// the parent(s) of this service.
-	HealthCheck        *ServerHealthCheck  `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	HealthCheck *ServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	// PassiveHealthCheck enables the ejection of the children servers of this load-balancer,
+	// based on the responses to the forwarded requests.
+	PassiveHealthCheck *PassiveServerHealthCheck `json:"passiveHealthCheck,omitempty" toml:"passiveHealthCheck,omitempty" yaml:"passiveHealthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// SlowStart enables the progressive ramp-up of the weight of the new, or recovered, servers.
+	// It is only supported by the wrr strategy.
+	SlowStart          *SlowStart          `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
@@ -207,6 +281,22 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ConsistentHash holds the consistent hashing configuration.
+// It defines what part of the request is hashed to select a server.
+// If none are set, the default is to use the request's remote address field (as an ipStrategy).
+// All fields are mutually exclusive.
+type ConsistentHash struct {
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" export:"true"`
+	// RequestHeaderName defines the name of the request header used as the hash key.
+	RequestHeaderName string `json:"requestHeaderName,omitempty" toml:"requestHeaderName,omitempty" yaml:"requestHeaderName,omitempty" export:"true"`
+	// CookieName defines the name of the request cookie used as the hash key.
+	CookieName string `json:"cookieName,omitempty" toml:"cookieName,omitempty" yaml:"cookieName,omitempty" export:"true"`
+	// QueryParameterName defines the name of the request query parameter used as the hash key.
+	QueryParameterName string `json:"queryParameterName,omitempty" toml:"queryParameterName,omitempty" yaml:"queryParameterName,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds the response forwarding configuration.
 type ResponseForwarding struct {
 	// FlushInterval defines the interval, in milliseconds, in between flushes to the client while copying the response body.
@@ -228,6 +318,10 @@ func (r *ResponseForwarding) SetDefaults() {
 type Server struct {
 	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
 	Weight *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" label:"weight"`
+	// Zone defines the zone (e.g. availability zone) in which the server runs.
+	Zone string `json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" label:"zone"`
+	// Region defines the region in which the server runs.
+	Region string `json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" label:"region"`
 	Scheme string `json:"-" toml:"-" yaml:"-" file:"-"`
 	Port   string `json:"-" toml:"-" yaml:"-" file:"-"`
 }
@@ -265,6 +359,57 @@ func (h *ServerHealthCheck) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// PassiveServerHealthCheck holds the passive HealthCheck (outlier detection) configuration.
+// A forwarded request fails when the server cannot be reached, when it answers with a 5XX status code,
+// or when its response takes longer than MaxLatency.
+// After ConsecutiveFailures failed requests, the server is ejected from the load-balancer for an ejection time,
+// which starts at BaseEjectionTime and doubles at each consecutive ejection, up to MaxEjectionTime.
+type PassiveServerHealthCheck struct {
+	// ConsecutiveFailures defines the number of consecutive failed requests after which a server is ejected.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty" toml:"consecutiveFailures,omitempty" yaml:"consecutiveFailures,omitempty" export:"true"`
+	// MaxLatency defines the duration after which a response is considered as failed.
+	// Zero means that the latency is not checked.
+	MaxLatency ptypes.Duration `json:"maxLatency,omitempty" toml:"maxLatency,omitempty" yaml:"maxLatency,omitempty" export:"true"`
+	// BaseEjectionTime defines the duration of the first ejection of a server.
+	BaseEjectionTime ptypes.Duration `json:"baseEjectionTime,omitempty" toml:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty" export:"true"`
+	// MaxEjectionTime defines the maximum duration of the ejection of a server.
+	MaxEjectionTime ptypes.Duration `json:"maxEjectionTime,omitempty" toml:"maxEjectionTime,omitempty" yaml:"maxEjectionTime,omitempty" export:"true"`
+	// MaxEjectionPercent defines the maximum percentage of the servers of the load-balancer which can be ejected at the same time.
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty" toml:"maxEjectionPercent,omitempty" yaml:"maxEjectionPercent,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a PassiveServerHealthCheck.
+func (h *PassiveServerHealthCheck) SetDefaults() {
+	h.ConsecutiveFailures = DefaultPassiveHealthCheckConsecutiveFailures
+	h.BaseEjectionTime = DefaultPassiveHealthCheckBaseEjectionTime
+	h.MaxEjectionTime = DefaultPassiveHealthCheckMaxEjectionTime
+	h.MaxEjectionPercent = DefaultPassiveHealthCheckMaxEjectionPercent
+}
+
+// +k8s:deepcopy-gen=true
+
+// SlowStart holds the slow start configuration.
+// During the slow start of a server, its effective weight is weight * max(MinWeightPercent / 100, (elapsed / Duration) ^ (1 / Aggression)),
+// where elapsed is the time since the server was added, or since it recovered.
+type SlowStart struct {
+	// Duration defines the duration of the ramp-up, after which the server gets its full weight.
+	Duration ptypes.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+	// Aggression defines the curve of the ramp-up.
+	// The ramp-up is linear with 1, and the weight rises faster at the start with greater values.
+	Aggression float64 `json:"aggression,omitempty" toml:"aggression,omitempty" yaml:"aggression,omitempty" export:"true"`
+	// MinWeightPercent defines the effective weight, as a percentage of the weight, at the start of the ramp-up.
+	MinWeightPercent int `json:"minWeightPercent,omitempty" toml:"minWeightPercent,omitempty" yaml:"minWeightPercent,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a SlowStart.
+func (s *SlowStart) SetDefaults() {
+	s.Duration = DefaultSlowStartDuration
+	s.Aggression = DefaultSlowStartAggression
+	s.MinWeightPercent = DefaultSlowStartMinWeightPercent
+}
+
+// +k8s:deepcopy-gen=true
+
 // HealthCheck controls healthcheck awareness and propagation at the services level.
 type HealthCheck struct{}
 
@@ -6,6 +6,7 @@ import (
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 // +k8s:deepcopy-gen=true
@@ -21,6 +22,8 @@ type Middleware struct {
 	// Deprecated: please use IPAllowList instead.
 	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
 	IPAllowList       *IPAllowList       `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	IPDenyList        *IPDenyList        `json:"ipDenyList,omitempty" toml:"ipDenyList,omitempty" yaml:"ipDenyList,omitempty" export:"true"`
+	GeoIP             *GeoIP             `json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
 	Errors            *ErrorPage         `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty" export:"true"`
 	RateLimit         *RateLimit         `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
@@ -29,10 +32,17 @@ type Middleware struct {
 	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty" export:"true"`
 	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty" export:"true"`
 	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty" export:"true"`
+	OIDC              *OIDC              `json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty" export:"true"`
 	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
+	BandwidthLimit    *BandwidthLimit    `json:"bandwidthLimit,omitempty" toml:"bandwidthLimit,omitempty" yaml:"bandwidthLimit,omitempty" export:"true"`
 	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
+	Cache             *Cache             `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Decompress        *Decompress        `json:"decompress,omitempty" toml:"decompress,omitempty" yaml:"decompress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	BodyRewrite       *BodyRewrite       `json:"bodyRewrite,omitempty" toml:"bodyRewrite,omitempty" yaml:"bodyRewrite,omitempty" export:"true"`
+	WAF               *WAF               `json:"waf,omitempty" toml:"waf,omitempty" yaml:"waf,omitempty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
@@ -98,6 +108,37 @@ type BasicAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// BodyRewrite holds the body rewrite middleware configuration.
+// This middleware rewrites the bodies of the responses, by replacing literal values or regular expression matches.
+type BodyRewrite struct {
+	// ContentTypes defines the media types of the responses to rewrite, e.g. text/html or text/*.
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+	// Rewrites defines the replacements to apply, in order.
+	Rewrites []BodyRewriteRule `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty" export:"true"`
+	// MaxBodyBytes defines the maximum size (in bytes) of a response body kept in memory,
+	// to apply the regular expressions or to rewrite a compressed body.
+	// The larger responses are forwarded unmodified.
+	// Default: 1048576 (1Mi).
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty" toml:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyRewriteRule holds a body rewrite replacement.
+type BodyRewriteRule struct {
+	// Literal defines the value to replace.
+	// Mutually exclusive with the Regex option.
+	Literal string `json:"literal,omitempty" toml:"literal,omitempty" yaml:"literal,omitempty" export:"true"`
+	// Regex defines the regular expression to replace.
+	// Mutually exclusive with the Literal option.
+	Regex string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	// Replacement defines the replacement value.
+	// With the Regex option, it can refer to the submatches, e.g. $1.
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Buffering holds the buffering middleware configuration.
 // This middleware retries or limits the size of requests that can be forwarded to backends.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/buffering/#maxrequestbodybytes
@@ -124,6 +165,32 @@ type Buffering struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the cache middleware configuration.
+// This middleware stores the responses of the service, and serves them again to the subsequent requests,
+// following the HTTP caching rules (RFC 9111).
+type Cache struct {
+	// MaxSize defines the maximum size (in bytes) of the stored responses.
+	// When the cache is full, the least recently used responses are evicted.
+	// Default: 67108864 (64Mi).
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+	// MaxResponseBodyBytes defines the maximum body size (in bytes) of a response to be stored.
+	// Default: 1048576 (1Mi).
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	// Disk defines the storage of the responses on the local disk, instead of in memory.
+	Disk *CacheDisk `json:"disk,omitempty" toml:"disk,omitempty" yaml:"disk,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CacheDisk holds the disk storage configuration of the cache middleware.
+type CacheDisk struct {
+	// Path defines the directory where the responses are stored.
+	// It is emptied when the cache is created.
+	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Chain holds the chain middleware configuration.
 // This middleware enables to define reusable combinations of other pieces of middleware.
 type Chain struct {
@@ -160,7 +227,7 @@ func (c *CircuitBreaker) SetDefaults() {
 // +k8s:deepcopy-gen=true
 
 // Compress holds the compress middleware configuration.
-// This middleware compresses responses before sending them to the client, using gzip compression.
+// This middleware compresses responses before sending them to the client, using gzip, Brotli, or Zstandard compression.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/compress/
 type Compress struct {
 	// ExcludedContentTypes defines the list of content types to compare the Content-Type header of the incoming requests and responses before compressing.
@@ -171,6 +238,31 @@ type Compress struct {
 	// MinResponseBodyBytes defines the minimum amount of bytes a response body must have to be compressed.
 	// Default: 1024.
 	MinResponseBodyBytes int `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	// Encodings defines the list of supported compression encodings, by order of preference.
+	// The first encoding of the list accepted by the client is used.
+	// Supported values are br, zstd, and gzip.
+	// Default: br, zstd, gzip.
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+	// GzipLevel defines the gzip compression level, from 1 (best speed) to 9 (best compression).
+	// Default: 5.
+	GzipLevel int `json:"gzipLevel,omitempty" toml:"gzipLevel,omitempty" yaml:"gzipLevel,omitempty" export:"true"`
+	// BrotliLevel defines the Brotli compression level, from 1 (best speed) to 11 (best compression).
+	// Default: 6.
+	BrotliLevel int `json:"brotliLevel,omitempty" toml:"brotliLevel,omitempty" yaml:"brotliLevel,omitempty" export:"true"`
+	// ZstdLevel defines the Zstandard compression level, from 1 (best speed) to 22 (best compression).
+	// Default: 3.
+	ZstdLevel int `json:"zstdLevel,omitempty" toml:"zstdLevel,omitempty" yaml:"zstdLevel,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Decompress holds the decompress middleware configuration.
+// This middleware decompresses the request bodies encoded with gzip, Brotli, or Zstandard before forwarding them to the service.
+type Decompress struct {
+	// MaxDecompressedBodyBytes defines the maximum size (in bytes) of a decompressed request body.
+	// The requests with a larger decompressed body are rejected with a 413 status code.
+	// Default: 10485760 (10Mi).
+	MaxDecompressedBodyBytes int64 `json:"maxDecompressedBodyBytes,omitempty" toml:"maxDecompressedBodyBytes,omitempty" yaml:"maxDecompressedBodyBytes,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -238,6 +330,125 @@ type ForwardAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JWT middleware configuration.
+// This middleware validates the JSON Web Token (JWT) carried by the Authorization header of the requests.
+type JWT struct {
+	// PublicKeys defines the PEM-encoded public keys, or the paths to the files containing them, used to verify the token signatures.
+	PublicKeys []string `json:"publicKeys,omitempty" toml:"publicKeys,omitempty" yaml:"publicKeys,omitempty"`
+	// Secret defines the secret used to verify the HMAC token signatures.
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty" loggable:"false"`
+	// JWKSURL defines the URL of the JSON Web Key Set used to verify the token signatures.
+	JWKSURL string `json:"jwksUrl,omitempty" toml:"jwksUrl,omitempty" yaml:"jwksUrl,omitempty"`
+	// JWKSRefreshInterval defines the interval after which the cached JSON Web Key Set is refreshed in the background.
+	// Default: 15m.
+	JWKSRefreshInterval ptypes.Duration `json:"jwksRefreshInterval,omitempty" toml:"jwksRefreshInterval,omitempty" yaml:"jwksRefreshInterval,omitempty" export:"true"`
+	// Issuer defines the expected issuer (iss claim) of the tokens.
+	Issuer string `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audiences defines the accepted audiences (aud claim) of the tokens.
+	// The tokens must be intended for at least one of them.
+	Audiences []string `json:"audiences,omitempty" toml:"audiences,omitempty" yaml:"audiences,omitempty"`
+	// ClockSkew defines the tolerance applied when checking the exp and nbf claims of the tokens.
+	ClockSkew ptypes.Duration `json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty" export:"true"`
+	// ClaimRules defines the rules that the claims of the tokens must satisfy.
+	ClaimRules []JWTClaimRule `json:"claimRules,omitempty" toml:"claimRules,omitempty" yaml:"claimRules,omitempty" export:"true"`
+	// ForwardHeaders defines the headers to set on the forwarded request, keyed by header name, from the claims of the token.
+	// It replaces any existing conflicting headers.
+	ForwardHeaders map[string]string `json:"forwardHeaders,omitempty" toml:"forwardHeaders,omitempty" yaml:"forwardHeaders,omitempty" export:"true"`
+	// RemoveHeader defines whether to remove the authorization header before forwarding the request to the backend.
+	RemoveHeader bool `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a JWT.
+func (j *JWT) SetDefaults() {
+	j.JWKSRefreshInterval = ptypes.Duration(15 * time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWTClaimRule holds a rule that a claim of the JWT tokens must satisfy.
+type JWTClaimRule struct {
+	// Claim defines the name of the claim.
+	// The nested claims can be referenced with a dot-separated path (e.g. realm_access.roles).
+	Claim string `json:"claim,omitempty" toml:"claim,omitempty" yaml:"claim,omitempty" export:"true"`
+	// Values defines the accepted values of the claim.
+	// The claim, or one of its elements if it is an array, must equal one of them.
+	// If empty, the claim must only be present.
+	Values []string `json:"values,omitempty" toml:"values,omitempty" yaml:"values,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect middleware configuration.
+// This middleware authenticates the users with an OpenID Connect provider, using the authorization code flow with PKCE.
+type OIDC struct {
+	// Issuer defines the URL of the OpenID Connect provider, used to discover its endpoints.
+	Issuer string `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// ClientID defines the client identifier registered with the provider.
+	ClientID string `json:"clientId,omitempty" toml:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// ClientSecret defines the client secret registered with the provider.
+	ClientSecret string `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty" loggable:"false"`
+	// Scopes defines the scopes requested to the provider.
+	// Default: openid, profile, email.
+	Scopes []string `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+	// RedirectPath defines the path where the provider redirects the users after their authentication.
+	// Default: /oauth2/callback.
+	RedirectPath string `json:"redirectPath,omitempty" toml:"redirectPath,omitempty" yaml:"redirectPath,omitempty" export:"true"`
+	// LogoutPath defines the path which logs the users out.
+	// Default: /oauth2/logout.
+	LogoutPath string `json:"logoutPath,omitempty" toml:"logoutPath,omitempty" yaml:"logoutPath,omitempty" export:"true"`
+	// PostLogoutRedirectURL defines the URL where the users are redirected after their logout.
+	PostLogoutRedirectURL string `json:"postLogoutRedirectUrl,omitempty" toml:"postLogoutRedirectUrl,omitempty" yaml:"postLogoutRedirectUrl,omitempty"`
+	// Session defines the configuration of the session cookie.
+	Session *OIDCSession `json:"session,omitempty" toml:"session,omitempty" yaml:"session,omitempty" export:"true"`
+	// ForwardHeaders defines the headers to set on the forwarded request, keyed by header name, from the claims of the ID token.
+	// It replaces any existing conflicting headers.
+	ForwardHeaders map[string]string `json:"forwardHeaders,omitempty" toml:"forwardHeaders,omitempty" yaml:"forwardHeaders,omitempty" export:"true"`
+	// ForwardAccessToken defines whether to forward the access token in the Authorization header of the request.
+	ForwardAccessToken bool `json:"forwardAccessToken,omitempty" toml:"forwardAccessToken,omitempty" yaml:"forwardAccessToken,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for an OIDC.
+func (o *OIDC) SetDefaults() {
+	o.Scopes = []string{"openid", "profile", "email"}
+	o.RedirectPath = "/oauth2/callback"
+	o.LogoutPath = "/oauth2/logout"
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDCSession holds the OIDC session cookie configuration.
+// The session is stored encrypted in the cookie, which is split in several cookies when it is too large.
+type OIDCSession struct {
+	// Secret defines the secret used to encrypt the session cookie.
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty" loggable:"false"`
+	// Name defines the session cookie name.
+	// Default: traefik_oidc.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Path defines the session cookie path.
+	// Default: /.
+	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	// Domain defines the session cookie domain.
+	Domain string `json:"domain,omitempty" toml:"domain,omitempty" yaml:"domain,omitempty" export:"true"`
+	// Secure defines whether the session cookie can only be transmitted over an encrypted connection (i.e. HTTPS).
+	Secure bool `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty" export:"true"`
+	// SameSite defines the same site policy of the session cookie.
+	// More info: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
+	// Default: lax.
+	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty" export:"true"`
+	// MaxAge indicates the number of seconds until the session cookie expires.
+	// When set to zero, the cookie expires when the browser is closed.
+	MaxAge int `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for an OIDCSession.
+func (s *OIDCSession) SetDefaults() {
+	s.Name = "traefik_oidc"
+	s.Path = "/"
+	s.SameSite = "lax"
+}
+
+// +k8s:deepcopy-gen=true
+
 // ClientTLS holds TLS specific configurations as client
 // CA, Cert and Key can be either path or file contents.
 // TODO: remove this struct when CAOptional option will be removed.
@@ -445,6 +656,87 @@ type IPAllowList struct {
 
 // +k8s:deepcopy-gen=true
 
+// IPDenyList holds the IP denylist middleware configuration.
+// This middleware refuses requests based on the client IP, against ranges which can be loaded from files and URLs.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/ipdenylist/
+type IPDenyList struct {
+	// SourceRange defines the set of denied IPs (or ranges of denied IPs by using CIDR notation).
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	// SourceRangeFiles defines the paths to files listing denied IPs (or ranges of denied IPs by using CIDR notation), one per line.
+	SourceRangeFiles []string `json:"sourceRangeFiles,omitempty" toml:"sourceRangeFiles,omitempty" yaml:"sourceRangeFiles,omitempty"`
+	// SourceRangeURLs defines the HTTP(S) URLs of lists of denied IPs (or ranges of denied IPs by using CIDR notation), one per line.
+	SourceRangeURLs []string `json:"sourceRangeURLs,omitempty" toml:"sourceRangeURLs,omitempty" yaml:"sourceRangeURLs,omitempty"`
+	// RefreshInterval defines the interval at which the lists are reloaded from the files and URLs.
+	// Default: 1h.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+	IPStrategy      *IPStrategy     `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// RejectStatusCode defines the HTTP status code used for refused requests.
+	// If not set, the default is 403 (Forbidden).
+	RejectStatusCode int `json:"rejectStatusCode,omitempty" toml:"rejectStatusCode,omitempty" yaml:"rejectStatusCode,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values on an IPDenyList.
+func (i *IPDenyList) SetDefaults() {
+	i.RefreshInterval = ptypes.Duration(time.Hour)
+}
+
+// +k8s:deepcopy-gen=true
+
+// GeoIP holds the GeoIP middleware configuration.
+// This middleware allows or denies requests based on the country of the client IP,
+// and forwards the country and the autonomous system number of the client IP in request headers.
+// The client IP is looked up in the GeoIP databases of the static configuration.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/geoip/
+type GeoIP struct {
+	// AllowedCountries defines the ISO 3166-1 alpha-2 codes of the allowed countries.
+	// If set, the requests from the other countries, and from unknown countries, are refused.
+	AllowedCountries []string `json:"allowedCountries,omitempty" toml:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty"`
+	// DeniedCountries defines the ISO 3166-1 alpha-2 codes of the refused countries.
+	DeniedCountries []string `json:"deniedCountries,omitempty" toml:"deniedCountries,omitempty" yaml:"deniedCountries,omitempty"`
+	// CountryHeader defines the name of the request header forwarding the country of the client IP.
+	// Default: X-Client-Country.
+	CountryHeader string `json:"countryHeader,omitempty" toml:"countryHeader,omitempty" yaml:"countryHeader,omitempty" export:"true"`
+	// ASNHeader defines the name of the request header forwarding the autonomous system number of the client IP.
+	// Default: X-Client-ASN.
+	ASNHeader  string      `json:"asnHeader,omitempty" toml:"asnHeader,omitempty" yaml:"asnHeader,omitempty" export:"true"`
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// RejectStatusCode defines the HTTP status code used for refused requests.
+	// If not set, the default is 403 (Forbidden).
+	RejectStatusCode int `json:"rejectStatusCode,omitempty" toml:"rejectStatusCode,omitempty" yaml:"rejectStatusCode,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values on a GeoIP.
+func (g *GeoIP) SetDefaults() {
+	g.CountryHeader = "X-Client-Country"
+	g.ASNHeader = "X-Client-ASN"
+}
+
+// +k8s:deepcopy-gen=true
+
+// WAF holds the web application firewall middleware configuration.
+// This middleware evaluates SecLang rules, e.g. the OWASP Core Rule Set, on the request headers, query, and body.
+type WAF struct {
+	// Rules defines the SecLang directives, e.g. SecRule or SecAction directives, one per line.
+	Rules string `json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty"`
+	// RulesFiles defines the paths to external files containing SecLang directives, which are loaded before the Rules option.
+	RulesFiles []string `json:"rulesFiles,omitempty" toml:"rulesFiles,omitempty" yaml:"rulesFiles,omitempty"`
+	// DetectionOnly defines whether the requests matching a disruptive rule are only logged, instead of being blocked.
+	DetectionOnly bool `json:"detectionOnly,omitempty" toml:"detectionOnly,omitempty" yaml:"detectionOnly,omitempty" export:"true"`
+	// MaxRequestBodyBytes defines the maximum size (in bytes) of a request body inspected by the rules.
+	// The requests with a larger body are rejected with a 413 (Request Entity Too Large) response, unless in detection only mode.
+	// Default: 1048576 (1Mi).
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+
+	// NoLocalFiles defines whether the rules are prevented from reading the files of the local filesystem.
+	// It is set by the Kubernetes CRD provider, as the rules are then written by the authors of the resources.
+	NoLocalFiles bool `json:"-" toml:"-" yaml:"-" label:"-" file:"-" kv:"-"`
+	// DataFiles defines the contents of the files read by the @pmFromFile operators, keyed by file name,
+	// when the rules cannot read the files of the local filesystem.
+	DataFiles map[string]string `json:"-" toml:"-" yaml:"-" label:"-" file:"-" kv:"-"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // InFlightReq holds the in-flight request middleware configuration.
 // This middleware limits the number of requests being processed and served concurrently.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/inflightreq/
@@ -461,6 +753,30 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// BandwidthLimit holds the bandwidth limit middleware configuration.
+// This middleware throttles the response bodies, with a token bucket for each response, and one for each source.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/bandwidthlimit/
+type BandwidthLimit struct {
+	// Rate is the maximum throughput, in bytes/s, of a response body.
+	// It defaults to 0, which means no limit per response.
+	Rate int64 `json:"rate,omitempty" toml:"rate,omitempty" yaml:"rate,omitempty" export:"true"`
+	// Burst is the maximum number of bytes of a response body written at once.
+	// It defaults to Rate.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// SourceRate is the maximum throughput, in bytes/s, shared by the response bodies to the same source.
+	// It defaults to 0, which means no limit per source.
+	SourceRate int64 `json:"sourceRate,omitempty" toml:"sourceRate,omitempty" yaml:"sourceRate,omitempty" export:"true"`
+	// SourceBurst is the maximum number of bytes written at once to the same source.
+	// It defaults to SourceRate.
+	SourceBurst int64 `json:"sourceBurst,omitempty" toml:"sourceBurst,omitempty" yaml:"sourceBurst,omitempty" export:"true"`
+	// SourceCriterion defines what criterion is used to group requests as originating from a common source.
+	// If several strategies are defined at the same time, an error will be raised.
+	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
+	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the pass TLS client cert middleware configuration.
 // This middleware adds the selected data from the passed client TLS certificate to a header.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/passtlsclientcert/
@@ -507,6 +823,18 @@ type RateLimit struct {
 	// If several strategies are defined at the same time, an error will be raised.
 	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+
+	// Redis defines the Redis server storing the token buckets, to enforce the rate limit across several Traefik instances.
+	// If not set, the token buckets are stored in memory.
+	Redis *Redis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+
+	// SendHeaders defines whether to add the RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers to the responses,
+	// to report the state of the token bucket of the source.
+	SendHeaders bool `json:"sendHeaders,omitempty" toml:"sendHeaders,omitempty" yaml:"sendHeaders,omitempty" export:"true"`
+
+	// Tiers defines additional rate limits, each with its own source criterion and rate, enforced along with the one above.
+	// A request is rejected as soon as one of the rate limits is exceeded.
+	Tiers []RateLimitTier `json:"tiers,omitempty" toml:"tiers,omitempty" yaml:"tiers,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -517,6 +845,47 @@ func (r *RateLimit) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// RateLimitTier holds a rate limit enforced by a RateLimit middleware along with its main rate limit.
+type RateLimitTier struct {
+	// Name is the name of the tier, reported in the X-RateLimit-Tier header when the tier rejects a request.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Average is the maximum rate, by default in requests/s, allowed for the given source.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of requests allowed to arrive in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// SourceCriterion defines what criterion is used to group requests as originating from a common source.
+	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
+	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Redis holds the Redis configuration.
+type Redis struct {
+	// Endpoints defines the endpoints of the Redis server(s).
+	// Default: 127.0.0.1:6379.
+	Endpoints []string `json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// TLS defines the configuration used to secure the connection to Redis.
+	TLS *types.ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	// Username defines the username used to authenticate with Redis.
+	Username string `json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty" loggable:"false"`
+	// Password defines the password used to authenticate with Redis.
+	Password string `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty" loggable:"false"`
+	// DB defines the database selected after connecting to Redis.
+	DB int `json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Redis.
+func (r *Redis) SetDefaults() {
+	r.Endpoints = []string{"127.0.0.1:6379"}
+}
+
+// +k8s:deepcopy-gen=true
+
 // RedirectRegex holds the redirect regex middleware configuration.
 // This middleware redirects a request using regex matching and replacement.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/redirectregex/#regex
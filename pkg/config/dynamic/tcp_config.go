@@ -9,6 +9,9 @@ import (
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
+// DefaultTCPStickyTTL is the default value for the TCPSticky TTL.
+const DefaultTCPStickyTTL = ptypes.Duration(time.Hour)
+
 // +k8s:deepcopy-gen=true
 
 // TCPConfiguration contains all the TCP configuration parameters.
@@ -40,6 +43,14 @@ type TCPService struct {
 // TCPWeightedRoundRobin is a weighted round robin tcp load-balancer of services.
 type TCPWeightedRoundRobin struct {
 	Services []TCPWRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	// HealthCheck enables automatic self-healthcheck for this service, i.e.
+	// whenever one of its children is reported as down, this service becomes aware of it,
+	// and takes it into account (i.e. it ignores the down child) when running the
+	// load-balancing algorithm. In addition, if the parent of this service also has
+	// HealthCheck enabled, this service reports to its parent any status change.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Sticky binds each client IP to the child service which handled its previous connection.
+	Sticky *TCPSticky `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -86,6 +97,13 @@ type TCPServersLoadBalancer struct {
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 	ServersTransport string         `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// HealthCheck enables regular active checks of the responsiveness of the
+	// children servers of this load-balancer. To propagate status changes (e.g. all
+	// servers of this service are down) upwards, HealthCheck must also be enabled on
+	// the parent(s) of this service.
+	HealthCheck *TCPServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	// Sticky binds each client IP to the server which handled its previous connection.
+	Sticky *TCPSticky `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 
 	// TerminationDelay, corresponds to the deadline that the proxy sets, after one
 	// of its connected peers indicates it has closed the writing capability of its
@@ -124,11 +142,52 @@ type TCPServer struct {
 
 // +k8s:deepcopy-gen=true
 
+// TCPServerHealthCheck holds the TCP HealthCheck configuration.
+// A server is considered healthy when a connection can be established to it
+// (using the same transport, including TLS, as the proxied connections),
+// and, if configured, when its response to the Send payload starts with the Expect payload.
+type TCPServerHealthCheck struct {
+	// Port defines the port used to connect to the servers, instead of the port of their address.
+	Port int `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty" export:"true"`
+	// Send defines the payload sent to the server once connected.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty" export:"true"`
+	// Expect defines the payload the server response must start with.
+	Expect   string          `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty" export:"true"`
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout  ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPServerHealthCheck.
+func (h *TCPServerHealthCheck) SetDefaults() {
+	h.Interval = DefaultHealthCheckInterval
+	h.Timeout = DefaultHealthCheckTimeout
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPSticky holds the TCP sticky configuration.
+// A client IP is bound to the server which handled its previous connection,
+// for as long as the server is available and the binding is not expired.
+type TCPSticky struct {
+	// TTL defines how long a client IP stays bound to a server after its last connection.
+	TTL ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPSticky.
+func (s *TCPSticky) SetDefaults() {
+	s.TTL = DefaultTCPStickyTTL
+}
+
+// +k8s:deepcopy-gen=true
+
 // ProxyProtocol holds the PROXY Protocol configuration.
 // More info: https://doc.traefik.io/traefik/v3.0/routing/services/#proxy-protocol
 type ProxyProtocol struct {
 	// Version defines the PROXY Protocol version to use.
 	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	// TLVs defines the TLVs to send with the PROXY Protocol header.
+	// It requires the version 2 of the PROXY Protocol.
+	TLVs *ProxyProtocolTLVs `json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // SetDefaults Default values for a ProxyProtocol.
@@ -138,6 +197,26 @@ func (p *ProxyProtocol) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ProxyProtocolTLVs holds the PROXY Protocol version 2 TLVs configuration.
+// The TLVs describe the TLS connection terminated by Traefik, and are not sent for the other connections.
+type ProxyProtocolTLVs struct {
+	// Authority sends the server name (SNI) requested by the client, in a PP2_TYPE_AUTHORITY TLV.
+	Authority bool `json:"authority,omitempty" toml:"authority,omitempty" yaml:"authority,omitempty" export:"true"`
+	// ALPN sends the application protocol negotiated with the client, in a PP2_TYPE_ALPN TLV.
+	ALPN bool `json:"alpn,omitempty" toml:"alpn,omitempty" yaml:"alpn,omitempty" export:"true"`
+	// SSL sends the TLS version, the cipher, and the client certificate details, in a PP2_TYPE_SSL TLV.
+	SSL bool `json:"ssl,omitempty" toml:"ssl,omitempty" yaml:"ssl,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a ProxyProtocolTLVs.
+func (p *ProxyProtocolTLVs) SetDefaults() {
+	p.Authority = true
+	p.ALPN = true
+	p.SSL = true
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServersTransport options to configure communication between Traefik and the servers.
 type TCPServersTransport struct {
 	DialKeepAlive ptypes.Duration `description:"Defines the interval between keep-alive probes for an active network connection. If zero, keep-alive probes are sent with a default value (currently 15 seconds), if supported by the protocol and operating system. Network protocols or operating systems that do not support keep-alives ignore this field. If negative, keep-alive probes are disabled" json:"dialKeepAlive,omitempty" toml:"dialKeepAlive,omitempty" yaml:"dialKeepAlive,omitempty" export:"true"`
@@ -1,13 +1,22 @@
 package dynamic
 
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPMiddleware holds the TCPMiddleware configuration.
 type TCPMiddleware struct {
 	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
 	// Deprecated: please use IPAllowList instead.
-	IPWhiteList *TCPIPWhiteList `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
-	IPAllowList *TCPIPAllowList `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	IPWhiteList    *TCPIPWhiteList    `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	IPAllowList    *TCPIPAllowList    `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	IPDenyList     *TCPIPDenyList     `json:"ipDenyList,omitempty" toml:"ipDenyList,omitempty" yaml:"ipDenyList,omitempty" export:"true"`
+	RateLimit      *TCPRateLimit      `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	BandwidthLimit *TCPBandwidthLimit `json:"bandwidthLimit,omitempty" toml:"bandwidthLimit,omitempty" yaml:"bandwidthLimit,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -24,6 +33,54 @@ type TCPInFlightConn struct {
 
 // +k8s:deepcopy-gen=true
 
+// TCPRateLimit holds the TCP rate limit middleware configuration.
+// This middleware limits the rate of new connections for one IP.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ratelimit/
+type TCPRateLimit struct {
+	// Average is the maximum rate, by default in connections/s, allowed for the given source.
+	// It must be greater than zero.
+	// The rate is actually defined by dividing Average by Period.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of connections allowed to arrive in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// MaxDelay is the maximum duration an excess connection is delayed, waiting to conform to the rate limit, before being closed.
+	// It defaults to 0, i.e. the excess connections are closed immediately.
+	MaxDelay ptypes.Duration `json:"maxDelay,omitempty" toml:"maxDelay,omitempty" yaml:"maxDelay,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPBandwidthLimit holds the TCP bandwidth limit middleware configuration.
+// This middleware throttles the data read from and written to the connections, in both directions,
+// with token buckets for each connection, and for each IP.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/bandwidthlimit/
+type TCPBandwidthLimit struct {
+	// Rate is the maximum throughput, in bytes/s, of a connection in each direction.
+	// It defaults to 0, which means no limit per connection.
+	Rate int64 `json:"rate,omitempty" toml:"rate,omitempty" yaml:"rate,omitempty" export:"true"`
+	// Burst is the maximum number of bytes of a connection transferred at once in each direction.
+	// It defaults to Rate.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// SourceRate is the maximum throughput, in bytes/s, shared by the connections from the same IP in each direction.
+	// It defaults to 0, which means no limit per IP.
+	SourceRate int64 `json:"sourceRate,omitempty" toml:"sourceRate,omitempty" yaml:"sourceRate,omitempty" export:"true"`
+	// SourceBurst is the maximum number of bytes of the connections from the same IP transferred at once in each direction.
+	// It defaults to SourceRate.
+	SourceBurst int64 `json:"sourceBurst,omitempty" toml:"sourceBurst,omitempty" yaml:"sourceBurst,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPIPWhiteList holds the TCP IPWhiteList middleware configuration.
 // Deprecated: please use IPAllowList instead.
 type TCPIPWhiteList struct {
@@ -38,3 +95,25 @@ type TCPIPAllowList struct {
 	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPDenyList holds the TCP IPDenyList middleware configuration.
+// This middleware refuses connections based on the client IP, against ranges which can be loaded from files and URLs.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ipdenylist/
+type TCPIPDenyList struct {
+	// SourceRange defines the denied IPs (or ranges of denied IPs by using CIDR notation).
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	// SourceRangeFiles defines the paths to files listing denied IPs (or ranges of denied IPs by using CIDR notation), one per line.
+	SourceRangeFiles []string `json:"sourceRangeFiles,omitempty" toml:"sourceRangeFiles,omitempty" yaml:"sourceRangeFiles,omitempty"`
+	// SourceRangeURLs defines the HTTP(S) URLs of lists of denied IPs (or ranges of denied IPs by using CIDR notation), one per line.
+	SourceRangeURLs []string `json:"sourceRangeURLs,omitempty" toml:"sourceRangeURLs,omitempty" yaml:"sourceRangeURLs,omitempty"`
+	// RefreshInterval defines the interval at which the lists are reloaded from the files and URLs.
+	// Default: 1h.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPIPDenyList.
+func (i *TCPIPDenyList) SetDefaults() {
+	i.RefreshInterval = ptypes.Duration(time.Hour)
+}
@@ -2,8 +2,14 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
 )
 
+// DefaultUDPStickyTTL is the default value for the UDPSticky TTL.
+const DefaultUDPStickyTTL = ptypes.Duration(time.Hour)
+
 // +k8s:deepcopy-gen=true
 
 // UDPConfiguration contains all the UDP configuration parameters.
@@ -25,6 +31,14 @@ type UDPService struct {
 // UDPWeightedRoundRobin is a weighted round robin UDP load-balancer of services.
 type UDPWeightedRoundRobin struct {
 	Services []UDPWRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	// HealthCheck enables automatic self-healthcheck for this service, i.e.
+	// whenever one of its children is reported as down, this service becomes aware of it,
+	// and takes it into account (i.e. it ignores the down child) when running the
+	// load-balancing algorithm. In addition, if the parent of this service also has
+	// HealthCheck enabled, this service reports to its parent any status change.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Sticky binds each client to the child service which handled its previous session.
+	Sticky *UDPSticky `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -54,6 +68,13 @@ type UDPRouter struct {
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
 	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// HealthCheck enables regular active checks of the responsiveness of the
+	// children servers of this load-balancer. To propagate status changes (e.g. all
+	// servers of this service are down) upwards, HealthCheck must also be enabled on
+	// the parent(s) of this service.
+	HealthCheck *UDPServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	// Sticky binds each client to the server which handled its previous session.
+	Sticky *UDPSticky `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -80,3 +101,46 @@ type UDPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `json:"-" toml:"-" yaml:"-" file:"-"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// UDPServerHealthCheck holds the UDP HealthCheck configuration.
+// The Send payload is sent as a probe datagram to the servers.
+// When Expect is set, a server is considered healthy if it responds within the timeout
+// with a datagram starting with the Expect payload.
+// Otherwise, a server is considered healthy as long as the probe is not rejected
+// (i.e. no ICMP port unreachable error is received) within the timeout.
+type UDPServerHealthCheck struct {
+	// Port defines the port used to probe the servers, instead of the port of their address.
+	Port int `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty" export:"true"`
+	// Send defines the payload of the probe datagram.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty" export:"true"`
+	// Expect defines the payload the server response must start with.
+	Expect   string          `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty" export:"true"`
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout  ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a UDPServerHealthCheck.
+func (h *UDPServerHealthCheck) SetDefaults() {
+	h.Interval = DefaultHealthCheckInterval
+	h.Timeout = DefaultHealthCheckTimeout
+}
+
+// +k8s:deepcopy-gen=true
+
+// UDPSticky holds the UDP sticky configuration.
+// A client is bound to the server which handled its previous session,
+// for as long as the server is available and the binding is not expired.
+// The bindings outlive the sessions, which are closed after the UDP timeout of the entryPoint.
+type UDPSticky struct {
+	// TTL defines how long a client stays bound to a server after the start of its last session.
+	TTL ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// SourcePort defines whether the clients are identified by their source IP and port, instead of their source IP only.
+	SourcePort bool `json:"sourcePort,omitempty" toml:"sourcePort,omitempty" yaml:"sourcePort,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a UDPSticky.
+func (s *UDPSticky) SetDefaults() {
+	s.TTL = DefaultUDPStickyTTL
+}
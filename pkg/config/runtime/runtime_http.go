@@ -7,6 +7,7 @@ import (
 	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -144,8 +145,9 @@ type ServiceInfo struct {
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
 
-	serverStatusMu sync.RWMutex
-	serverStatus   map[string]string // keyed by server URL
+	serverStatusMu  sync.RWMutex
+	serverStatus    map[string]string    // keyed by server URL
+	serverEjections map[string]time.Time // keyed by server URL
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -197,3 +199,38 @@ func (s *ServiceInfo) GetAllStatus() map[string]string {
 	}
 	return allStatus
 }
+
+// UpdateServerEjection records that the server is ejected by the passive health check until the given time.
+// A zero time means that the server is not ejected anymore.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateServerEjection(server string, until time.Time) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	if until.IsZero() {
+		delete(s.serverEjections, server)
+		return
+	}
+
+	if s.serverEjections == nil {
+		s.serverEjections = make(map[string]time.Time)
+	}
+	s.serverEjections[server] = until
+}
+
+// GetAllEjections returns the end of the ejection of all the ejected servers in ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetAllEjections() map[string]time.Time {
+	s.serverStatusMu.RLock()
+	defer s.serverStatusMu.RUnlock()
+
+	if len(s.serverEjections) == 0 {
+		return nil
+	}
+
+	allEjections := make(map[string]time.Time, len(s.serverEjections))
+	for k, v := range s.serverEjections {
+		allEjections[k] = v
+	}
+	return allEjections
+}
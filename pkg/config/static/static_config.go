@@ -76,6 +76,10 @@ type Configuration struct {
 	Core *Core `description:"Core controls." json:"core,omitempty" toml:"core,omitempty" yaml:"core,omitempty" export:"true"`
 
 	Spiffe *SpiffeClientConfig `description:"SPIFFE integration configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" export:"true"`
+
+	Locality *Locality `description:"Locality of Traefik, to prefer the servers of the same zone." json:"locality,omitempty" toml:"locality,omitempty" yaml:"locality,omitempty" export:"true"`
+
+	GeoIP *GeoIP `description:"GeoIP databases, used by the ClientCountry and ClientASN matchers, and by the GeoIP middleware." json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" export:"true"`
 }
 
 // Core configures Traefik core behavior.
@@ -88,6 +92,25 @@ func (c *Core) SetDefaults() {
 	c.DefaultRuleSyntax = "v3"
 }
 
+// Locality holds the locality of Traefik.
+// The load-balancers prefer the servers of the same zone, then of the same region,
+// and spill over to the next servers when the healthy capacity of the preferred ones drops below MinHealthyPercent.
+type Locality struct {
+	Zone              string `description:"Zone in which Traefik runs." json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" export:"true"`
+	Region            string `description:"Region in which Traefik runs." json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" export:"true"`
+	MinHealthyPercent int    `description:"Percentage of healthy capacity of the preferred servers under which the requests spill over to the next servers." json:"minHealthyPercent,omitempty" toml:"minHealthyPercent,omitempty" yaml:"minHealthyPercent,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (l *Locality) SetDefaults() {
+	l.MinHealthyPercent = 70
+}
+
+// GeoIP holds the GeoIP databases configuration.
+type GeoIP struct {
+	Databases []string `description:"Paths to the MaxMind (MMDB) databases, looked up in order. The databases are reloaded when their files change." json:"databases,omitempty" toml:"databases,omitempty" yaml:"databases,omitempty" export:"true"`
+}
+
 // SpiffeClientConfig defines the SPIFFE client configuration.
 type SpiffeClientConfig struct {
 	WorkloadAPIAddr string `description:"Defines the workload API address." json:"workloadAPIAddr,omitempty" toml:"workloadAPIAddr,omitempty" yaml:"workloadAPIAddr,omitempty"`
@@ -0,0 +1,234 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+// Record holds the GeoIP information of an IP address.
+// The fields are empty when the databases do not provide them.
+type Record struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country.
+	Country string
+	// ASN is the autonomous system number.
+	ASN uint
+}
+
+// ASNString returns the autonomous system number as a string, or an empty string if it is unknown.
+func (r Record) ASNString() string {
+	if r.ASN == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(r.ASN), 10)
+}
+
+// mmdbRecord is the subset of the MaxMind City, Country, and ASN databases records used by Traefik.
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	AutonomousSystemNumber uint `maxminddb:"autonomous_system_number"`
+}
+
+// Database looks up the GeoIP information of IP addresses in MaxMind (MMDB) databases.
+// The databases are loaded in memory, and can be reloaded when their files change.
+type Database struct {
+	paths   []string
+	readers []atomic.Pointer[maxminddb.Reader]
+}
+
+// NewDatabase loads the MaxMind (MMDB) databases of the given paths.
+// The databases are looked up in order, the first one providing the country, resp. the ASN, of an IP address wins,
+// which allows to combine a country database with an ASN one.
+func NewDatabase(paths []string) (*Database, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no GeoIP database defined")
+	}
+
+	db := &Database{
+		paths:   paths,
+		readers: make([]atomic.Pointer[maxminddb.Reader], len(paths)),
+	}
+
+	for i, path := range paths {
+		reader, err := loadReader(path)
+		if err != nil {
+			return nil, err
+		}
+
+		db.readers[i].Store(reader)
+	}
+
+	return db, nil
+}
+
+// Lookup returns the GeoIP information of the given IP address.
+func (d *Database) Lookup(addr netip.Addr) (Record, error) {
+	var record Record
+
+	ipAddr := net.IP(addr.Unmap().AsSlice())
+	for i := range d.readers {
+		var result mmdbRecord
+		if err := d.readers[i].Load().Lookup(ipAddr, &result); err != nil {
+			return Record{}, fmt.Errorf("looking up %s in %s: %w", addr, d.paths[i], err)
+		}
+
+		if record.Country == "" {
+			record.Country = result.Country.ISOCode
+		}
+		if record.Country == "" {
+			record.Country = result.RegisteredCountry.ISOCode
+		}
+		if record.ASN == 0 {
+			record.ASN = result.AutonomousSystemNumber
+		}
+
+		if record.Country != "" && record.ASN != 0 {
+			break
+		}
+	}
+
+	return record, nil
+}
+
+// LookupString returns the GeoIP information of the given IP address, which can be followed by a port.
+func (d *Database) LookupString(addr string) (Record, error) {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	ipAddr, err := netip.ParseAddr(addr)
+	if err != nil {
+		return Record{}, fmt.Errorf("parsing IP address %q: %w", addr, err)
+	}
+
+	return d.Lookup(ipAddr)
+}
+
+// Watch reloads the databases when their files change, until the pool is stopped.
+// The previous version of a database is kept if the new one cannot be loaded.
+func (d *Database) Watch(pool *safe.Pool) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating GeoIP database watcher: %w", err)
+	}
+
+	// The parent directories are watched, as the database files are usually replaced rather than rewritten.
+	dirs := make(map[string]struct{})
+	for _, path := range d.paths {
+		dir := filepath.Dir(path)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("watching GeoIP database directory %s: %w", dir, err)
+		}
+		dirs[dir] = struct{}{}
+	}
+
+	pool.GoCtx(func(ctx context.Context) {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt := <-watcher.Events:
+				if !evt.Has(fsnotify.Create) && !evt.Has(fsnotify.Write) {
+					continue
+				}
+
+				for i, path := range d.paths {
+					if filepath.Clean(evt.Name) != filepath.Clean(path) {
+						continue
+					}
+
+					if err := d.reload(i); err != nil {
+						log.Error().Err(err).Str("database", path).Msg("Unable to reload GeoIP database, keeping the previous one")
+						continue
+					}
+
+					log.Info().Str("database", path).Msg("GeoIP database reloaded")
+				}
+			case err := <-watcher.Errors:
+				log.Error().Err(err).Msg("GeoIP database watcher error")
+			}
+		}
+	})
+
+	return nil
+}
+
+func (d *Database) reload(i int) error {
+	reader, err := loadReader(d.paths[i])
+	if err != nil {
+		return err
+	}
+
+	d.readers[i].Store(reader)
+
+	return nil
+}
+
+// loadReader loads the database in memory rather than memory-mapping it,
+// so that the lookups in progress are not affected when the file is replaced.
+func loadReader(path string) (*maxminddb.Reader, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading GeoIP database: %w", err)
+	}
+
+	reader, err := maxminddb.FromBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("loading GeoIP database %s: %w", path, err)
+	}
+
+	return reader, nil
+}
+
+// ParseCountry validates the given ISO 3166-1 alpha-2 country code, and returns it in upper case.
+func ParseCountry(country string) (string, error) {
+	if len(country) != 2 || !isLetter(country[0]) || !isLetter(country[1]) {
+		return "", fmt.Errorf("invalid country code %q, expected an ISO 3166-1 alpha-2 code", country)
+	}
+
+	return strings.ToUpper(country), nil
+}
+
+// ParseASN parses the given autonomous system number, which can be prefixed by AS.
+func ParseASN(asn string) (uint, error) {
+	number := asn
+	if len(number) > 2 && strings.EqualFold(number[:2], "AS") {
+		number = number[2:]
+	}
+
+	value, err := strconv.ParseUint(number, 10, 32)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("invalid autonomous system number %q", asn)
+	}
+
+	return uint(value), nil
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -0,0 +1,187 @@
+package geoip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+func TestDatabase_LookupString(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		paths    []string
+		addr     string
+		expected Record
+	}{
+		{
+			desc:     "IPv4",
+			paths:    []string{"fixtures/test.mmdb"},
+			addr:     "1.2.3.4",
+			expected: Record{Country: "FR", ASN: 64500},
+		},
+		{
+			desc:     "IPv4 with port",
+			paths:    []string{"fixtures/test.mmdb"},
+			addr:     "81.2.69.1:1234",
+			expected: Record{Country: "GB", ASN: 64501},
+		},
+		{
+			desc:     "IPv4-mapped IPv6",
+			paths:    []string{"fixtures/test.mmdb"},
+			addr:     "::ffff:1.2.3.4",
+			expected: Record{Country: "FR", ASN: 64500},
+		},
+		{
+			desc:     "IPv6 with port",
+			paths:    []string{"fixtures/test.mmdb"},
+			addr:     "[2001:db8::1]:1234",
+			expected: Record{Country: "DE", ASN: 64502},
+		},
+		{
+			desc:     "registered country",
+			paths:    []string{"fixtures/test.mmdb"},
+			addr:     "10.1.1.1",
+			expected: Record{Country: "US"},
+		},
+		{
+			desc:  "unknown",
+			paths: []string{"fixtures/test.mmdb"},
+			addr:  "8.8.8.8",
+		},
+		{
+			desc:     "first database wins",
+			paths:    []string{"fixtures/asn.mmdb", "fixtures/test.mmdb"},
+			addr:     "1.2.3.4",
+			expected: Record{Country: "FR", ASN: 64999},
+		},
+		{
+			desc:     "combined databases",
+			paths:    []string{"fixtures/test.mmdb", "fixtures/asn.mmdb"},
+			addr:     "8.8.8.8",
+			expected: Record{ASN: 15169},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := NewDatabase(test.paths)
+			require.NoError(t, err)
+
+			record, err := db.LookupString(test.addr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, record)
+		})
+	}
+}
+
+func TestDatabase_LookupString_invalid(t *testing.T) {
+	db, err := NewDatabase([]string{"fixtures/test.mmdb"})
+	require.NoError(t, err)
+
+	_, err = db.LookupString("foo")
+	assert.Error(t, err)
+}
+
+func TestNewDatabase_invalid(t *testing.T) {
+	_, err := NewDatabase(nil)
+	assert.Error(t, err)
+
+	_, err = NewDatabase([]string{"fixtures/does-not-exist.mmdb"})
+	assert.Error(t, err)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.mmdb")
+	err = os.WriteFile(invalid, []byte("foo"), 0o644)
+	require.NoError(t, err)
+
+	_, err = NewDatabase([]string{invalid})
+	assert.Error(t, err)
+}
+
+func TestRecord_ASNString(t *testing.T) {
+	assert.Equal(t, "64500", Record{ASN: 64500}.ASNString())
+	assert.Equal(t, "", Record{}.ASNString())
+}
+
+func TestDatabase_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip.mmdb")
+	copyFile(t, "fixtures/asn.mmdb", path)
+
+	db, err := NewDatabase([]string{path})
+	require.NoError(t, err)
+
+	pool := safe.NewPool(context.Background())
+	t.Cleanup(pool.Stop)
+
+	err = db.Watch(pool)
+	require.NoError(t, err)
+
+	record, err := db.LookupString("1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, Record{ASN: 64999}, record)
+
+	// The previous database is kept when the new one is invalid.
+	err = os.WriteFile(path, []byte("foo"), 0o644)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	record, err = db.LookupString("1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, Record{ASN: 64999}, record)
+
+	copyFile(t, "fixtures/test.mmdb", path)
+
+	assert.Eventually(t, func() bool {
+		record, err := db.LookupString("1.2.3.4")
+		return err == nil && record == Record{Country: "FR", ASN: 64500}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestParseCountry(t *testing.T) {
+	country, err := ParseCountry("fr")
+	require.NoError(t, err)
+	assert.Equal(t, "FR", country)
+
+	for _, value := range []string{"", "F", "FRA", "F1"} {
+		_, err = ParseCountry(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseASN(t *testing.T) {
+	for _, value := range []string{"64500", "AS64500", "as64500"} {
+		asn, err := ParseASN(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, uint(64500), asn)
+	}
+
+	for _, value := range []string{"", "AS", "0", "-1", "foo", "4294967296"} {
+		_, err := ParseASN(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	content, err := os.ReadFile(src)
+	require.NoError(t, err)
+
+	// The file is replaced rather than rewritten, like the database updaters do.
+	tmp := dst + ".tmp"
+	err = os.WriteFile(tmp, content, 0o644)
+	require.NoError(t, err)
+
+	err = os.Rename(tmp, dst)
+	require.NoError(t, err)
+}
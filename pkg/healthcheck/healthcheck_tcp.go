@@ -0,0 +1,179 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"golang.org/x/net/proxy"
+)
+
+// TCPHealthCheckTarget is a TCP server checked by a ServiceTCPHealthChecker.
+type TCPHealthCheckTarget struct {
+	Address string
+	// Dialer is the dialer used to proxy the connections to the server,
+	// which is reused to check it with the same transport (e.g. TLS) settings.
+	Dialer tcp.Dialer
+}
+
+// ServiceTCPHealthChecker regularly checks the TCP servers of a service,
+// and reports their status to the service load-balancer.
+type ServiceTCPHealthChecker struct {
+	balancer StatusSetter
+	info     *runtime.TCPServiceInfo
+
+	config   *dynamic.TCPServerHealthCheck
+	interval time.Duration
+	timeout  time.Duration
+
+	metrics metricsHealthCheck
+
+	targets map[string]*TCPHealthCheckTarget
+}
+
+// NewServiceTCPHealthChecker creates a new ServiceTCPHealthChecker.
+func NewServiceTCPHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.TCPServerHealthCheck, service StatusSetter, info *runtime.TCPServiceInfo, targets map[string]*TCPHealthCheckTarget) *ServiceTCPHealthChecker {
+	logger := log.Ctx(ctx)
+
+	interval := time.Duration(config.Interval)
+	if interval <= 0 {
+		logger.Error().Msg("Health check interval smaller than zero")
+		interval = time.Duration(dynamic.DefaultHealthCheckInterval)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		logger.Error().Msg("Health check timeout smaller than zero")
+		timeout = time.Duration(dynamic.DefaultHealthCheckTimeout)
+	}
+
+	return &ServiceTCPHealthChecker{
+		balancer: service,
+		info:     info,
+		config:   config,
+		interval: interval,
+		timeout:  timeout,
+		targets:  targets,
+		metrics:  metrics,
+	}
+}
+
+// Launch runs the health checks until the given context is canceled.
+func (thc *ServiceTCPHealthChecker) Launch(ctx context.Context) {
+	ticker := time.NewTicker(thc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			for proxyName, target := range thc.targets {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				up := true
+				serverUpMetricValue := float64(1)
+
+				if err := thc.executeHealthCheck(ctx, target); err != nil {
+					// The context is canceled when the dynamic configuration is refreshed.
+					if errors.Is(err, context.Canceled) {
+						return
+					}
+
+					log.Ctx(ctx).Warn().
+						Str("targetAddress", target.Address).
+						Err(err).
+						Msg("Health check failed.")
+
+					up = false
+					serverUpMetricValue = float64(0)
+				}
+
+				thc.balancer.SetStatus(ctx, proxyName, up)
+
+				statusStr := runtime.StatusDown
+				if up {
+					statusStr = runtime.StatusUp
+				}
+
+				thc.info.UpdateServerStatus(target.Address, statusStr)
+
+				thc.metrics.ServiceServerUpGauge().
+					With("service", proxyName, "url", target.Address).
+					Set(serverUpMetricValue)
+			}
+		}
+	}
+}
+
+// executeHealthCheck returns an error with a meaningful description if the health check failed.
+func (thc *ServiceTCPHealthChecker) executeHealthCheck(ctx context.Context, target *TCPHealthCheckTarget) error {
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(thc.timeout))
+	defer cancel()
+
+	address := target.Address
+	if thc.config.Port != 0 {
+		host, _, err := net.SplitHostPort(target.Address)
+		if err != nil {
+			return fmt.Errorf("parsing server address: %w", err)
+		}
+
+		address = net.JoinHostPort(host, strconv.Itoa(thc.config.Port))
+	}
+
+	conn, err := dial(ctx, target.Dialer, address)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return context.Canceled
+		}
+		return fmt.Errorf("connecting to %s: %w", address, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("setting connection deadline: %w", err)
+		}
+	}
+
+	if thc.config.Send != "" {
+		if _, err := conn.Write([]byte(thc.config.Send)); err != nil {
+			return fmt.Errorf("sending payload: %w", err)
+		}
+	}
+
+	if thc.config.Expect != "" {
+		resp := make([]byte, len(thc.config.Expect))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+
+		if !bytes.Equal(resp, []byte(thc.config.Expect)) {
+			return fmt.Errorf("received unexpected response: %q", resp)
+		}
+	}
+
+	return nil
+}
+
+func dial(ctx context.Context, dialer tcp.Dialer, address string) (net.Conn, error) {
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, "tcp", address)
+	}
+
+	return dialer.Dial("tcp", address)
+}
@@ -0,0 +1,233 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestServiceTCPHealthChecker_executeHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.TCPServerHealthCheck
+		response    string
+		closed      bool
+		port        bool
+		expectError bool
+	}{
+		{
+			desc: "connection only",
+		},
+		{
+			desc:     "expected response",
+			config:   dynamic.TCPServerHealthCheck{Send: "PING\n", Expect: "PONG"},
+			response: "PONG\n",
+		},
+		{
+			desc:        "unexpected response",
+			config:      dynamic.TCPServerHealthCheck{Send: "PING\n", Expect: "PONG"},
+			response:    "NOPE\n",
+			expectError: true,
+		},
+		{
+			desc:        "response too short",
+			config:      dynamic.TCPServerHealthCheck{Send: "PING\n", Expect: "PONG"},
+			response:    "PO",
+			expectError: true,
+		},
+		{
+			desc:        "no response",
+			config:      dynamic.TCPServerHealthCheck{Send: "PING\n", Expect: "PONG"},
+			expectError: true,
+		},
+		{
+			desc:        "connection refused",
+			closed:      true,
+			expectError: true,
+		},
+		{
+			desc:     "port override",
+			config:   dynamic.TCPServerHealthCheck{Expect: "PONG"},
+			response: "PONG",
+			port:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			address := startTCPServer(t, test.config.Send, test.response)
+			if test.closed {
+				address = closedAddress(t)
+			}
+
+			config := test.config
+			config.Timeout = ptypes.Duration(500 * time.Millisecond)
+
+			targetAddress := address
+			if test.port {
+				_, port, err := net.SplitHostPort(address)
+				require.NoError(t, err)
+
+				config.Port, err = strconv.Atoi(port)
+				require.NoError(t, err)
+
+				targetAddress = closedAddress(t)
+			}
+
+			hc := NewServiceTCPHealthChecker(context.Background(), nil, &config, nil, nil, nil)
+
+			err := hc.executeHealthCheck(context.Background(), &TCPHealthCheckTarget{Address: targetAddress, Dialer: &testDialer{}})
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceTCPHealthChecker_Launch(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		response              string
+		expNumRemovedServers  int
+		expNumUpsertedServers int
+		expGaugeValue         float64
+		targetStatus          string
+	}{
+		{
+			desc:                  "healthy server staying healthy",
+			response:              "PONG",
+			expNumUpsertedServers: 1,
+			expGaugeValue:         1,
+			targetStatus:          runtime.StatusUp,
+		},
+		{
+			desc:                 "healthy server becoming sick",
+			response:             "NOPE",
+			expNumRemovedServers: 1,
+			expGaugeValue:        0,
+			targetStatus:         runtime.StatusDown,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			address := startTCPServer(t, "PING", test.response)
+
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+
+			config := &dynamic.TCPServerHealthCheck{
+				Send:     "PING",
+				Expect:   "PONG",
+				Interval: ptypes.Duration(100 * time.Millisecond),
+				Timeout:  ptypes.Duration(99 * time.Millisecond),
+			}
+
+			gauge := &testhelpers.CollectingGauge{}
+			serviceInfo := &runtime.TCPServiceInfo{}
+			targets := map[string]*TCPHealthCheckTarget{
+				"test": {Address: address, Dialer: &testDialer{}},
+			}
+
+			hc := NewServiceTCPHealthChecker(ctx, &MetricsMock{gauge}, config, lb, serviceInfo, targets)
+
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+
+			go func() {
+				hc.Launch(ctx)
+				wg.Done()
+			}()
+
+			// Let the first health check run, and stop before the second one.
+			time.Sleep(150 * time.Millisecond)
+			cancel()
+			wg.Wait()
+
+			lb.Lock()
+			defer lb.Unlock()
+
+			assert.Equal(t, test.expNumRemovedServers, lb.numRemovedServers, "removed servers")
+			assert.Equal(t, test.expNumUpsertedServers, lb.numUpsertedServers, "upserted servers")
+			assert.InDelta(t, test.expGaugeValue, gauge.GaugeValue, delta, "ServerUp Gauge")
+			assert.Equal(t, map[string]string{address: test.targetStatus}, serviceInfo.GetAllStatus())
+		})
+	}
+}
+
+type testDialer struct {
+	net.Dialer
+}
+
+func (d *testDialer) TerminationDelay() time.Duration {
+	return 0
+}
+
+// startTCPServer starts a TCP server which waits for the given payload,
+// and writes the given response before closing the connection.
+func startTCPServer(t *testing.T, payload, response string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+
+				if payload != "" {
+					buf := make([]byte, len(payload))
+					if _, err := io.ReadFull(conn, buf); err != nil {
+						return
+					}
+				}
+
+				_, _ = conn.Write([]byte(response))
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// closedAddress returns the address of a TCP listener which is already closed.
+func closedAddress(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	return address
+}
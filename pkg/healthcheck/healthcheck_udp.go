@@ -0,0 +1,171 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+// maxDatagramSize is the maximum size of a UDP datagram read from a server.
+const maxDatagramSize = 65535
+
+// ServiceUDPHealthChecker regularly probes the UDP servers of a service,
+// and reports their status to the service load-balancer.
+type ServiceUDPHealthChecker struct {
+	balancer StatusSetter
+	info     *runtime.UDPServiceInfo
+
+	config   *dynamic.UDPServerHealthCheck
+	interval time.Duration
+	timeout  time.Duration
+
+	metrics metricsHealthCheck
+
+	// targets are the server addresses, keyed by the name of the server in the load-balancer.
+	targets map[string]string
+}
+
+// NewServiceUDPHealthChecker creates a new ServiceUDPHealthChecker.
+func NewServiceUDPHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.UDPServerHealthCheck, service StatusSetter, info *runtime.UDPServiceInfo, targets map[string]string) *ServiceUDPHealthChecker {
+	logger := log.Ctx(ctx)
+
+	interval := time.Duration(config.Interval)
+	if interval <= 0 {
+		logger.Error().Msg("Health check interval smaller than zero")
+		interval = time.Duration(dynamic.DefaultHealthCheckInterval)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		logger.Error().Msg("Health check timeout smaller than zero")
+		timeout = time.Duration(dynamic.DefaultHealthCheckTimeout)
+	}
+
+	return &ServiceUDPHealthChecker{
+		balancer: service,
+		info:     info,
+		config:   config,
+		interval: interval,
+		timeout:  timeout,
+		targets:  targets,
+		metrics:  metrics,
+	}
+}
+
+// Launch runs the health checks until the given context is canceled.
+func (uhc *ServiceUDPHealthChecker) Launch(ctx context.Context) {
+	ticker := time.NewTicker(uhc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			for proxyName, address := range uhc.targets {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				up := true
+				serverUpMetricValue := float64(1)
+
+				if err := uhc.executeHealthCheck(ctx, address); err != nil {
+					// The context is canceled when the dynamic configuration is refreshed.
+					if errors.Is(err, context.Canceled) {
+						return
+					}
+
+					log.Ctx(ctx).Warn().
+						Str("targetAddress", address).
+						Err(err).
+						Msg("Health check failed.")
+
+					up = false
+					serverUpMetricValue = float64(0)
+				}
+
+				uhc.balancer.SetStatus(ctx, proxyName, up)
+
+				statusStr := runtime.StatusDown
+				if up {
+					statusStr = runtime.StatusUp
+				}
+
+				uhc.info.UpdateServerStatus(address, statusStr)
+
+				uhc.metrics.ServiceServerUpGauge().
+					With("service", proxyName, "url", address).
+					Set(serverUpMetricValue)
+			}
+		}
+	}
+}
+
+// executeHealthCheck returns an error with a meaningful description if the health check failed.
+func (uhc *ServiceUDPHealthChecker) executeHealthCheck(ctx context.Context, address string) error {
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(uhc.timeout))
+	defer cancel()
+
+	if uhc.config.Port != 0 {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return fmt.Errorf("parsing server address: %w", err)
+		}
+
+		address = net.JoinHostPort(host, strconv.Itoa(uhc.config.Port))
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return context.Canceled
+		}
+		return fmt.Errorf("connecting to %s: %w", address, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("setting connection deadline: %w", err)
+		}
+	}
+
+	if _, err := conn.Write([]byte(uhc.config.Send)); err != nil {
+		return fmt.Errorf("sending probe: %w", err)
+	}
+
+	resp := make([]byte, maxDatagramSize)
+	n, err := conn.Read(resp)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return context.Canceled
+		}
+
+		// Without an expected response, a server which does not answer
+		// within the timeout, and does not reject the probe, is considered healthy.
+		if uhc.config.Expect == "" && errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil
+		}
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if !bytes.HasPrefix(resp[:n], []byte(uhc.config.Expect)) {
+		return fmt.Errorf("received unexpected response: %q", resp[:n])
+	}
+
+	return nil
+}
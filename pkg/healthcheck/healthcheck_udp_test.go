@@ -0,0 +1,214 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestServiceUDPHealthChecker_executeHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.UDPServerHealthCheck
+		response    string
+		closed      bool
+		port        bool
+		expectError bool
+	}{
+		{
+			desc:   "no expected response, no response",
+			config: dynamic.UDPServerHealthCheck{Send: "PING"},
+		},
+		{
+			desc:     "no expected response, any response",
+			config:   dynamic.UDPServerHealthCheck{Send: "PING"},
+			response: "WHATEVER",
+		},
+		{
+			desc:     "expected response",
+			config:   dynamic.UDPServerHealthCheck{Send: "PING", Expect: "PONG"},
+			response: "PONG\n",
+		},
+		{
+			desc:        "unexpected response",
+			config:      dynamic.UDPServerHealthCheck{Send: "PING", Expect: "PONG"},
+			response:    "NOPE",
+			expectError: true,
+		},
+		{
+			desc:        "no response",
+			config:      dynamic.UDPServerHealthCheck{Send: "PING", Expect: "PONG"},
+			expectError: true,
+		},
+		{
+			desc:        "probe rejected",
+			config:      dynamic.UDPServerHealthCheck{Send: "PING"},
+			closed:      true,
+			expectError: true,
+		},
+		{
+			desc:     "port override",
+			config:   dynamic.UDPServerHealthCheck{Send: "PING", Expect: "PONG"},
+			response: "PONG",
+			port:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			address := startUDPServer(t, test.response)
+			if test.closed {
+				address = closedUDPAddress(t)
+			}
+
+			config := test.config
+			config.Timeout = ptypes.Duration(200 * time.Millisecond)
+
+			targetAddress := address
+			if test.port {
+				_, port, err := net.SplitHostPort(address)
+				require.NoError(t, err)
+
+				config.Port, err = strconv.Atoi(port)
+				require.NoError(t, err)
+
+				targetAddress = closedUDPAddress(t)
+			}
+
+			hc := NewServiceUDPHealthChecker(context.Background(), nil, &config, nil, nil, nil)
+
+			err := hc.executeHealthCheck(context.Background(), targetAddress)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceUDPHealthChecker_Launch(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		response              string
+		expNumRemovedServers  int
+		expNumUpsertedServers int
+		expGaugeValue         float64
+		targetStatus          string
+	}{
+		{
+			desc:                  "healthy server staying healthy",
+			response:              "PONG",
+			expNumUpsertedServers: 1,
+			expGaugeValue:         1,
+			targetStatus:          runtime.StatusUp,
+		},
+		{
+			desc:                 "healthy server becoming sick",
+			response:             "NOPE",
+			expNumRemovedServers: 1,
+			expGaugeValue:        0,
+			targetStatus:         runtime.StatusDown,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			address := startUDPServer(t, test.response)
+
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+
+			config := &dynamic.UDPServerHealthCheck{
+				Send:     "PING",
+				Expect:   "PONG",
+				Interval: ptypes.Duration(100 * time.Millisecond),
+				Timeout:  ptypes.Duration(40 * time.Millisecond),
+			}
+
+			gauge := &testhelpers.CollectingGauge{}
+			serviceInfo := &runtime.UDPServiceInfo{}
+
+			hc := NewServiceUDPHealthChecker(ctx, &MetricsMock{gauge}, config, lb, serviceInfo, map[string]string{"test": address})
+
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+
+			go func() {
+				hc.Launch(ctx)
+				wg.Done()
+			}()
+
+			// Let the first health check run, and stop before the second one.
+			time.Sleep(180 * time.Millisecond)
+			cancel()
+			wg.Wait()
+
+			lb.Lock()
+			defer lb.Unlock()
+
+			assert.Equal(t, test.expNumRemovedServers, lb.numRemovedServers, "removed servers")
+			assert.Equal(t, test.expNumUpsertedServers, lb.numUpsertedServers, "upserted servers")
+			assert.InDelta(t, test.expGaugeValue, gauge.GaugeValue, delta, "ServerUp Gauge")
+			assert.Equal(t, map[string]string{address: test.targetStatus}, serviceInfo.GetAllStatus())
+		})
+	}
+}
+
+// startUDPServer starts a UDP server which answers every datagram with the given response,
+// or which does not answer at all if the response is empty.
+func startUDPServer(t *testing.T, response string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			if response != "" {
+				_, _ = conn.WriteTo([]byte(response), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// closedUDPAddress returns the address of a UDP socket which is already closed.
+func closedUDPAddress(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	address := conn.LocalAddr().String()
+	require.NoError(t, conn.Close())
+
+	return address
+}
@@ -0,0 +1,286 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+type metricsPassiveHealthCheck interface {
+	metricsHealthCheck
+	ServiceServerEjectionsCounter() gokitmetrics.Counter
+}
+
+// PassiveHealthChecker watches the responses of the servers of a service to the forwarded requests,
+// and ejects the failing servers from the service load-balancer for an exponentially growing period.
+// Ejections and re-admissions go through the StatusSetter of the load-balancer,
+// the same way as the status changes detected by the active health checks.
+type PassiveHealthChecker struct {
+	ctx         context.Context
+	serviceName string
+	balancer    StatusSetter
+	info        *runtime.ServiceInfo
+	metrics     metricsPassiveHealthCheck
+
+	consecutiveFailures int
+	maxLatency          time.Duration
+	baseEjectionTime    time.Duration
+	maxEjectionTime     time.Duration
+	maxEjectionPercent  int
+
+	// timeNow and afterFunc can be overridden for testing purposes.
+	timeNow   func() time.Time
+	afterFunc func(d time.Duration, f func()) (stop func() bool)
+
+	mu      sync.Mutex
+	servers []*passiveServer
+	ejected int
+	// stopped is whether the context of the checker is done, i.e. the configuration has been reloaded,
+	// in which case the ejected servers are not re-admitted in the replaced load-balancer.
+	stopped bool
+}
+
+type passiveServer struct {
+	name   string
+	target string
+
+	failures  int
+	ejected   bool
+	ejections int
+	// readmittedAt is the time at which the server was re-admitted after its last ejection.
+	readmittedAt time.Time
+	// stopReadmit stops the timer re-admitting the server after its current ejection.
+	stopReadmit func() bool
+}
+
+// NewPassiveHealthChecker creates a new PassiveHealthChecker.
+func NewPassiveHealthChecker(ctx context.Context, metrics metricsPassiveHealthCheck, config *dynamic.PassiveServerHealthCheck, serviceName string, service StatusSetter, info *runtime.ServiceInfo) *PassiveHealthChecker {
+	logger := log.Ctx(ctx)
+
+	consecutiveFailures := config.ConsecutiveFailures
+	if consecutiveFailures <= 0 {
+		logger.Error().Msg("Passive health check consecutive failures smaller than one")
+		consecutiveFailures = dynamic.DefaultPassiveHealthCheckConsecutiveFailures
+	}
+
+	baseEjectionTime := time.Duration(config.BaseEjectionTime)
+	if baseEjectionTime <= 0 {
+		logger.Error().Msg("Passive health check base ejection time smaller than zero")
+		baseEjectionTime = time.Duration(dynamic.DefaultPassiveHealthCheckBaseEjectionTime)
+	}
+
+	maxEjectionTime := time.Duration(config.MaxEjectionTime)
+	if maxEjectionTime <= 0 {
+		logger.Error().Msg("Passive health check max ejection time smaller than zero")
+		maxEjectionTime = time.Duration(dynamic.DefaultPassiveHealthCheckMaxEjectionTime)
+	}
+
+	if maxEjectionTime < baseEjectionTime {
+		logger.Error().Msg("Passive health check max ejection time smaller than the base ejection time")
+		maxEjectionTime = baseEjectionTime
+	}
+
+	maxEjectionPercent := config.MaxEjectionPercent
+	if maxEjectionPercent <= 0 || maxEjectionPercent > 100 {
+		logger.Error().Msg("Passive health check max ejection percent not between 0 and 100")
+		maxEjectionPercent = dynamic.DefaultPassiveHealthCheckMaxEjectionPercent
+	}
+
+	checker := &PassiveHealthChecker{
+		ctx:                 ctx,
+		serviceName:         serviceName,
+		balancer:            service,
+		info:                info,
+		metrics:             metrics,
+		consecutiveFailures: consecutiveFailures,
+		maxLatency:          time.Duration(config.MaxLatency),
+		baseEjectionTime:    baseEjectionTime,
+		maxEjectionTime:     maxEjectionTime,
+		maxEjectionPercent:  maxEjectionPercent,
+		timeNow:             time.Now,
+		afterFunc: func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		},
+	}
+
+	context.AfterFunc(ctx, checker.stop)
+
+	return checker
+}
+
+// stop stops the timers re-admitting the ejected servers.
+func (p *PassiveHealthChecker) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = true
+
+	for _, server := range p.servers {
+		if server.stopReadmit != nil {
+			server.stopReadmit()
+			server.stopReadmit = nil
+		}
+	}
+}
+
+// WrapRoundTripper returns a RoundTripper reporting the outcome of the requests forwarded to the given server,
+// named childName in the load-balancer, through the given RoundTripper.
+func (p *PassiveHealthChecker) WrapRoundTripper(childName string, target *url.URL, rt http.RoundTripper) http.RoundTripper {
+	server := &passiveServer{name: childName, target: target.String()}
+
+	p.mu.Lock()
+	p.servers = append(p.servers, server)
+	p.mu.Unlock()
+
+	return &passiveRoundTripper{checker: p, server: server, next: rt}
+}
+
+type passiveRoundTripper struct {
+	checker *PassiveHealthChecker
+	server  *passiveServer
+	next    http.RoundTripper
+}
+
+func (r *passiveRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := r.checker.timeNow()
+
+	resp, err := r.next.RoundTrip(req)
+
+	// A request canceled by the client says nothing about the health of the server.
+	if req.Context().Err() != nil {
+		return resp, err
+	}
+
+	var failure error
+	switch {
+	case err != nil:
+		failure = err
+	case resp.StatusCode >= http.StatusInternalServerError:
+		failure = fmt.Errorf("received status code: %d", resp.StatusCode)
+	case r.checker.maxLatency > 0 && r.checker.timeNow().Sub(start) > r.checker.maxLatency:
+		failure = fmt.Errorf("response latency over %s", r.checker.maxLatency)
+	}
+
+	r.checker.record(r.server, failure)
+
+	return resp, err
+}
+
+// record updates the state of the server with the outcome of a forwarded request,
+// and ejects it if it reached the maximum number of consecutive failures.
+func (p *PassiveHealthChecker) record(server *passiveServer, failure error) {
+	p.mu.Lock()
+
+	// Requests in flight when the server was ejected are not taken into account.
+	if server.ejected || p.stopped {
+		p.mu.Unlock()
+		return
+	}
+
+	if failure == nil {
+		server.failures = 0
+		p.mu.Unlock()
+		return
+	}
+
+	server.failures++
+	if server.failures < p.consecutiveFailures {
+		p.mu.Unlock()
+		return
+	}
+
+	logger := log.Ctx(p.ctx).With().Str("targetURL", server.target).Logger()
+
+	if p.ejected >= p.maxEjected() {
+		p.mu.Unlock()
+		logger.Warn().Err(failure).Msg("Passive health check failed, but the maximum number of ejected servers is reached.")
+		return
+	}
+
+	now := p.timeNow()
+
+	// The ejection time backs off exponentially as long as the server keeps being ejected,
+	// and is reset once the server has stayed healthy for the maximum ejection time.
+	if !server.readmittedAt.IsZero() && now.Sub(server.readmittedAt) > p.maxEjectionTime {
+		server.ejections = 0
+	}
+
+	ejectionTime := p.maxEjectionTime
+	if server.ejections < 32 && p.baseEjectionTime<<server.ejections < p.maxEjectionTime {
+		ejectionTime = p.baseEjectionTime << server.ejections
+	}
+
+	server.failures = 0
+	server.ejected = true
+	server.ejections++
+	p.ejected++
+
+	p.mu.Unlock()
+
+	logger.Warn().Err(failure).Msgf("Passive health check failed, ejecting server for %s.", ejectionTime)
+
+	p.balancer.SetStatus(p.ctx, server.name, false)
+	p.info.UpdateServerStatus(server.target, runtime.StatusDown)
+	p.info.UpdateServerEjection(server.target, now.Add(ejectionTime))
+
+	p.metrics.ServiceServerUpGauge().
+		With("service", server.name, "url", server.target).
+		Set(0)
+	p.metrics.ServiceServerEjectionsCounter().
+		With("service", p.serviceName, "url", server.target).
+		Add(1)
+
+	stopReadmit := p.afterFunc(ejectionTime, func() {
+		p.readmit(server)
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The checker may have been stopped while the server was being ejected.
+	if p.stopped {
+		stopReadmit()
+		return
+	}
+
+	server.stopReadmit = stopReadmit
+}
+
+// readmit puts back the given ejected server in the load-balancer.
+func (p *PassiveHealthChecker) readmit(server *passiveServer) {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+
+	server.ejected = false
+	server.readmittedAt = p.timeNow()
+	server.stopReadmit = nil
+	p.ejected--
+	p.mu.Unlock()
+
+	log.Ctx(p.ctx).Info().Str("targetURL", server.target).Msg("Re-admitting server ejected by the passive health check.")
+
+	p.balancer.SetStatus(p.ctx, server.name, true)
+	p.info.UpdateServerStatus(server.target, runtime.StatusUp)
+	p.info.UpdateServerEjection(server.target, time.Time{})
+
+	p.metrics.ServiceServerUpGauge().
+		With("service", server.name, "url", server.target).
+		Set(1)
+}
+
+// maxEjected returns the maximum number of servers which can be ejected at the same time.
+// At least one server can always be ejected.
+func (p *PassiveHealthChecker) maxEjected() int {
+	return max(len(p.servers)*p.maxEjectionPercent/100, 1)
+}
@@ -0,0 +1,353 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestPassiveHealthChecker_ejection(t *testing.T) {
+	errDial := errors.New("dial tcp: connection refused")
+
+	testCases := []struct {
+		desc       string
+		config     dynamic.PassiveServerHealthCheck
+		responses  []fakeResponse
+		expEjected bool
+	}{
+		{
+			desc:      "successful responses",
+			config:    dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses: []fakeResponse{{code: http.StatusOK}, {code: http.StatusNotFound}, {code: http.StatusOK}},
+		},
+		{
+			desc:       "consecutive server errors",
+			config:     dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses:  []fakeResponse{{code: http.StatusBadGateway}, {code: http.StatusInternalServerError}},
+			expEjected: true,
+		},
+		{
+			desc:       "consecutive dial errors",
+			config:     dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses:  []fakeResponse{{err: errDial}, {err: errDial}},
+			expEjected: true,
+		},
+		{
+			desc:      "non consecutive server errors",
+			config:    dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses: []fakeResponse{{code: http.StatusBadGateway}, {code: http.StatusOK}, {code: http.StatusBadGateway}},
+		},
+		{
+			desc:       "consecutive slow responses",
+			config:     dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2, MaxLatency: ptypes.Duration(time.Second)},
+			responses:  []fakeResponse{{code: http.StatusOK, latency: 2 * time.Second}, {code: http.StatusOK, latency: 2 * time.Second}},
+			expEjected: true,
+		},
+		{
+			desc:      "slow responses without max latency",
+			config:    dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses: []fakeResponse{{code: http.StatusOK, latency: 2 * time.Second}, {code: http.StatusOK, latency: 2 * time.Second}},
+		},
+		{
+			desc:      "requests canceled by the client",
+			config:    dynamic.PassiveServerHealthCheck{ConsecutiveFailures: 2},
+			responses: []fakeResponse{{err: context.Canceled, canceled: true}, {err: context.Canceled, canceled: true}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, lb, info, metrics, clock := newTestPassiveHealthChecker(t, context.Background(), &test.config)
+
+			rt := newFakeRoundTripper(clock, test.responses...)
+			transport := checker.WrapRoundTripper("server", testhelpers.MustParseURL("http://127.0.0.1:8080"), rt)
+			checker.WrapRoundTripper("other", testhelpers.MustParseURL("http://127.0.0.1:8081"), rt)
+
+			for _, resp := range test.responses {
+				ctx, cancel := context.WithCancel(context.Background())
+				if resp.canceled {
+					cancel()
+				}
+
+				req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080", nil).WithContext(ctx)
+				_, _ = transport.RoundTrip(req)
+
+				cancel()
+			}
+
+			lb.Lock()
+			defer lb.Unlock()
+
+			if !test.expEjected {
+				assert.Equal(t, 0, lb.numRemovedServers)
+				assert.Nil(t, info.GetAllEjections())
+				assert.Zero(t, metrics.counter.CounterValue)
+				return
+			}
+
+			assert.Equal(t, 1, lb.numRemovedServers)
+			assert.Equal(t, map[string]string{"http://127.0.0.1:8080": runtime.StatusDown}, info.GetAllStatus())
+			assert.Contains(t, info.GetAllEjections(), "http://127.0.0.1:8080")
+			assert.InDelta(t, 0, metrics.gauge.GaugeValue, delta)
+			// The server up gauge is labeled as the one of the active health check.
+			assert.Equal(t, []string{"service", "server", "url", "http://127.0.0.1:8080"}, metrics.gauge.LastLabelValues)
+			assert.InDelta(t, 1, metrics.counter.CounterValue, delta)
+			assert.Equal(t, []string{"service", "test", "url", "http://127.0.0.1:8080"}, metrics.counter.LastLabelValues)
+		})
+	}
+}
+
+func TestPassiveHealthChecker_ejectionTime(t *testing.T) {
+	config := &dynamic.PassiveServerHealthCheck{
+		ConsecutiveFailures: 1,
+		BaseEjectionTime:    ptypes.Duration(10 * time.Second),
+		MaxEjectionTime:     ptypes.Duration(30 * time.Second),
+		MaxEjectionPercent:  100,
+	}
+
+	checker, lb, info, _, clock := newTestPassiveHealthChecker(t, context.Background(), config)
+
+	rt := newFakeRoundTripper(clock, fakeResponse{code: http.StatusBadGateway})
+	transport := checker.WrapRoundTripper("server", testhelpers.MustParseURL("http://127.0.0.1:8080"), rt)
+
+	fail := func() {
+		req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080", nil)
+		_, _ = transport.RoundTrip(req)
+	}
+
+	// The ejection time doubles at each consecutive ejection, up to the max ejection time.
+	for _, expected := range []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second} {
+		fail()
+		require.Len(t, clock.timers, 1)
+		assert.Equal(t, expected, clock.timers[0])
+
+		// Failures of an ejected server are ignored.
+		fail()
+		require.Len(t, clock.timers, 1)
+
+		clock.fire()
+		assert.Equal(t, map[string]string{"http://127.0.0.1:8080": runtime.StatusUp}, info.GetAllStatus())
+		assert.Nil(t, info.GetAllEjections())
+	}
+
+	// The ejection time is reset once the server stayed healthy for the max ejection time.
+	clock.now = clock.now.Add(31 * time.Second)
+
+	fail()
+	require.Len(t, clock.timers, 1)
+	assert.Equal(t, 10*time.Second, clock.timers[0])
+
+	lb.Lock()
+	defer lb.Unlock()
+
+	assert.Equal(t, 5, lb.numRemovedServers)
+	assert.Equal(t, 4, lb.numUpsertedServers)
+}
+
+func TestPassiveHealthChecker_stop(t *testing.T) {
+	config := &dynamic.PassiveServerHealthCheck{
+		ConsecutiveFailures: 1,
+		BaseEjectionTime:    ptypes.Duration(10 * time.Second),
+		MaxEjectionTime:     ptypes.Duration(30 * time.Second),
+		MaxEjectionPercent:  100,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	checker, lb, info, _, clock := newTestPassiveHealthChecker(t, ctx, config)
+
+	rt := newFakeRoundTripper(clock, fakeResponse{code: http.StatusBadGateway})
+	transport := checker.WrapRoundTripper("server", testhelpers.MustParseURL("http://127.0.0.1:8080"), rt)
+
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080", nil)
+	_, _ = transport.RoundTrip(req)
+	require.Len(t, clock.timers, 1)
+
+	// The configuration is reloaded while the server is ejected.
+	cancel()
+	require.Eventually(t, func() bool {
+		checker.mu.Lock()
+		defer checker.mu.Unlock()
+
+		return checker.stopped
+	}, time.Second, 10*time.Millisecond)
+
+	clock.fire()
+	assert.Equal(t, map[string]string{"http://127.0.0.1:8080": runtime.StatusDown}, info.GetAllStatus())
+
+	// Failures are not recorded anymore.
+	_, _ = transport.RoundTrip(req)
+	assert.Empty(t, clock.timers)
+
+	lb.Lock()
+	defer lb.Unlock()
+
+	assert.Equal(t, 1, lb.numRemovedServers)
+	assert.Equal(t, 0, lb.numUpsertedServers)
+}
+
+func TestPassiveHealthChecker_maxEjectionPercent(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		servers            int
+		maxEjectionPercent int
+		expEjected         int
+	}{
+		{
+			desc:               "half of the servers",
+			servers:            4,
+			maxEjectionPercent: 50,
+			expEjected:         2,
+		},
+		{
+			desc:               "at least one server",
+			servers:            4,
+			maxEjectionPercent: 10,
+			expEjected:         1,
+		},
+		{
+			desc:               "all servers",
+			servers:            4,
+			maxEjectionPercent: 100,
+			expEjected:         4,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &dynamic.PassiveServerHealthCheck{
+				ConsecutiveFailures: 1,
+				MaxEjectionPercent:  test.maxEjectionPercent,
+			}
+
+			checker, lb, _, _, clock := newTestPassiveHealthChecker(t, context.Background(), config)
+
+			rt := newFakeRoundTripper(clock, fakeResponse{code: http.StatusBadGateway})
+
+			var transports []http.RoundTripper
+			for i := 0; i < test.servers; i++ {
+				target := testhelpers.MustParseURL("http://127.0.0.1:808" + strconv.Itoa(i))
+				transports = append(transports, checker.WrapRoundTripper(target.Host, target, rt))
+			}
+
+			for _, transport := range transports {
+				req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+				_, _ = transport.RoundTrip(req)
+			}
+
+			lb.Lock()
+			defer lb.Unlock()
+
+			assert.Equal(t, test.expEjected, lb.numRemovedServers)
+		})
+	}
+}
+
+type fakeResponse struct {
+	code     int
+	err      error
+	latency  time.Duration
+	canceled bool
+}
+
+// fakeRoundTripper returns the given responses in order, the last one being repeated,
+// and advances the given clock by the response latency.
+type fakeRoundTripper struct {
+	clock     *fakeClock
+	responses []fakeResponse
+}
+
+func newFakeRoundTripper(clock *fakeClock, responses ...fakeResponse) *fakeRoundTripper {
+	return &fakeRoundTripper{clock: clock, responses: responses}
+}
+
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := f.responses[0]
+	if len(f.responses) > 1 {
+		f.responses = f.responses[1:]
+	}
+
+	f.clock.now = f.clock.now.Add(resp.latency)
+
+	if resp.err != nil {
+		return nil, resp.err
+	}
+
+	return &http.Response{StatusCode: resp.code, Request: req}, nil
+}
+
+type fakeClock struct {
+	now    time.Time
+	timers []time.Duration
+	funcs  []func()
+}
+
+// fire runs the functions of the pending timers.
+func (c *fakeClock) fire() {
+	funcs := c.funcs
+	c.timers, c.funcs = nil, nil
+
+	for _, f := range funcs {
+		f()
+	}
+}
+
+type passiveMetricsMock struct {
+	gauge   *testhelpers.CollectingGauge
+	counter *testhelpers.CollectingCounter
+}
+
+func (m *passiveMetricsMock) ServiceServerUpGauge() gokitmetrics.Gauge {
+	return m.gauge
+}
+
+func (m *passiveMetricsMock) ServiceServerEjectionsCounter() gokitmetrics.Counter {
+	return m.counter
+}
+
+func newTestPassiveHealthChecker(t *testing.T, ctx context.Context, config *dynamic.PassiveServerHealthCheck) (*PassiveHealthChecker, *testLoadBalancer, *runtime.ServiceInfo, *passiveMetricsMock, *fakeClock) {
+	t.Helper()
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	info := &runtime.ServiceInfo{}
+	metrics := &passiveMetricsMock{gauge: &testhelpers.CollectingGauge{}, counter: &testhelpers.CollectingCounter{}}
+
+	checker := NewPassiveHealthChecker(ctx, metrics, config, "test", lb, info)
+
+	clock := &fakeClock{now: time.Now()}
+	checker.timeNow = func() time.Time { return clock.now }
+	checker.afterFunc = func(d time.Duration, f func()) func() bool {
+		var stopped bool
+		clock.timers = append(clock.timers, d)
+		clock.funcs = append(clock.funcs, func() {
+			if !stopped {
+				f()
+			}
+		})
+
+		return func() bool {
+			stopped = true
+			return true
+		}
+	}
+
+	return checker, lb, info, metrics, clock
+}
@@ -0,0 +1,211 @@
+package ip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// Trie is a set of IPs and CIDR ranges, stored in a path-compressed binary prefix trie,
+// which allows to check whether an address belongs to the set in a time proportional to the length of the addresses,
+// whatever the number of ranges.
+// The IPv4 addresses and ranges are stored as IPv4-mapped IPv6 ones.
+// A Trie is not safe for concurrent modifications, but is safe for concurrent lookups once built.
+type Trie struct {
+	root trieNode
+	size int
+}
+
+// trieNode is a node of the trie, holding a prefix of the 128-bit addresses.
+type trieNode struct {
+	hi, lo   uint64 // bits of the prefix, masked to its length.
+	length   int    // length of the prefix, in bits.
+	terminal bool   // whether the prefix is a range of the set.
+	children [2]*trieNode
+}
+
+// NewTrie builds a new Trie given a list of IPs or CIDR ranges.
+func NewTrie(ipMasks []string) (*Trie, error) {
+	trie := &Trie{}
+
+	for _, ipMask := range ipMasks {
+		if err := trie.Insert(ipMask); err != nil {
+			return nil, err
+		}
+	}
+
+	return trie, nil
+}
+
+// Insert adds the given IP or CIDR range to the set.
+func (t *Trie) Insert(ipMask string) error {
+	prefix, err := parsePrefix(ipMask)
+	if err != nil {
+		return err
+	}
+
+	t.InsertPrefix(prefix)
+
+	return nil
+}
+
+// InsertPrefix adds the given range to the set.
+func (t *Trie) InsertPrefix(prefix netip.Prefix) {
+	hi, lo, length := prefixKey(prefix)
+
+	t.size++
+
+	node := &t.root
+	for {
+		if node.length == length {
+			node.terminal = true
+			return
+		}
+
+		branch := bitAt(hi, lo, node.length)
+
+		child := node.children[branch]
+		if child == nil {
+			node.children[branch] = newTrieNode(hi, lo, length, true)
+			return
+		}
+
+		common := min(length, child.length, commonLength(hi, lo, child.hi, child.lo))
+		if common == child.length {
+			node = child
+			continue
+		}
+
+		// Splits the edge to the child at the end of the common prefix.
+		split := newTrieNode(hi, lo, common, common == length)
+		split.children[bitAt(child.hi, child.lo, common)] = child
+		if common < length {
+			split.children[bitAt(hi, lo, common)] = newTrieNode(hi, lo, length, true)
+		}
+
+		node.children[branch] = split
+		return
+	}
+}
+
+// Len returns the number of ranges inserted in the set.
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Contains checks if provided address is in the set.
+func (t *Trie) Contains(addr string) (bool, error) {
+	if len(addr) == 0 {
+		return false, errors.New("empty IP address")
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ipAddr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse address: %s: %w", addr, err)
+	}
+
+	return t.ContainsAddr(ipAddr), nil
+}
+
+// ContainsAddr checks if provided address is in the set.
+func (t *Trie) ContainsAddr(addr netip.Addr) bool {
+	bytes := addr.WithZone("").As16()
+	hi := binary.BigEndian.Uint64(bytes[:8])
+	lo := binary.BigEndian.Uint64(bytes[8:])
+
+	node := &t.root
+	for {
+		if node.terminal {
+			return true
+		}
+
+		if node.length == 128 {
+			return false
+		}
+
+		node = node.children[bitAt(hi, lo, node.length)]
+		if node == nil || commonLength(hi, lo, node.hi, node.lo) < node.length {
+			return false
+		}
+	}
+}
+
+func newTrieNode(hi, lo uint64, length int, terminal bool) *trieNode {
+	maskedHi, maskedLo := mask(hi, lo, length)
+
+	return &trieNode{hi: maskedHi, lo: maskedLo, length: length, terminal: terminal}
+}
+
+// parsePrefix parses an IP or a CIDR range.
+func parsePrefix(ipMask string) (netip.Prefix, error) {
+	if !strings.Contains(ipMask, "/") {
+		addr, err := netip.ParseAddr(ipMask)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("parsing IP %s: %w", ipMask, err)
+		}
+
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(ipMask)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("parsing CIDR %s: %w", ipMask, err)
+	}
+
+	return prefix, nil
+}
+
+// prefixKey returns the 128 bits and the length of the given prefix, as an IPv6 one.
+func prefixKey(prefix netip.Prefix) (uint64, uint64, int) {
+	length := prefix.Bits()
+	if prefix.Addr().Is4() {
+		length += 96
+	}
+
+	bytes := prefix.Addr().As16()
+
+	return binary.BigEndian.Uint64(bytes[:8]), binary.BigEndian.Uint64(bytes[8:]), length
+}
+
+// bitAt returns the bit of the given 128-bit key at the given index, starting from the most significant one.
+func bitAt(hi, lo uint64, index int) int {
+	if index < 64 {
+		return int(hi>>(63-index)) & 1
+	}
+
+	return int(lo>>(127-index)) & 1
+}
+
+// commonLength returns the length of the common prefix of the given 128-bit keys.
+func commonLength(hi1, lo1, hi2, lo2 uint64) int {
+	if diff := hi1 ^ hi2; diff != 0 {
+		return bits.LeadingZeros64(diff)
+	}
+
+	return 64 + bits.LeadingZeros64(lo1^lo2)
+}
+
+// mask keeps the first length bits of the given 128-bit key.
+func mask(hi, lo uint64, length int) (uint64, uint64) {
+	switch {
+	case length == 0:
+		return 0, 0
+	case length < 64:
+		return hi & ^(^uint64(0) >> length), 0
+	case length == 64:
+		return hi, 0
+	case length < 128:
+		return hi, lo & ^(^uint64(0) >> (length - 64))
+	default:
+		return hi, lo
+	}
+}
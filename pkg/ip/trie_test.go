@@ -0,0 +1,154 @@
+package ip
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrie_Contains(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ranges   []string
+		addr     string
+		expected bool
+	}{
+		{
+			desc:     "IP in range",
+			ranges:   []string{"1.2.3.4/24"},
+			addr:     "1.2.3.1",
+			expected: true,
+		},
+		{
+			desc:     "IP with port in range",
+			ranges:   []string{"1.2.3.4/24"},
+			addr:     "1.2.3.1:123",
+			expected: true,
+		},
+		{
+			desc:   "IP not in range",
+			ranges: []string{"1.2.3.4/24"},
+			addr:   "10.2.3.1",
+		},
+		{
+			desc:     "single IP",
+			ranges:   []string{"10.0.0.1"},
+			addr:     "10.0.0.1",
+			expected: true,
+		},
+		{
+			desc:   "next to single IP",
+			ranges: []string{"10.0.0.1"},
+			addr:   "10.0.0.2",
+		},
+		{
+			desc:     "nested ranges",
+			ranges:   []string{"10.1.2.0/24", "10.0.0.0/8", "10.1.0.0/16"},
+			addr:     "10.200.0.1",
+			expected: true,
+		},
+		{
+			desc:     "sibling ranges",
+			ranges:   []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.3.0/24"},
+			addr:     "10.0.3.255",
+			expected: true,
+		},
+		{
+			desc:   "between sibling ranges",
+			ranges: []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.3.0/24"},
+			addr:   "10.0.2.1",
+		},
+		{
+			desc:     "all IPv4",
+			ranges:   []string{"0.0.0.0/0"},
+			addr:     "192.168.1.1",
+			expected: true,
+		},
+		{
+			desc:   "all IPv4 does not contain IPv6",
+			ranges: []string{"0.0.0.0/0"},
+			addr:   "2001:db8::1",
+		},
+		{
+			desc:     "IPv6 in range",
+			ranges:   []string{"2001:db8::/32"},
+			addr:     "[2001:db8::1]:443",
+			expected: true,
+		},
+		{
+			desc:   "IPv6 not in range",
+			ranges: []string{"2001:db8::/32"},
+			addr:   "2001:db9::1",
+		},
+		{
+			desc:     "IPv4-mapped IPv6 in IPv4 range",
+			ranges:   []string{"1.2.3.0/24"},
+			addr:     "::ffff:1.2.3.4",
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			trie, err := NewTrie(test.ranges)
+			require.NoError(t, err)
+
+			contains, err := trie.Contains(test.addr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, contains)
+		})
+	}
+}
+
+func TestTrie_invalid(t *testing.T) {
+	_, err := NewTrie([]string{"1.2.3.4/33"})
+	require.Error(t, err)
+
+	_, err = NewTrie([]string{"foo"})
+	require.Error(t, err)
+
+	trie, err := NewTrie(nil)
+	require.NoError(t, err)
+
+	_, err = trie.Contains("")
+	require.Error(t, err)
+
+	_, err = trie.Contains("0127.2.3.1")
+	require.Error(t, err)
+}
+
+func TestTrie_sameAsChecker(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+
+	var ranges []string
+	for range 1000 {
+		ranges = append(ranges, fmt.Sprintf("%d.%d.%d.%d/%d", random.Intn(256), random.Intn(256), random.Intn(256), random.Intn(256), 8+random.Intn(25)))
+	}
+
+	trie, err := NewTrie(ranges)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, trie.Len())
+
+	checker, err := NewChecker(ranges)
+	require.NoError(t, err)
+
+	for range 10000 {
+		addr := net.IPv4(byte(random.Intn(256)), byte(random.Intn(256)), byte(random.Intn(256)), byte(random.Intn(256))).String()
+
+		expected, err := checker.Contains(addr)
+		require.NoError(t, err)
+
+		contains, err := trie.Contains(addr)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, contains, addr)
+	}
+}
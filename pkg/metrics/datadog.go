@@ -39,13 +39,17 @@ const (
 	ddRouterReqsBytesName    = "router.requests.bytes.total"
 	ddRouterRespsBytesName   = "router.responses.bytes.total"
 
-	ddServiceReqsName         = "service.request.total"
-	ddServiceReqsTLSName      = "service.request.tls.total"
-	ddServiceReqsDurationName = "service.request.duration"
-	ddServiceRetriesName      = "service.retries.total"
-	ddServiceServerUpName     = "service.server.up"
-	ddServiceReqsBytesName    = "service.requests.bytes.total"
-	ddServiceRespsBytesName   = "service.responses.bytes.total"
+	ddServiceReqsName            = "service.request.total"
+	ddServiceReqsTLSName         = "service.request.tls.total"
+	ddServiceReqsDurationName    = "service.request.duration"
+	ddServiceRetriesName         = "service.retries.total"
+	ddServiceServerUpName        = "service.server.up"
+	ddServiceServerEjectionsName = "service.server.ejections.total"
+	ddServiceReqsBytesName       = "service.requests.bytes.total"
+	ddServiceRespsBytesName      = "service.responses.bytes.total"
+
+	ddRateLimitRejectionsName    = "middleware.ratelimit.rejections.total"
+	ddTCPRateLimitRejectionsName = "middleware.tcp.ratelimit.rejections.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -67,6 +71,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		openConnectionsGauge:           datadogClient.NewGauge(ddOpenConnsName),
 		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		rateLimitRejectionsCounter:     datadogClient.NewCounter(ddRateLimitRejectionsName, 1.0),
+		tcpRateLimitRejectionsCounter:  datadogClient.NewCounter(ddTCPRateLimitRejectionsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -94,6 +100,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddServiceReqsDurationName, 1.0), time.Second)
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddServiceRetriesName, 1.0)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServiceServerUpName)
+		registry.serviceServerEjectionsCounter = datadogClient.NewCounter(ddServiceServerEjectionsName, 1.0)
 		registry.serviceReqsBytesCounter = datadogClient.NewCounter(ddServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = datadogClient.NewCounter(ddServiceRespsBytesName, 1.0)
 	}
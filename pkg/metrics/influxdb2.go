@@ -42,13 +42,17 @@ const (
 	influxDBRouterReqsBytesName    = "traefik.router.requests.bytes.total"
 	influxDBRouterRespsBytesName   = "traefik.router.responses.bytes.total"
 
-	influxDBServiceReqsName         = "traefik.service.requests.total"
-	influxDBServiceReqsTLSName      = "traefik.service.requests.tls.total"
-	influxDBServiceReqsDurationName = "traefik.service.request.duration"
-	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
-	influxDBServiceServerUpName     = "traefik.service.server.up"
-	influxDBServiceReqsBytesName    = "traefik.service.requests.bytes.total"
-	influxDBServiceRespsBytesName   = "traefik.service.responses.bytes.total"
+	influxDBServiceReqsName            = "traefik.service.requests.total"
+	influxDBServiceReqsTLSName         = "traefik.service.requests.tls.total"
+	influxDBServiceReqsDurationName    = "traefik.service.request.duration"
+	influxDBServiceRetriesTotalName    = "traefik.service.retries.total"
+	influxDBServiceServerUpName        = "traefik.service.server.up"
+	influxDBServiceServerEjectionsName = "traefik.service.server.ejections.total"
+	influxDBServiceReqsBytesName       = "traefik.service.requests.bytes.total"
+	influxDBServiceRespsBytesName      = "traefik.service.responses.bytes.total"
+
+	influxDBRateLimitRejectionsName    = "traefik.middleware.ratelimit.rejections.total"
+	influxDBTCPRateLimitRejectionsName = "traefik.middleware.tcp.ratelimit.rejections.total"
 )
 
 // RegisterInfluxDB2 creates metrics exporter for InfluxDB2.
@@ -83,6 +87,8 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		lastConfigReloadSuccessGauge:   influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
 		openConnectionsGauge:           influxDB2Store.NewGauge(influxDBOpenConnsName),
 		tlsCertsNotAfterTimestampGauge: influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		rateLimitRejectionsCounter:     influxDB2Store.NewCounter(influxDBRateLimitRejectionsName),
+		tcpRateLimitRejectionsCounter:  influxDB2Store.NewCounter(influxDBTCPRateLimitRejectionsName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -110,6 +116,7 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(influxDB2Store.NewHistogram(influxDBServiceReqsDurationName), time.Second)
 		registry.serviceRetriesCounter = influxDB2Store.NewCounter(influxDBServiceRetriesTotalName)
 		registry.serviceServerUpGauge = influxDB2Store.NewGauge(influxDBServiceServerUpName)
+		registry.serviceServerEjectionsCounter = influxDB2Store.NewCounter(influxDBServiceServerEjectionsName)
 		registry.serviceReqsBytesCounter = influxDB2Store.NewCounter(influxDBServiceReqsBytesName)
 		registry.serviceRespsBytesCounter = influxDB2Store.NewCounter(influxDBServiceRespsBytesName)
 	}
@@ -52,8 +52,14 @@ type Registry interface {
 	ServiceReqDurationHistogram() ScalableHistogram
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceServerEjectionsCounter() metrics.Counter
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+
+	// middleware metrics
+
+	RateLimitRejectionsCounter() metrics.Counter
+	TCPRateLimitRejectionsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -85,8 +91,11 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceServerEjectionsCounter []metrics.Counter
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var rateLimitRejectionsCounter []metrics.Counter
+	var tcpRateLimitRejectionsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -146,12 +155,21 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceServerEjectionsCounter() != nil {
+			serviceServerEjectionsCounter = append(serviceServerEjectionsCounter, r.ServiceServerEjectionsCounter())
+		}
 		if r.ServiceReqsBytesCounter() != nil {
 			serviceReqsBytesCounter = append(serviceReqsBytesCounter, r.ServiceReqsBytesCounter())
 		}
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.RateLimitRejectionsCounter() != nil {
+			rateLimitRejectionsCounter = append(rateLimitRejectionsCounter, r.RateLimitRejectionsCounter())
+		}
+		if r.TCPRateLimitRejectionsCounter() != nil {
+			tcpRateLimitRejectionsCounter = append(tcpRateLimitRejectionsCounter, r.TCPRateLimitRejectionsCounter())
+		}
 	}
 
 	return &standardRegistry{
@@ -177,8 +195,11 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceReqDurationHistogram:    MultiHistogram(serviceReqDurationHistogram),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		serviceServerEjectionsCounter:  multi.NewCounter(serviceServerEjectionsCounter...),
 		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
 		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+		rateLimitRejectionsCounter:     multi.NewCounter(rateLimitRejectionsCounter...),
+		tcpRateLimitRejectionsCounter:  multi.NewCounter(tcpRateLimitRejectionsCounter...),
 	}
 }
 
@@ -205,8 +226,11 @@ type standardRegistry struct {
 	serviceReqDurationHistogram    ScalableHistogram
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	serviceServerEjectionsCounter  metrics.Counter
 	serviceReqsBytesCounter        metrics.Counter
 	serviceRespsBytesCounter       metrics.Counter
+	rateLimitRejectionsCounter     metrics.Counter
+	tcpRateLimitRejectionsCounter  metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -297,6 +321,10 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceServerEjectionsCounter() metrics.Counter {
+	return r.serviceServerEjectionsCounter
+}
+
 func (r *standardRegistry) ServiceReqsBytesCounter() metrics.Counter {
 	return r.serviceReqsBytesCounter
 }
@@ -305,6 +333,14 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) RateLimitRejectionsCounter() metrics.Counter {
+	return r.rateLimitRejectionsCounter
+}
+
+func (r *standardRegistry) TCPRateLimitRejectionsCounter() metrics.Counter {
+	return r.tcpRateLimitRejectionsCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
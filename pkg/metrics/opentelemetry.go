@@ -55,6 +55,8 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OTLP) Registry {
 		lastConfigReloadSuccessGauge:   newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", "ms"),
 		openConnectionsGauge:           newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", "1"),
 		tlsCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", "ms"),
+		rateLimitRejectionsCounter:     newOTLPCounterFrom(meter, rateLimitRejectionsTotalName, "How many requests were rejected by a rate limit middleware."),
+		tcpRateLimitRejectionsCounter:  newOTLPCounterFrom(meter, tcpRateLimitRejectionsTotalName, "How many connections were rejected by a TCP rate limit middleware."),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -98,6 +100,8 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OTLP) Registry {
 		reg.serviceServerUpGauge = newOTLPGaugeFrom(meter, serviceServerUpName,
 			"service server is up, described by gauge value of 0 or 1.",
 			"1")
+		reg.serviceServerEjectionsCounter = newOTLPCounterFrom(meter, serviceServerEjectionsName,
+			"How many times a service server has been ejected by the passive health check.")
 		reg.serviceReqsBytesCounter = newOTLPCounterFrom(meter, serviceReqsBytesTotalName,
 			"The total size of requests in bytes received by a service, partitioned by status code, protocol, and method.")
 		reg.serviceRespsBytesCounter = newOTLPCounterFrom(meter, serviceRespsBytesTotalName,
@@ -53,8 +53,14 @@ const (
 	serviceReqDurationName     = metricServicePrefix + "request_duration_seconds"
 	serviceRetriesTotalName    = metricServicePrefix + "retries_total"
 	serviceServerUpName        = metricServicePrefix + "server_up"
+	serviceServerEjectionsName = metricServicePrefix + "server_ejections_total"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+
+	// middleware level.
+	metricMiddlewarePrefix          = MetricNamePrefix + "middleware_"
+	rateLimitRejectionsTotalName    = metricMiddlewarePrefix + "ratelimit_rejections_total"
+	tcpRateLimitRejectionsTotalName = metricMiddlewarePrefix + "tcp_ratelimit_rejections_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -126,12 +132,22 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: openConnectionsName,
 		Help: "How many open connections exist, by entryPoint and protocol",
 	}, []string{"entrypoint", "protocol"})
+	rateLimitRejections := newCounterFrom(stdprometheus.CounterOpts{
+		Name: rateLimitRejectionsTotalName,
+		Help: "How many requests were rejected by a rate limit middleware.",
+	}, []string{"middleware"})
+	tcpRateLimitRejections := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tcpRateLimitRejectionsTotalName,
+		Help: "How many connections were rejected by a TCP rate limit middleware.",
+	}, []string{"middleware"})
 
 	promState.vectors = []vector{
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
 		openConnections.gv,
+		rateLimitRejections.cv,
+		tcpRateLimitRejections.cv,
 	}
 
 	reg := &standardRegistry{
@@ -142,6 +158,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
 		openConnectionsGauge:           openConnections,
+		rateLimitRejectionsCounter:     rateLimitRejections,
+		tcpRateLimitRejectionsCounter:  tcpRateLimitRejections,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -241,6 +259,10 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceServerEjections := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceServerEjectionsName,
+			Help: "How many times a service server has been ejected by the passive health check.",
+		}, []string{"service", "url"})
 		serviceReqsBytesTotal := newCounterFrom(stdprometheus.CounterOpts{
 			Name: serviceReqsBytesTotalName,
 			Help: "The total size of requests in bytes received by a service, partitioned by status code, protocol, and method.",
@@ -256,6 +278,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceReqDurations.hv,
 			serviceRetries.cv,
 			serviceServerUp.gv,
+			serviceServerEjections.cv,
 			serviceReqsBytesTotal.cv,
 			serviceRespsBytesTotal.cv,
 		)
@@ -265,6 +288,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceReqDurationHistogram, _ = NewHistogramWithScale(serviceReqDurations, time.Second)
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceServerEjectionsCounter = serviceServerEjections
 		reg.serviceReqsBytesCounter = serviceReqsBytesTotal
 		reg.serviceRespsBytesCounter = serviceRespsBytesTotal
 	}
@@ -174,6 +174,10 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceServerEjectionsCounter().
+		With("service", "service1", "url", "http://127.0.0.10:80").
+		Add(1)
 	prometheusRegistry.
 		ServiceRespsBytesCounter().
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
@@ -182,6 +186,14 @@ func TestPrometheus(t *testing.T) {
 		ServiceReqsBytesCounter().
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		RateLimitRejectionsCounter().
+		With("middleware", "ratelimit1").
+		Add(1)
+	prometheusRegistry.
+		TCPRateLimitRejectionsCounter().
+		With("middleware", "tcpratelimit1").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -358,6 +370,14 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceServerEjectionsName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+			},
+			assert: buildCounterAssert(t, serviceServerEjectionsName, 1),
+		},
 		{
 			name: serviceReqsBytesTotalName,
 			labels: map[string]string{
@@ -378,6 +398,20 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildCounterAssert(t, serviceRespsBytesTotalName, 1),
 		},
+		{
+			name: rateLimitRejectionsTotalName,
+			labels: map[string]string{
+				"middleware": "ratelimit1",
+			},
+			assert: buildCounterAssert(t, rateLimitRejectionsTotalName, 1),
+		},
+		{
+			name: tcpRateLimitRejectionsTotalName,
+			labels: map[string]string{
+				"middleware": "tcpratelimit1",
+			},
+			assert: buildCounterAssert(t, tcpRateLimitRejectionsTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -35,13 +35,17 @@ const (
 	statsdRouterReqsBytesName    = "router.requests.bytes.total"
 	statsdRouterRespsBytesName   = "router.responses.bytes.total"
 
-	statsdServiceReqsName         = "service.request.total"
-	statsdServiceReqsTLSName      = "service.request.tls.total"
-	statsdServiceReqsDurationName = "service.request.duration"
-	statsdServiceRetriesTotalName = "service.retries.total"
-	statsdServiceServerUpName     = "service.server.up"
-	statsdServiceReqsBytesName    = "service.requests.bytes.total"
-	statsdServiceRespsBytesName   = "service.responses.bytes.total"
+	statsdServiceReqsName            = "service.request.total"
+	statsdServiceReqsTLSName         = "service.request.tls.total"
+	statsdServiceReqsDurationName    = "service.request.duration"
+	statsdServiceRetriesTotalName    = "service.retries.total"
+	statsdServiceServerUpName        = "service.server.up"
+	statsdServiceServerEjectionsName = "service.server.ejections.total"
+	statsdServiceReqsBytesName       = "service.requests.bytes.total"
+	statsdServiceRespsBytesName      = "service.responses.bytes.total"
+
+	statsdRateLimitRejectionsName    = "middleware.ratelimit.rejections.total"
+	statsdTCPRateLimitRejectionsName = "middleware.tcp.ratelimit.rejections.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -62,6 +66,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
 		openConnectionsGauge:           statsdClient.NewGauge(statsdOpenConnectionsName),
+		rateLimitRejectionsCounter:     statsdClient.NewCounter(statsdRateLimitRejectionsName, 1.0),
+		tcpRateLimitRejectionsCounter:  statsdClient.NewCounter(statsdTCPRateLimitRejectionsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -89,6 +95,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdServiceReqsDurationName, 1.0), time.Millisecond)
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
+		registry.serviceServerEjectionsCounter = statsdClient.NewCounter(statsdServiceServerEjectionsName, 1.0)
 		registry.serviceReqsBytesCounter = statsdClient.NewCounter(statsdServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = statsdClient.NewCounter(statsdServiceRespsBytesName, 1.0)
 	}
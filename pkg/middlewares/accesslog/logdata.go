@@ -70,6 +70,8 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// WAFMatchedRules is the map key used for the IDs of the WAF rules matched by the request.
+	WAFMatchedRules = "WAFMatchedRules"
 
 	// TLSVersion is the version of TLS used in the request.
 	TLSVersion = "TLSVersion"
@@ -77,6 +79,17 @@ const (
 	TLSCipher = "TLSCipher"
 	// TLSClientSubject is the string representation of the TLS client certificate's Subject.
 	TLSClientSubject = "TLSClientSubject"
+
+	// ProxyProtocolAuthority is the host name sent in the PROXY protocol header received by the entry point.
+	ProxyProtocolAuthority = "ProxyProtocolAuthority"
+	// ProxyProtocolALPN is the application protocol sent in the PROXY protocol header received by the entry point.
+	ProxyProtocolALPN = "ProxyProtocolALPN"
+	// ProxyProtocolTLSVersion is the TLS version sent in the PROXY protocol header received by the entry point.
+	ProxyProtocolTLSVersion = "ProxyProtocolTLSVersion"
+	// ProxyProtocolTLSCipher is the TLS cipher sent in the PROXY protocol header received by the entry point.
+	ProxyProtocolTLSCipher = "ProxyProtocolTLSCipher"
+	// ProxyProtocolTLSClientCN is the client certificate common name sent in the PROXY protocol header received by the entry point.
+	ProxyProtocolTLSClientCN = "ProxyProtocolTLSClientCN"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -118,9 +131,15 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[WAFMatchedRules] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
 	allCoreKeys[TLSClientSubject] = struct{}{}
+	allCoreKeys[ProxyProtocolAuthority] = struct{}{}
+	allCoreKeys[ProxyProtocolALPN] = struct{}{}
+	allCoreKeys[ProxyProtocolTLSVersion] = struct{}{}
+	allCoreKeys[ProxyProtocolTLSCipher] = struct{}{}
+	allCoreKeys[ProxyProtocolTLSClientCN] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -21,6 +21,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
@@ -224,6 +225,14 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		}
 	}
 
+	if info, ok := tcp.ProxyProtocolInfoFromContext(req.Context()); ok {
+		setNonEmpty(core, ProxyProtocolAuthority, info.Authority)
+		setNonEmpty(core, ProxyProtocolALPN, info.ALPN)
+		setNonEmpty(core, ProxyProtocolTLSVersion, info.TLSVersion)
+		setNonEmpty(core, ProxyProtocolTLSCipher, info.TLSCipher)
+		setNonEmpty(core, ProxyProtocolTLSClientCN, info.TLSClientCN)
+	}
+
 	core[ClientAddr] = req.RemoteAddr
 	core[ClientHost], core[ClientPort] = silentSplitHostPort(req.RemoteAddr)
 
@@ -298,6 +307,12 @@ func usernameIfPresent(theURL *url.URL) string {
 	return "-"
 }
 
+func setNonEmpty(core CoreLogData, key, value string) {
+	if value != "" {
+		core[key] = value
+	}
+}
+
 // Logging handler to log frontend name, backend name, and elapsed time.
 func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 	core := logDataTable.Core
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
@@ -498,6 +499,45 @@ func TestLoggerJSON(t *testing.T) {
 	}
 }
 
+func TestLoggerJSON_proxyProtocolInfo(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+
+	logger, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := logger.Close()
+		require.NoError(t, err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.localhost/", nil)
+	req = req.WithContext(tcp.AddProxyProtocolInfoOnContext(req.Context(), tcp.ProxyProtocolInfo{
+		Authority:  "foo.localhost",
+		ALPN:       "h2",
+		TLSVersion: "TLSv1.3",
+	}))
+
+	chain := alice.New()
+	chain = chain.Append(capture.Wrap)
+	chain = chain.Append(WrapHandler(logger))
+	handler, err := chain.Then(http.HandlerFunc(logWriterTestHandlerFunc))
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	jsonData := make(map[string]interface{})
+	err = json.Unmarshal(logData, &jsonData)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo.localhost", jsonData[ProxyProtocolAuthority])
+	assert.Equal(t, "h2", jsonData[ProxyProtocolALPN])
+	assert.Equal(t, "TLSv1.3", jsonData[ProxyProtocolTLSVersion])
+	assert.NotContains(t, jsonData, ProxyProtocolTLSCipher)
+	assert.NotContains(t, jsonData, ProxyProtocolTLSClientCN)
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	testCases := []struct {
 		desc        string
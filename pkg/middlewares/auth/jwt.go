@@ -0,0 +1,558 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+	"github.com/traefik/traefik/v3/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeNameJWT = "JWT"
+
+const (
+	// jwksMinRefreshInterval is the minimum interval between two fetches of the JSON Web Key Set
+	// triggered by a token signed with an unknown key, or following a failed fetch.
+	jwksMinRefreshInterval = 10 * time.Second
+	// jwksMaxSize is the maximum size of a JSON Web Key Set document.
+	jwksMaxSize = 1 << 20
+)
+
+var (
+	errInvalidAudience = errors.New("validation failed, invalid audience claim (aud)")
+	errMissingExpiry   = errors.New("validation failed, missing expiration claim (exp)")
+)
+
+type jwtAuth struct {
+	next           http.Handler
+	name           string
+	keys           []interface{}
+	jwks           *jwksCache
+	issuer         string
+	audiences      []string
+	clockSkew      time.Duration
+	claimRules     []dynamic.JWTClaimRule
+	forwardHeaders map[string]string
+	removeHeader   bool
+
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
+}
+
+// NewJWT creates a JWT middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeNameJWT).Debug().Msg("Creating middleware")
+
+	if len(config.PublicKeys) == 0 && config.Secret == "" && config.JWKSURL == "" {
+		return nil, errors.New("one of publicKeys, secret or jwksUrl must be set")
+	}
+
+	ja := &jwtAuth{
+		next:           next,
+		name:           name,
+		issuer:         config.Issuer,
+		audiences:      config.Audiences,
+		clockSkew:      time.Duration(config.ClockSkew),
+		claimRules:     config.ClaimRules,
+		forwardHeaders: config.ForwardHeaders,
+		removeHeader:   config.RemoveHeader,
+		timeNow:        time.Now,
+	}
+
+	for _, rule := range config.ClaimRules {
+		if rule.Claim == "" {
+			return nil, errors.New("claim rules must define a claim")
+		}
+	}
+
+	for i, publicKey := range config.PublicKeys {
+		key, err := parsePublicKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key %d: %w", i, err)
+		}
+
+		ja.keys = append(ja.keys, key)
+	}
+
+	if config.Secret != "" {
+		ja.keys = append(ja.keys, []byte(config.Secret))
+	}
+
+	if config.JWKSURL != "" {
+		ja.jwks = newJWKSCache(config.JWKSURL, time.Duration(config.JWKSRefreshInterval))
+
+		// Fetches the key set ahead of the first request.
+		safe.Go(func() {
+			if _, err := ja.jwks.refresh(context.Background(), time.Time{}); err != nil {
+				middlewares.GetLogger(ctx, name, typeNameJWT).Error().Err(err).Msg("Unable to fetch the JSON Web Key Set")
+			}
+		})
+	}
+
+	return ja, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, string, trace.SpanKind) {
+	return j.name, typeNameJWT, trace.SpanKindInternal
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), j.name, typeNameJWT)
+
+	token, ok := bearerToken(req)
+	if !ok {
+		logger.Debug().Msg("Authentication failed: missing bearer token")
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.validate(req.Context(), token)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Authentication failed")
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if sub, ok := claims["sub"].(string); ok {
+		logData := accesslog.GetLogData(req)
+		if logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	for _, rule := range j.claimRules {
+		if !matchClaimRule(claims, rule) {
+			logger.Debug().Msgf("Authorization failed: claim %q does not satisfy the rule", rule.Claim)
+			tracing.SetStatusErrorf(req.Context(), "Authorization failed")
+
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	logger.Debug().Msg("Authentication succeeded")
+
+	for headerName, claim := range j.forwardHeaders {
+		value, ok := lookupClaim(claims, claim)
+		if !ok {
+			req.Header.Del(headerName)
+			continue
+		}
+
+		req.Header.Set(headerName, claimHeaderValue(value))
+	}
+
+	if j.removeHeader {
+		logger.Debug().Msg("Removing authorization header")
+		req.Header.Del(authorizationHeader)
+	}
+
+	j.next.ServeHTTP(rw, req)
+}
+
+// validate verifies the signature and the registered claims of the given token, and returns its claims.
+func (j *jwtAuth) validate(ctx context.Context, rawToken string) (map[string]interface{}, error) {
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered jwt.Claims
+	var claims map[string]interface{}
+	if err = verifySignature(ctx, token, j.keys, j.jwks, &registered, &claims); err != nil {
+		return nil, err
+	}
+
+	// The tokens without expiration would be valid forever.
+	if registered.Expiry == nil {
+		return nil, errMissingExpiry
+	}
+
+	expected := jwt.Expected{Issuer: j.issuer, Time: j.timeNow()}
+	if err = registered.ValidateWithLeeway(expected, j.clockSkew); err != nil {
+		return nil, err
+	}
+
+	if len(j.audiences) > 0 && !containsAudience(registered.Audience, j.audiences) {
+		return nil, errInvalidAudience
+	}
+
+	return claims, nil
+}
+
+// verifySignature verifies the signature of the token against the given keys, then against the keys of the given key set if any,
+// and decodes its claims into dest.
+func verifySignature(ctx context.Context, token *jwt.JSONWebToken, staticKeys []interface{}, jwks *jwksCache, dest ...interface{}) error {
+	for _, key := range staticKeys {
+		if token.Claims(key, dest...) == nil {
+			return nil
+		}
+	}
+
+	if jwks == nil {
+		return errors.New("no key matches the token signature")
+	}
+
+	var kid string
+	if len(token.Headers) > 0 {
+		kid = token.Headers[0].KeyID
+	}
+
+	keySet, err := jwks.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyWithKeySet(token, keySet.keys, kid, dest...); err == nil {
+		return nil
+	}
+
+	// The token might be signed with a key rotated in since the last fetch of the key set.
+	keySet, err = jwks.refreshUnknownKey(ctx, keySet.fetchedAt)
+	if err != nil {
+		return err
+	}
+
+	return verifyWithKeySet(token, keySet.keys, kid, dest...)
+}
+
+func verifyWithKeySet(token *jwt.JSONWebToken, keySet jose.JSONWebKeySet, kid string, dest ...interface{}) error {
+	keys := keySet.Keys
+	if kid != "" {
+		keys = keySet.Key(kid)
+	}
+
+	for _, key := range keys {
+		if token.Claims(key.Key, dest...) == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no key of the key set matches the token signature (kid: %q)", kid)
+}
+
+func containsAudience(audience jwt.Audience, accepted []string) bool {
+	for _, aud := range accepted {
+		if audience.Contains(aud) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Header.Get(authorizationHeader), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+// matchClaimRule returns whether the claims satisfy the given rule.
+func matchClaimRule(claims map[string]interface{}, rule dynamic.JWTClaimRule) bool {
+	value, ok := lookupClaim(claims, rule.Claim)
+	if !ok {
+		return false
+	}
+
+	if len(rule.Values) == 0 {
+		return true
+	}
+
+	values := []interface{}{value}
+	if elements, isArray := value.([]interface{}); isArray {
+		values = elements
+	}
+
+	for _, v := range values {
+		s, ok := claimString(v)
+		if !ok {
+			continue
+		}
+
+		for _, accepted := range rule.Values {
+			if s == accepted {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lookupClaim returns the value of the claim with the given name.
+// A claim which is not found by its full name is looked up as a dot-separated path of nested claims.
+func lookupClaim(claims map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := claims[name]; ok {
+		return value, true
+	}
+
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = object[part]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// claimString returns the string representation of a scalar claim value.
+func claimString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
+
+// claimHeaderValue returns the header value of the given claim value:
+// scalars as is, arrays of scalars as a comma-separated list, and anything else as JSON.
+func claimHeaderValue(value interface{}) string {
+	if s, ok := claimString(value); ok {
+		return s
+	}
+
+	if elements, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(elements))
+		for _, element := range elements {
+			s, ok := claimString(element)
+			if !ok {
+				values = nil
+				break
+			}
+
+			values = append(values, s)
+		}
+
+		if values != nil {
+			return strings.Join(values, ",")
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(raw)
+}
+
+// parsePublicKey parses a PEM-encoded public key or certificate, given either as content or as a file path.
+func parsePublicKey(publicKey string) (interface{}, error) {
+	content, err := types.FileOrContent(publicKey).Read()
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return cert.PublicKey, nil
+
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+type jwksKeySet struct {
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// jwksCache caches a JSON Web Key Set.
+// A stale key set is still served while it is refreshed in the background.
+type jwksCache struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu     sync.RWMutex
+	keySet *jwksKeySet
+	// lastAttempt and lastErr hold the time and the error of the last fetch of the key set.
+	lastAttempt time.Time
+	lastErr     error
+	// lastUnknownKey holds the time of the last refresh of the key set triggered by an unknown key.
+	lastUnknownKey time.Time
+
+	// fetchMu ensures that a single fetch of the key set is in progress.
+	fetchMu    sync.Mutex
+	refreshing atomic.Bool
+
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
+}
+
+func newJWKSCache(url string, refreshInterval time.Duration) *jwksCache {
+	if refreshInterval <= 0 {
+		refreshInterval = 15 * time.Minute
+	}
+
+	return &jwksCache{
+		url:             url,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: refreshInterval,
+		timeNow:         time.Now,
+	}
+}
+
+// get returns the cached key set, fetching it if it has never been fetched.
+func (c *jwksCache) get(ctx context.Context) (*jwksKeySet, error) {
+	c.mu.RLock()
+	keySet := c.keySet
+	c.mu.RUnlock()
+
+	if keySet == nil {
+		return c.refresh(ctx, time.Time{})
+	}
+
+	if c.timeNow().Sub(keySet.fetchedAt) >= c.refreshInterval && c.refreshing.CompareAndSwap(false, true) {
+		logger := log.Ctx(ctx).With().Logger()
+		safe.Go(func() {
+			defer c.refreshing.Store(false)
+
+			if _, err := c.refresh(context.Background(), keySet.fetchedAt); err != nil {
+				logger.Error().Err(err).Msg("Unable to refresh the JSON Web Key Set")
+			}
+		})
+	}
+
+	return keySet, nil
+}
+
+// refreshUnknownKey refreshes the key set fetched at the given time,
+// unless a refresh was already triggered by an unknown key recently.
+func (c *jwksCache) refreshUnknownKey(ctx context.Context, fetchedAt time.Time) (*jwksKeySet, error) {
+	c.mu.Lock()
+	if c.timeNow().Sub(c.lastUnknownKey) < jwksMinRefreshInterval {
+		keySet := c.keySet
+		c.mu.Unlock()
+
+		return keySet, nil
+	}
+	c.lastUnknownKey = c.timeNow()
+	c.mu.Unlock()
+
+	return c.refresh(ctx, fetchedAt)
+}
+
+// refresh fetches the key set, unless it was fetched after the given time meanwhile.
+// Following a failed fetch, the error is returned until jwksMinRefreshInterval elapses.
+func (c *jwksCache) refresh(ctx context.Context, fetchedAfter time.Time) (*jwksKeySet, error) {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	c.mu.RLock()
+	keySet, lastAttempt, lastErr := c.keySet, c.lastAttempt, c.lastErr
+	c.mu.RUnlock()
+
+	if keySet != nil && keySet.fetchedAt.After(fetchedAfter) {
+		return keySet, nil
+	}
+
+	if lastErr != nil && c.timeNow().Sub(lastAttempt) < jwksMinRefreshInterval {
+		if keySet != nil {
+			return keySet, nil
+		}
+
+		return nil, lastErr
+	}
+
+	keys, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastAttempt = c.timeNow()
+	c.lastErr = err
+	if err != nil {
+		if c.keySet != nil {
+			// Keeps serving the stale key set.
+			return c.keySet, err
+		}
+
+		return nil, err
+	}
+
+	c.keySet = &jwksKeySet{keys: keys, fetchedAt: c.lastAttempt}
+
+	return c.keySet, nil
+}
+
+func (c *jwksCache) fetch(ctx context.Context) (jose.JSONWebKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return jose.JSONWebKeySet{}, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return jose.JSONWebKeySet{}, fmt.Errorf("fetching JSON Web Key Set: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return jose.JSONWebKeySet{}, fmt.Errorf("fetching JSON Web Key Set: unexpected status code %d", resp.StatusCode)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err = json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&keys); err != nil {
+		return jose.JSONWebKeySet{}, fmt.Errorf("decoding JSON Web Key Set: %w", err)
+	}
+
+	return keys, nil
+}
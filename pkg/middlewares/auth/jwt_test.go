@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+
+	now := time.Now()
+	exp := jwt.NewNumericDate(now.Add(time.Hour))
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.JWT
+		token          string
+		expectedStatus int
+	}{
+		{
+			desc:           "missing token",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "malformed token",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          "foo.bar.baz",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "valid RSA token",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          signRS256(t, key, "", jwt.Claims{Subject: "user", Expiry: exp}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "RSA token signed with another key",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          signRS256(t, otherKey, "", jwt.Claims{Subject: "user", Expiry: exp}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "valid HMAC token",
+			config:         dynamic.JWT{Secret: "secretsecretsecretsecretsecretse"},
+			token:          signHS256(t, "secretsecretsecretsecretsecretse", jwt.Claims{Subject: "user", Expiry: exp}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "HMAC token signed with another secret",
+			config:         dynamic.JWT{Secret: "secretsecretsecretsecretsecretse"},
+			token:          signHS256(t, "othersecretothersecretothersecre", jwt.Claims{Subject: "user", Expiry: exp}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "valid issuer",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}, Issuer: "https://issuer.localhost"},
+			token:          signRS256(t, key, "", jwt.Claims{Issuer: "https://issuer.localhost", Expiry: exp}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "invalid issuer",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}, Issuer: "https://issuer.localhost"},
+			token:          signRS256(t, key, "", jwt.Claims{Issuer: "https://other.localhost", Expiry: exp}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "one of the accepted audiences",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}, Audiences: []string{"api", "web"}},
+			token:          signRS256(t, key, "", jwt.Claims{Audience: jwt.Audience{"web"}, Expiry: exp}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "invalid audience",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}, Audiences: []string{"api", "web"}},
+			token:          signRS256(t, key, "", jwt.Claims{Audience: jwt.Audience{"other"}, Expiry: exp}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "expired token",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          signRS256(t, key, "", jwt.Claims{Expiry: jwt.NewNumericDate(now.Add(-time.Minute))}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "expired token within the clock skew",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}, ClockSkew: ptypes.Duration(2 * time.Minute)},
+			token:          signRS256(t, key, "", jwt.Claims{Expiry: jwt.NewNumericDate(now.Add(-time.Minute))}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "token without expiration",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          signRS256(t, key, "", jwt.Claims{Subject: "user"}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "token not valid yet",
+			config:         dynamic.JWT{PublicKeys: []string{publicKeyPEM}},
+			token:          signRS256(t, key, "", jwt.Claims{NotBefore: jwt.NewNumericDate(now.Add(time.Minute)), Expiry: exp}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "claim rules satisfied",
+			config: dynamic.JWT{
+				PublicKeys: []string{publicKeyPEM},
+				ClaimRules: []dynamic.JWTClaimRule{
+					{Claim: "realm_access.roles", Values: []string{"admin"}},
+					{Claim: "email_verified", Values: []string{"true"}},
+					{Claim: "sub"},
+				},
+			},
+			token: signRS256(t, key, "", map[string]interface{}{
+				"exp":            exp,
+				"sub":            "user",
+				"email_verified": true,
+				"realm_access":   map[string]interface{}{"roles": []string{"user", "admin"}},
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "claim rule not satisfied",
+			config: dynamic.JWT{
+				PublicKeys: []string{publicKeyPEM},
+				ClaimRules: []dynamic.JWTClaimRule{{Claim: "realm_access.roles", Values: []string{"admin"}}},
+			},
+			token: signRS256(t, key, "", map[string]interface{}{
+				"exp":          exp,
+				"realm_access": map[string]interface{}{"roles": []string{"user"}},
+			}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc: "missing claim",
+			config: dynamic.JWT{
+				PublicKeys: []string{publicKeyPEM},
+				ClaimRules: []dynamic.JWTClaimRule{{Claim: "groups"}},
+			},
+			token:          signRS256(t, key, "", jwt.Claims{Subject: "user", Expiry: exp}),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := NewJWT(context.Background(), next, test.config, "jwt")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusUnauthorized {
+				assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestJWT_forwardHeaders(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	config := dynamic.JWT{
+		PublicKeys: []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))},
+		ForwardHeaders: map[string]string{
+			"X-User":    "sub",
+			"X-Groups":  "groups",
+			"X-Address": "address",
+			"X-Tenant":  "tenant",
+		},
+		RemoveHeader: true,
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "user", req.Header.Get("X-User"))
+		assert.Equal(t, "dev,ops", req.Header.Get("X-Groups"))
+		assert.Equal(t, `{"country":"FR"}`, req.Header.Get("X-Address"))
+		assert.Empty(t, req.Header.Values("X-Tenant"))
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	handler, err := NewJWT(context.Background(), next, config, "jwt")
+	require.NoError(t, err)
+
+	token := signRS256(t, key, "", map[string]interface{}{
+		"exp":     jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		"sub":     "user",
+		"groups":  []string{"dev", "ops"},
+		"address": map[string]interface{}{"country": "FR"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-User", "spoofed")
+	req.Header.Set("X-Tenant", "spoofed")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestJWT_JWKS(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+
+		keys := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key1.PublicKey, KeyID: "key1", Algorithm: string(jose.RS256), Use: "sig"},
+		}}
+		if rotated.Load() {
+			keys.Keys = append(keys.Keys, jose.JSONWebKey{Key: &key2.PublicKey, KeyID: "key2", Algorithm: string(jose.RS256), Use: "sig"})
+		}
+
+		err := json.NewEncoder(rw).Encode(keys)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewJWT(context.Background(), next, dynamic.JWT{JWKSURL: server.URL, JWKSRefreshInterval: ptypes.Duration(time.Hour)}, "jwt")
+	require.NoError(t, err)
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	exp := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	assert.Equal(t, http.StatusOK, serve(signRS256(t, key1, "key1", jwt.Claims{Subject: "user", Expiry: exp})))
+	assert.Equal(t, http.StatusUnauthorized, serve(signRS256(t, key2, "key2", jwt.Claims{Subject: "user", Expiry: exp})))
+
+	// A token signed with an unknown key triggers a refresh of the key set, once in a while.
+	fetchesBefore := fetches.Load()
+	rotated.Store(true)
+	assert.Equal(t, http.StatusUnauthorized, serve(signRS256(t, key2, "key2", jwt.Claims{Subject: "user", Expiry: exp})))
+	assert.Equal(t, fetchesBefore, fetches.Load())
+
+	handler.(*jwtAuth).jwks.lastUnknownKey = time.Time{}
+	assert.Equal(t, http.StatusOK, serve(signRS256(t, key2, "key2", jwt.Claims{Subject: "user", Expiry: exp})))
+	assert.Equal(t, fetchesBefore+1, fetches.Load())
+}
+
+func TestJWKSCache_refresh(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if fetches.Add(1) > 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"keys":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	cache := newJWKSCache(server.URL, time.Minute)
+	cache.timeNow = func() time.Time { return now }
+
+	keySet, err := cache.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, keySet.fetchedAt)
+
+	// The stale key set is served while it is refreshed in the background, and kept when the refresh fails.
+	now = now.Add(2 * time.Minute)
+	stale, err := cache.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, keySet, stale)
+
+	assert.Eventually(t, func() bool {
+		return !cache.refreshing.Load() && fetches.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stale, err = cache.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, keySet, stale)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims interface{}) string {
+	t.Helper()
+
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, opts)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func signHS256(t *testing.T, secret string, claims interface{}) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)}, (&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
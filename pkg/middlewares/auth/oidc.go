@@ -0,0 +1,576 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+)
+
+const typeNameOIDC = "OIDC"
+
+const (
+	defaultOIDCRedirectPath = "/oauth2/callback"
+	defaultOIDCLogoutPath   = "/oauth2/logout"
+
+	// oidcDiscoveryPath is the path of the OpenID Connect discovery document, relative to the issuer URL.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	// oidcStateMaxAge is the number of seconds given to the users to authenticate with the provider.
+	oidcStateMaxAge = 600
+	// oidcSessionTTL is the lifetime of the sessions whose tokens have no expiration time.
+	oidcSessionTTL = 5 * time.Minute
+)
+
+type oidcAuth struct {
+	next                  http.Handler
+	name                  string
+	issuer                string
+	clientID              string
+	clientSecret          string
+	scopes                []string
+	redirectPath          string
+	logoutPath            string
+	postLogoutRedirectURL string
+	forwardHeaders        map[string]string
+	forwardAccessToken    bool
+	cookies               *sessionCookies
+	client                *http.Client
+
+	providerMu sync.Mutex
+	provider   *oidcProvider
+	// lastDiscovery holds the time of the last failed discovery of the provider.
+	lastDiscovery time.Time
+	discoveryErr  error
+
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
+}
+
+// oidcProvider holds the endpoints of an OpenID Connect provider.
+type oidcProvider struct {
+	oauth2             *oauth2.Config
+	issuer             string
+	endSessionEndpoint string
+	jwks               *jwksCache
+}
+
+// oidcProviderMetadata is the OpenID Connect discovery document.
+type oidcProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// oidcSession is the session stored in the session cookie.
+type oidcSession struct {
+	IDToken      string    `json:"id"`
+	AccessToken  string    `json:"at,omitempty"`
+	RefreshToken string    `json:"rt,omitempty"`
+	Expiry       time.Time `json:"exp"`
+}
+
+// oidcState is the state of an authentication in progress, stored in the state cookie.
+type oidcState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	// RequestURI is the URI requested before the authentication, where the user is redirected afterward.
+	RequestURI string `json:"r"`
+}
+
+// NewOIDC creates an OpenID Connect middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDC, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeNameOIDC).Debug().Msg("Creating middleware")
+
+	if config.Issuer == "" {
+		return nil, errors.New("issuer must be set")
+	}
+
+	if config.ClientID == "" {
+		return nil, errors.New("clientId must be set")
+	}
+
+	if config.Session == nil || config.Session.Secret == "" {
+		return nil, errors.New("session secret must be set")
+	}
+
+	oa := &oidcAuth{
+		next:                  next,
+		name:                  name,
+		issuer:                config.Issuer,
+		clientID:              config.ClientID,
+		clientSecret:          config.ClientSecret,
+		scopes:                config.Scopes,
+		redirectPath:          config.RedirectPath,
+		logoutPath:            config.LogoutPath,
+		postLogoutRedirectURL: config.PostLogoutRedirectURL,
+		forwardHeaders:        config.ForwardHeaders,
+		forwardAccessToken:    config.ForwardAccessToken,
+		cookies:               newSessionCookies(config.Session),
+		client:                &http.Client{Timeout: 10 * time.Second},
+		timeNow:               time.Now,
+	}
+
+	if len(oa.scopes) == 0 {
+		oa.scopes = []string{"openid", "profile", "email"}
+	}
+
+	if oa.redirectPath == "" {
+		oa.redirectPath = defaultOIDCRedirectPath
+	}
+
+	if oa.logoutPath == "" {
+		oa.logoutPath = defaultOIDCLogoutPath
+	}
+
+	return oa, nil
+}
+
+func (o *oidcAuth) GetTracingInformation() (string, string, trace.SpanKind) {
+	return o.name, typeNameOIDC, trace.SpanKindInternal
+}
+
+func (o *oidcAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case o.redirectPath:
+		o.callback(rw, req)
+		return
+
+	case o.logoutPath:
+		o.logout(rw, req)
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), o.name, typeNameOIDC)
+
+	var session oidcSession
+	if err := o.cookies.readSession(req, &session); err != nil {
+		logger.Debug().Err(err).Msg("No valid session")
+		o.login(rw, req)
+		return
+	}
+
+	if !o.timeNow().Before(session.Expiry) {
+		if err := o.refresh(req.Context(), &session); err != nil {
+			logger.Debug().Err(err).Msg("Unable to refresh the session")
+			o.login(rw, req)
+			return
+		}
+
+		if err := o.cookies.writeSession(rw, req, session); err != nil {
+			logger.Error().Err(err).Msg("Unable to write the session cookie")
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var claims map[string]interface{}
+	token, err := jwt.ParseSigned(session.IDToken)
+	if err == nil {
+		// The ID token was validated when the session was created, and the session is encrypted.
+		err = token.UnsafeClaimsWithoutVerification(&claims)
+	}
+	if err != nil {
+		logger.Debug().Err(err).Msg("Invalid ID token in session")
+		o.login(rw, req)
+		return
+	}
+
+	if sub, ok := claims["sub"].(string); ok {
+		logData := accesslog.GetLogData(req)
+		if logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	for headerName, claim := range o.forwardHeaders {
+		value, ok := lookupClaim(claims, claim)
+		if !ok {
+			req.Header.Del(headerName)
+			continue
+		}
+
+		req.Header.Set(headerName, claimHeaderValue(value))
+	}
+
+	if o.forwardAccessToken && session.AccessToken != "" {
+		req.Header.Set(authorizationHeader, "Bearer "+session.AccessToken)
+	}
+
+	o.cookies.strip(req)
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// login redirects the user to the provider to authenticate.
+func (o *oidcAuth) login(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), o.name, typeNameOIDC)
+
+	tracing.SetStatusErrorf(req.Context(), "Authentication required")
+
+	// Only the navigations of the users can be redirected to the provider.
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	provider, err := o.discover(req.Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to discover the OpenID Connect provider")
+		rw.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	state := oidcState{
+		State:      randomString(),
+		Nonce:      randomString(),
+		Verifier:   oauth2.GenerateVerifier(),
+		RequestURI: req.URL.RequestURI(),
+	}
+
+	if err = o.cookies.writeState(rw, state); err != nil {
+		logger.Error().Err(err).Msg("Unable to write the state cookie")
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	authURL := provider.oauth2.AuthCodeURL(state.State,
+		oauth2.S256ChallengeOption(state.Verifier),
+		oauth2.SetAuthURLParam("nonce", state.Nonce),
+		oauth2.SetAuthURLParam("redirect_uri", o.redirectURL(req)),
+	)
+
+	http.Redirect(rw, req, authURL, http.StatusFound)
+}
+
+// callback handles the redirection of the user by the provider after the authentication.
+func (o *oidcAuth) callback(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), o.name, typeNameOIDC)
+
+	var state oidcState
+	if err := o.cookies.readState(req, &state); err != nil {
+		logger.Debug().Err(err).Msg("Authentication failed: invalid state cookie")
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	o.cookies.clearState(rw)
+
+	query := req.URL.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		logger.Debug().Msgf("Authentication failed: %s: %s", errCode, query.Get("error_description"))
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if query.Get("state") != state.State {
+		logger.Debug().Msg("Authentication failed: state mismatch")
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	session, err := o.exchange(req.Context(), query.Get("code"), state, o.redirectURL(req))
+	if err != nil {
+		logger.Debug().Err(err).Msg("Authentication failed")
+		tracing.SetStatusErrorf(req.Context(), "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err = o.cookies.writeSession(rw, req, session); err != nil {
+		logger.Error().Err(err).Msg("Unable to write the session cookie")
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debug().Msg("Authentication succeeded")
+
+	requestURI := state.RequestURI
+	if !strings.HasPrefix(requestURI, "/") || strings.HasPrefix(requestURI, "//") {
+		requestURI = "/"
+	}
+
+	http.Redirect(rw, req, requestURI, http.StatusFound)
+}
+
+// logout clears the session, and redirects the user to the provider to end the session there too.
+func (o *oidcAuth) logout(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), o.name, typeNameOIDC)
+
+	var session oidcSession
+	hasSession := o.cookies.readSession(req, &session) == nil
+
+	o.cookies.clearSession(rw, req)
+
+	redirectURL := o.postLogoutRedirectURL
+	if redirectURL == "" {
+		redirectURL = "/"
+	}
+
+	provider, err := o.discover(req.Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("Unable to discover the OpenID Connect provider")
+	}
+
+	if provider != nil && provider.endSessionEndpoint != "" {
+		endSessionURL, err := url.Parse(provider.endSessionEndpoint)
+		if err == nil {
+			values := endSessionURL.Query()
+			values.Set("client_id", o.clientID)
+			if hasSession {
+				values.Set("id_token_hint", session.IDToken)
+			}
+			if o.postLogoutRedirectURL != "" {
+				values.Set("post_logout_redirect_uri", o.postLogoutRedirectURL)
+			}
+			endSessionURL.RawQuery = values.Encode()
+
+			redirectURL = endSessionURL.String()
+		}
+	}
+
+	http.Redirect(rw, req, redirectURL, http.StatusFound)
+}
+
+// exchange exchanges the authorization code for the tokens of the session.
+func (o *oidcAuth) exchange(ctx context.Context, code string, state oidcState, redirectURL string) (oidcSession, error) {
+	provider, err := o.discover(ctx)
+	if err != nil {
+		return oidcSession{}, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, o.client)
+
+	token, err := provider.oauth2.Exchange(ctx, code,
+		oauth2.VerifierOption(state.Verifier),
+		oauth2.SetAuthURLParam("redirect_uri", redirectURL),
+	)
+	if err != nil {
+		return oidcSession{}, fmt.Errorf("exchanging authorization code: %w", err)
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return oidcSession{}, errors.New("no ID token in the token response")
+	}
+
+	idToken, err := o.validateIDToken(ctx, provider, rawIDToken)
+	if err != nil {
+		return oidcSession{}, err
+	}
+
+	if nonce, _ := idToken.claims["nonce"].(string); nonce != state.Nonce {
+		return oidcSession{}, errors.New("invalid ID token nonce")
+	}
+
+	return newOIDCSession(token, rawIDToken, idToken.expiry, o.timeNow()), nil
+}
+
+// refresh refreshes the tokens of the given session.
+func (o *oidcAuth) refresh(ctx context.Context, session *oidcSession) error {
+	if session.RefreshToken == "" {
+		return errors.New("session expired")
+	}
+
+	provider, err := o.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, o.client)
+
+	token, err := provider.oauth2.TokenSource(ctx, &oauth2.Token{RefreshToken: session.RefreshToken}).Token()
+	if err != nil {
+		return fmt.Errorf("refreshing tokens: %w", err)
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		// The provider may not issue a new ID token on refresh.
+		*session = newOIDCSession(token, session.IDToken, time.Time{}, o.timeNow())
+		return nil
+	}
+
+	idToken, err := o.validateIDToken(ctx, provider, rawIDToken)
+	if err != nil {
+		return err
+	}
+
+	*session = newOIDCSession(token, rawIDToken, idToken.expiry, o.timeNow())
+
+	return nil
+}
+
+func newOIDCSession(token *oauth2.Token, rawIDToken string, idTokenExpiry, now time.Time) oidcSession {
+	expiry := token.Expiry
+	if expiry.IsZero() {
+		expiry = idTokenExpiry
+	}
+	if expiry.IsZero() {
+		expiry = now.Add(oidcSessionTTL)
+	}
+
+	return oidcSession{
+		IDToken:      rawIDToken,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		Expiry:       expiry,
+	}
+}
+
+type oidcIDToken struct {
+	claims map[string]interface{}
+	expiry time.Time
+}
+
+// validateIDToken verifies the signature and the registered claims of the given ID token.
+func (o *oidcAuth) validateIDToken(ctx context.Context, provider *oidcProvider, rawIDToken string) (oidcIDToken, error) {
+	token, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return oidcIDToken{}, fmt.Errorf("parsing ID token: %w", err)
+	}
+
+	var registered jwt.Claims
+	var claims map[string]interface{}
+	if err = verifySignature(ctx, token, nil, provider.jwks, &registered, &claims); err != nil {
+		return oidcIDToken{}, fmt.Errorf("verifying ID token: %w", err)
+	}
+
+	expected := jwt.Expected{
+		Issuer:   provider.issuer,
+		Audience: jwt.Audience{o.clientID},
+		Time:     o.timeNow(),
+	}
+	if err = registered.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return oidcIDToken{}, fmt.Errorf("validating ID token: %w", err)
+	}
+
+	idToken := oidcIDToken{claims: claims}
+	if registered.Expiry != nil {
+		idToken.expiry = registered.Expiry.Time()
+	}
+
+	return idToken, nil
+}
+
+// discover returns the endpoints of the provider, fetching its discovery document on first use.
+// Following a failed discovery, the error is returned until jwksMinRefreshInterval elapses.
+func (o *oidcAuth) discover(ctx context.Context) (*oidcProvider, error) {
+	o.providerMu.Lock()
+	defer o.providerMu.Unlock()
+
+	if o.provider != nil {
+		return o.provider, nil
+	}
+
+	if o.discoveryErr != nil && o.timeNow().Sub(o.lastDiscovery) < jwksMinRefreshInterval {
+		return nil, o.discoveryErr
+	}
+
+	metadata, err := o.fetchMetadata(ctx)
+	if err != nil {
+		o.lastDiscovery = o.timeNow()
+		o.discoveryErr = err
+		return nil, err
+	}
+
+	o.provider = &oidcProvider{
+		oauth2: &oauth2.Config{
+			ClientID:     o.clientID,
+			ClientSecret: o.clientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  metadata.AuthorizationEndpoint,
+				TokenURL: metadata.TokenEndpoint,
+			},
+			Scopes: o.scopes,
+		},
+		issuer:             metadata.Issuer,
+		endSessionEndpoint: metadata.EndSessionEndpoint,
+		jwks:               newJWKSCache(metadata.JWKSURI, 0),
+	}
+
+	return o.provider, nil
+}
+
+func (o *oidcAuth) fetchMetadata(ctx context.Context) (*oidcProviderMetadata, error) {
+	// The issuer is compared as-is with the one of the discovery document and of the tokens,
+	// as some providers, e.g. Auth0, include a trailing slash in it.
+	discoveryURL := strings.TrimSuffix(o.issuer, "/") + oidcDiscoveryPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching discovery document: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching discovery document: unexpected status code %d", resp.StatusCode)
+	}
+
+	var metadata oidcProviderMetadata
+	if err = json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("decoding discovery document: %w", err)
+	}
+
+	if metadata.Issuer != o.issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match the configured issuer %q", metadata.Issuer, o.issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing the authorization, token, or JWKS endpoints")
+	}
+
+	return &metadata, nil
+}
+
+// redirectURL returns the URL where the provider redirects the user after the authentication.
+func (o *oidcAuth) redirectURL(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + req.Host + o.redirectPath
+}
+
+func randomString() string {
+	data := make([]byte, 32)
+	// rand.Read never returns an error on the supported platforms.
+	_, _ = rand.Read(data)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,264 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+const (
+	defaultSessionCookieName = "traefik_oidc"
+	// sessionCookieChunkSize is the maximum size of the value of a session cookie,
+	// below the 4096 bytes limit of the browsers for a cookie with its attributes.
+	sessionCookieChunkSize = 3800
+	// stateCookieSuffix is the suffix of the name of the state cookie.
+	stateCookieSuffix = "_state"
+)
+
+// sessionCookies reads and writes the encrypted session and state cookies.
+// A session cookie too large for the browsers is split in several cookies, suffixed by their index (_0, _1, ...).
+type sessionCookies struct {
+	name     string
+	path     string
+	domain   string
+	secure   bool
+	sameSite http.SameSite
+	maxAge   int
+	aead     cipher.AEAD
+}
+
+func newSessionCookies(config *dynamic.OIDCSession) *sessionCookies {
+	c := &sessionCookies{
+		name:     config.Name,
+		path:     config.Path,
+		domain:   config.Domain,
+		secure:   config.Secure,
+		sameSite: convertSameSite(config.SameSite),
+		maxAge:   config.MaxAge,
+	}
+
+	if c.name == "" {
+		c.name = defaultSessionCookieName
+	}
+
+	if c.path == "" {
+		c.path = "/"
+	}
+
+	if config.SameSite == "" {
+		c.sameSite = http.SameSiteLaxMode
+	}
+
+	// The secret is hashed to get a key of the size expected by AES-256.
+	key := sha256.Sum256([]byte(config.Secret))
+
+	// NewCipher and NewGCM never fail with a 32 bytes key.
+	block, _ := aes.NewCipher(key[:])
+	c.aead, _ = cipher.NewGCM(block)
+
+	return c
+}
+
+// readSession decrypts the session carried by the session cookies of the request into session.
+func (c *sessionCookies) readSession(req *http.Request, session interface{}) error {
+	if cookie, err := req.Cookie(c.name); err == nil {
+		return c.decrypt(c.name, cookie.Value, session)
+	}
+
+	var value strings.Builder
+	for i := 0; ; i++ {
+		cookie, err := req.Cookie(c.chunkName(i))
+		if err != nil {
+			break
+		}
+
+		value.WriteString(cookie.Value)
+	}
+
+	if value.Len() == 0 {
+		return errors.New("no session cookie")
+	}
+
+	return c.decrypt(c.name, value.String(), session)
+}
+
+// writeSession writes the session cookies carrying the given session,
+// and expires the cookies of the request which are no longer used.
+func (c *sessionCookies) writeSession(rw http.ResponseWriter, req *http.Request, session interface{}) error {
+	value, err := c.encrypt(c.name, session)
+	if err != nil {
+		return err
+	}
+
+	if len(value) <= sessionCookieChunkSize {
+		http.SetCookie(rw, c.cookie(c.name, value, c.maxAge))
+		c.expireChunks(rw, req, 0)
+		return nil
+	}
+
+	var count int
+	for ; len(value) > 0; count++ {
+		size := min(len(value), sessionCookieChunkSize)
+		http.SetCookie(rw, c.cookie(c.chunkName(count), value[:size], c.maxAge))
+		value = value[size:]
+	}
+
+	if _, err = req.Cookie(c.name); err == nil {
+		http.SetCookie(rw, c.cookie(c.name, "", -1))
+	}
+	c.expireChunks(rw, req, count)
+
+	return nil
+}
+
+// clearSession expires the session cookies of the request.
+func (c *sessionCookies) clearSession(rw http.ResponseWriter, req *http.Request) {
+	if _, err := req.Cookie(c.name); err == nil {
+		http.SetCookie(rw, c.cookie(c.name, "", -1))
+	}
+
+	c.expireChunks(rw, req, 0)
+}
+
+// expireChunks expires the session cookie chunks of the request from the given index.
+func (c *sessionCookies) expireChunks(rw http.ResponseWriter, req *http.Request, from int) {
+	for _, cookie := range req.Cookies() {
+		index, ok := c.chunkIndex(cookie.Name)
+		if ok && index >= from {
+			http.SetCookie(rw, c.cookie(cookie.Name, "", -1))
+		}
+	}
+}
+
+// readState decrypts the state carried by the state cookie of the request into state.
+func (c *sessionCookies) readState(req *http.Request, state interface{}) error {
+	cookie, err := req.Cookie(c.name + stateCookieSuffix)
+	if err != nil {
+		return err
+	}
+
+	return c.decrypt(c.name+stateCookieSuffix, cookie.Value, state)
+}
+
+// writeState writes the state cookie carrying the given state.
+func (c *sessionCookies) writeState(rw http.ResponseWriter, state interface{}) error {
+	value, err := c.encrypt(c.name+stateCookieSuffix, state)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(rw, c.cookie(c.name+stateCookieSuffix, value, oidcStateMaxAge))
+
+	return nil
+}
+
+// clearState expires the state cookie.
+func (c *sessionCookies) clearState(rw http.ResponseWriter) {
+	http.SetCookie(rw, c.cookie(c.name+stateCookieSuffix, "", -1))
+}
+
+// strip removes the session and state cookies from the request.
+func (c *sessionCookies) strip(req *http.Request) {
+	cookies := req.Cookies()
+
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if _, ok := c.chunkIndex(cookie.Name); ok || cookie.Name == c.name || cookie.Name == c.name+stateCookieSuffix {
+			continue
+		}
+
+		req.AddCookie(cookie)
+	}
+}
+
+func (c *sessionCookies) cookie(name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     c.path,
+		Domain:   c.domain,
+		HttpOnly: true,
+		Secure:   c.secure,
+		SameSite: c.sameSite,
+		MaxAge:   maxAge,
+	}
+}
+
+func (c *sessionCookies) chunkName(index int) string {
+	return c.name + "_" + strconv.Itoa(index)
+}
+
+// chunkIndex returns the index of the session cookie chunk with the given name.
+func (c *sessionCookies) chunkIndex(name string) (int, bool) {
+	suffix, ok := strings.CutPrefix(name, c.name+"_")
+	if !ok {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(suffix)
+	if err != nil || index < 0 {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// encrypt returns the encrypted value of the cookie with the given name, carrying the given data.
+// The cookie name is authenticated along with the data, so that the value of a cookie cannot be used for another one.
+func (c *sessionCookies) encrypt(name string, data interface{}) (string, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+// decrypt decrypts the value of the cookie with the given name into data.
+func (c *sessionCookies) decrypt(name, value string, data interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) < c.aead.NonceSize() {
+		return errors.New("invalid encrypted value")
+	}
+
+	nonce, ciphertext := raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():]
+
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return fmt.Errorf("decrypting value: %w", err)
+	}
+
+	return json.Unmarshal(plaintext, data)
+}
+
+func convertSameSite(sameSite string) http.SameSite {
+	switch sameSite {
+	case "none":
+		return http.SameSiteNoneMode
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
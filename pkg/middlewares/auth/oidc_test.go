@@ -0,0 +1,423 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"golang.org/x/oauth2"
+)
+
+func TestOIDC(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	var forwarded *http.Request
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req
+	})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:                idp.URL,
+		ClientID:              "client",
+		ClientSecret:          "secret",
+		PostLogoutRedirectURL: "http://app.localhost/bye",
+		Session:               &dynamic.OIDCSession{Secret: "sessionsecret"},
+		ForwardHeaders:        map[string]string{"X-Auth-User": "sub", "X-Auth-Groups": "groups"},
+		ForwardAccessToken:    true,
+	}, "oidc")
+	require.NoError(t, err)
+
+	now := time.Now()
+	handler.(*oidcAuth).timeNow = func() time.Time { return now }
+
+	// The user is redirected to the provider.
+	recorder := serveOIDC(handler, http.MethodGet, "http://app.localhost/app?foo=bar", nil)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	authURL, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	query := authURL.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "openid profile email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "http://app.localhost/oauth2/callback", query.Get("redirect_uri"))
+	require.NotEmpty(t, query.Get("nonce"))
+
+	stateCookies := recorder.Result().Cookies()
+	require.Len(t, stateCookies, 1)
+
+	// The provider redirects the user back with an authorization code.
+	code := idp.authorize(query.Get("code_challenge"), query.Get("nonce"))
+
+	callbackURL := "http://app.localhost/oauth2/callback?code=" + code + "&state=" + query.Get("state")
+	recorder = serveOIDC(handler, http.MethodGet, callbackURL, stateCookies)
+	require.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "/app?foo=bar", recorder.Header().Get("Location"))
+
+	var sessionCookies []*http.Cookie
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			sessionCookies = append(sessionCookies, cookie)
+		}
+	}
+	require.Len(t, sessionCookies, 1)
+	assert.Equal(t, "traefik_oidc", sessionCookies[0].Name)
+	assert.True(t, sessionCookies[0].HttpOnly)
+
+	// The authenticated user reaches the service, without the session cookie.
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.localhost/app", append(sessionCookies, &http.Cookie{Name: "other", Value: "value"}))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "user", forwarded.Header.Get("X-Auth-User"))
+	assert.Equal(t, "dev,ops", forwarded.Header.Get("X-Auth-Groups"))
+	assert.Equal(t, "Bearer access-1", forwarded.Header.Get("Authorization"))
+	assert.Equal(t, "other=value", forwarded.Header.Get("Cookie"))
+
+	// Once the access token expired, the tokens are refreshed.
+	now = now.Add(2 * time.Hour)
+	idp.setClock(now)
+
+	forwarded = nil
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.localhost/app", sessionCookies)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "Bearer access-2", forwarded.Header.Get("Authorization"))
+	require.Len(t, recorder.Result().Cookies(), 1)
+
+	sessionCookies = recorder.Result().Cookies()
+
+	// The user is logged out from the provider too.
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.localhost/oauth2/logout", sessionCookies)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	logoutURL, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/logout", logoutURL.Scheme+"://"+logoutURL.Host+logoutURL.Path)
+	assert.Equal(t, "http://app.localhost/bye", logoutURL.Query().Get("post_logout_redirect_uri"))
+	assert.NotEmpty(t, logoutURL.Query().Get("id_token_hint"))
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "traefik_oidc", cookies[0].Name)
+	assert.Negative(t, cookies[0].MaxAge)
+}
+
+func TestOIDC_issuerWithTrailingSlash(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.issuer = idp.URL + "/"
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:   idp.issuer,
+		ClientID: "client",
+		Session:  &dynamic.OIDCSession{Secret: "sessionsecret"},
+	}, "oidc")
+	require.NoError(t, err)
+
+	recorder := serveOIDC(handler, http.MethodGet, "http://app.localhost/app", nil)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	authURL, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	query := authURL.Query()
+	code := idp.authorize(query.Get("code_challenge"), query.Get("nonce"))
+
+	callbackURL := "http://app.localhost/oauth2/callback?code=" + code + "&state=" + query.Get("state")
+	recorder = serveOIDC(handler, http.MethodGet, callbackURL, recorder.Result().Cookies())
+	require.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "/app", recorder.Header().Get("Location"))
+}
+
+func TestOIDC_callbackFailures(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:   idp.URL,
+		ClientID: "client",
+		Session:  &dynamic.OIDCSession{Secret: "sessionsecret"},
+	}, "oidc")
+	require.NoError(t, err)
+
+	recorder := serveOIDC(handler, http.MethodGet, "http://app.localhost/app", nil)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	authURL, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+
+	query := authURL.Query()
+	stateCookies := recorder.Result().Cookies()
+
+	testCases := []struct {
+		desc    string
+		query   string
+		cookies []*http.Cookie
+	}{
+		{
+			desc:  "missing state cookie",
+			query: "code=" + idp.authorize(query.Get("code_challenge"), query.Get("nonce")) + "&state=" + query.Get("state"),
+		},
+		{
+			desc:    "state mismatch",
+			query:   "code=" + idp.authorize(query.Get("code_challenge"), query.Get("nonce")) + "&state=other",
+			cookies: stateCookies,
+		},
+		{
+			desc:    "invalid code verifier",
+			query:   "code=" + idp.authorize("invalid", query.Get("nonce")) + "&state=" + query.Get("state"),
+			cookies: stateCookies,
+		},
+		{
+			desc:    "invalid nonce",
+			query:   "code=" + idp.authorize(query.Get("code_challenge"), "other") + "&state=" + query.Get("state"),
+			cookies: stateCookies,
+		},
+		{
+			desc:    "provider error",
+			query:   "error=access_denied&state=" + query.Get("state"),
+			cookies: stateCookies,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			recorder := serveOIDC(handler, http.MethodGet, "http://app.localhost/oauth2/callback?"+test.query, test.cookies)
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		})
+	}
+}
+
+func TestOIDC_unauthenticatedRequests(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:   idp.URL,
+		ClientID: "client",
+		Session:  &dynamic.OIDCSession{Secret: "sessionsecret"},
+	}, "oidc")
+	require.NoError(t, err)
+
+	recorder := serveOIDC(handler, http.MethodPost, "http://app.localhost/app", nil)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	forged := &http.Cookie{Name: "traefik_oidc", Value: "forged"}
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.localhost/app", []*http.Cookie{forged})
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Location"), idp.URL+"/authorize"))
+}
+
+func TestSessionCookies_chunks(t *testing.T) {
+	cookies := newSessionCookies(&dynamic.OIDCSession{Secret: "secret"})
+
+	session := oidcSession{IDToken: strings.Repeat("a", 5000), Expiry: time.Now().UTC()}
+
+	// The request holds the chunks of a larger previous session.
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	for _, name := range []string{"traefik_oidc_0", "traefik_oidc_1", "traefik_oidc_2"} {
+		req.AddCookie(&http.Cookie{Name: name, Value: "old"})
+	}
+
+	recorder := httptest.NewRecorder()
+	err := cookies.writeSession(recorder, req, session)
+	require.NoError(t, err)
+
+	written := make(map[string]*http.Cookie)
+	for _, cookie := range recorder.Result().Cookies() {
+		written[cookie.Name] = cookie
+	}
+
+	require.Len(t, written, 3)
+	assert.LessOrEqual(t, len(written["traefik_oidc_0"].Value), sessionCookieChunkSize)
+	assert.NotEmpty(t, written["traefik_oidc_1"].Value)
+	assert.Negative(t, written["traefik_oidc_2"].MaxAge)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.AddCookie(written["traefik_oidc_0"])
+	req.AddCookie(written["traefik_oidc_1"])
+
+	var got oidcSession
+	err = cookies.readSession(req, &got)
+	require.NoError(t, err)
+	assert.Equal(t, session, got)
+
+	// A cookie value cannot be used as the value of another cookie.
+	value, err := cookies.encrypt("traefik_oidc_state", session)
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.AddCookie(&http.Cookie{Name: "traefik_oidc", Value: value})
+
+	err = cookies.readSession(req, &got)
+	assert.Error(t, err)
+}
+
+func serveOIDC(handler http.Handler, method, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+// fakeIdP is a stand-in OpenID Connect provider.
+type fakeIdP struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+	// issuer is the issuer URL of the provider, its URL by default.
+	issuer string
+
+	mu    sync.Mutex
+	clock time.Time
+	// codes holds the code challenge and the nonce of the issued authorization codes.
+	codes  map[string][2]string
+	tokens int
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &fakeIdP{key: key, clock: time.Now(), codes: make(map[string][2]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(oidcProviderMetadata{
+			Issuer:                idp.issuer,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+			EndSessionEndpoint:    idp.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "key", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", idp.token)
+
+	idp.Server = httptest.NewServer(mux)
+	idp.issuer = idp.URL
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+func (p *fakeIdP) setClock(clock time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clock = clock
+}
+
+// authorize returns an authorization code for the given code challenge and nonce.
+func (p *fakeIdP) authorize(codeChallenge, nonce string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	code := randomString()
+	p.codes[code] = [2]string{codeChallenge, nonce}
+
+	return code
+}
+
+func (p *fakeIdP) token(rw http.ResponseWriter, req *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if user, password, _ := req.BasicAuth(); user != "client" || (password != "secret" && password != "") {
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var nonce string
+	switch req.FormValue("grant_type") {
+	case "authorization_code":
+		code, ok := p.codes[req.FormValue("code")]
+		delete(p.codes, req.FormValue("code"))
+
+		if !ok || code[0] != oauth2.S256ChallengeFromVerifier(req.FormValue("code_verifier")) {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		nonce = code[1]
+
+	case "refresh_token":
+		if req.FormValue("refresh_token") != "refresh" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+	default:
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key}, (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "key"))
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":    p.issuer,
+		"sub":    "user",
+		"aud":    "client",
+		"exp":    p.clock.Add(time.Hour).Unix(),
+		"iat":    p.clock.Unix(),
+		"groups": []string{"dev", "ops"},
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	idToken, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	p.tokens++
+
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"access_token":  "access-" + strconv.Itoa(p.tokens),
+		"token_type":    "Bearer",
+		"refresh_token": "refresh",
+		"id_token":      idToken,
+		"expires_in":    3600,
+	})
+}
@@ -0,0 +1,130 @@
+// Package bandwidthlimiter implements a middleware throttling the response bodies with token buckets.
+package bandwidthlimiter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bandwidthlimiter/shaper"
+	"github.com/vulcand/oxy/v2/utils"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+const typeName = "BandwidthLimiter"
+
+// bandwidthLimiter throttles the response bodies with a token bucket for each response,
+// and a token bucket shared by the responses to the same source.
+type bandwidthLimiter struct {
+	name string
+	next http.Handler
+
+	rate  int64
+	burst int64
+
+	sources         *shaper.Sources
+	sourceExtractor utils.SourceExtractor
+}
+
+// New creates a bandwidth limiter middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.BandwidthLimit, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if err := shaper.ValidateLimit(config.Rate, config.Burst); err != nil {
+		return nil, err
+	}
+
+	if err := shaper.ValidateLimit(config.SourceRate, config.SourceBurst); err != nil {
+		return nil, fmt.Errorf("invalid source limit: %w", err)
+	}
+
+	if config.Rate == 0 && config.SourceRate == 0 {
+		return nil, errors.New("at least one of rate and sourceRate must be defined")
+	}
+
+	bl := &bandwidthLimiter{
+		name:    name,
+		next:    next,
+		rate:    config.Rate,
+		burst:   config.Burst,
+		sources: shaper.NewSources(config.SourceRate, config.SourceBurst),
+	}
+
+	if bl.sources != nil {
+		sourceExtractor, err := middlewares.GetSourceExtractor(logger.WithContext(ctx), config.SourceCriterion)
+		if err != nil {
+			return nil, err
+		}
+		bl.sourceExtractor = sourceExtractor
+	}
+
+	return bl, nil
+}
+
+func (b *bandwidthLimiter) GetTracingInformation() (string, string, trace.SpanKind) {
+	return b.name, typeName, trace.SpanKindInternal
+}
+
+func (b *bandwidthLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), b.name, typeName)
+
+	if b.sourceExtractor != nil {
+		source, _, err := b.sourceExtractor.Extract(req)
+		if err != nil {
+			logger.Error().Err(err).Msg("Could not extract source of request")
+			http.Error(rw, "could not extract source of request", http.StatusInternalServerError)
+			return
+		}
+
+		_, sourceBucket := b.sources.Acquire(source)
+		defer b.sources.Release(source)
+
+		rw = newResponseWriter(req.Context(), rw, shaper.NewBucket(b.rate, b.burst), sourceBucket)
+	} else {
+		rw = newResponseWriter(req.Context(), rw, shaper.NewBucket(b.rate, b.burst))
+	}
+
+	b.next.ServeHTTP(rw, req)
+}
+
+// responseWriter throttles the writes of the response body.
+type responseWriter struct {
+	http.ResponseWriter
+
+	writer *shaper.Writer
+}
+
+func newResponseWriter(ctx context.Context, rw http.ResponseWriter, buckets ...*rate.Limiter) *responseWriter {
+	return &responseWriter{
+		ResponseWriter: rw,
+		writer:         shaper.NewWriter(ctx, rw, buckets...),
+	}
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	return r.writer.Write(p)
+}
+
+// Hijack hijacks the connection, which is not throttled anymore.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
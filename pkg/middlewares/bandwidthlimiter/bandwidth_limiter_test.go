@@ -0,0 +1,124 @@
+package bandwidthlimiter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.BandwidthLimit
+		expectedError bool
+	}{
+		{
+			desc:   "rate",
+			config: dynamic.BandwidthLimit{Rate: 1000, Burst: 100},
+		},
+		{
+			desc:   "source rate",
+			config: dynamic.BandwidthLimit{SourceRate: 1000},
+		},
+		{
+			desc:          "no rate",
+			config:        dynamic.BandwidthLimit{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative rate",
+			config:        dynamic.BandwidthLimit{Rate: -1},
+			expectedError: true,
+		},
+		{
+			desc:          "burst without rate",
+			config:        dynamic.BandwidthLimit{Burst: 100, SourceRate: 1000},
+			expectedError: true,
+		},
+		{
+			desc:          "negative source burst",
+			config:        dynamic.BandwidthLimit{SourceRate: 1000, SourceBurst: -1},
+			expectedError: true,
+		},
+		{
+			desc: "invalid source criterion",
+			config: dynamic.BandwidthLimit{
+				SourceRate: 1000,
+				SourceCriterion: &dynamic.SourceCriterion{
+					RequestHeaderName: "Foo",
+					RequestHost:       true,
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "foo")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBandwidthLimiter_ServeHTTP(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 3000)
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(body)
+	})
+
+	testCases := []struct {
+		desc   string
+		config dynamic.BandwidthLimit
+	}{
+		{
+			desc:   "response rate",
+			config: dynamic.BandwidthLimit{Rate: 10000, Burst: 1000},
+		},
+		{
+			desc:   "source rate",
+			config: dynamic.BandwidthLimit{SourceRate: 10000, SourceBurst: 1000},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			rw := httptest.NewRecorder()
+
+			start := time.Now()
+			handler.ServeHTTP(rw, req)
+
+			// The first 1000 bytes are sent at once, the next 2000 bytes need 200ms worth of tokens.
+			assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, body, rw.Body.Bytes())
+
+			// The source buckets are released once the response is sent.
+			assert.Zero(t, handler.(*bandwidthLimiter).sources.Len())
+		})
+	}
+}
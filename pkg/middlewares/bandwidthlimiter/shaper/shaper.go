@@ -0,0 +1,243 @@
+// Package shaper implements the token bucket shaping of the byte streams, shared by the HTTP and TCP bandwidth limiters.
+package shaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// NewBucket returns a token bucket refilled with rate tokens (bytes) per second, and holding up to burst tokens.
+// The burst defaults to the rate.
+// It returns nil if the rate is zero, i.e. when there is no limit.
+func NewBucket(bytesPerSecond, burst int64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+
+	if burst == 0 {
+		burst = bytesPerSecond
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(burst))
+}
+
+// ValidateLimit checks the given rate and burst.
+func ValidateLimit(bytesPerSecond, burst int64) error {
+	if bytesPerSecond < 0 {
+		return fmt.Errorf("negative value not valid for rate: %d", bytesPerSecond)
+	}
+
+	if burst < 0 {
+		return fmt.Errorf("negative value not valid for burst: %d", burst)
+	}
+
+	if bytesPerSecond == 0 && burst > 0 {
+		return errors.New("burst cannot be defined without rate")
+	}
+
+	return nil
+}
+
+// Reader is an io.Reader throttled by a set of token buckets.
+type Reader struct {
+	ctx     context.Context
+	reader  io.Reader
+	buckets []*rate.Limiter
+}
+
+// NewReader returns a Reader throttling the given reader with the given buckets.
+// The nil buckets are ignored.
+// The reads waiting for tokens are interrupted when the context is done.
+func NewReader(ctx context.Context, reader io.Reader, buckets ...*rate.Limiter) *Reader {
+	return &Reader{ctx: ctx, reader: reader, buckets: nonNil(buckets)}
+}
+
+// Read reads at most the burst of the buckets, and waits for the tokens of the read bytes to be available,
+// so that the next read is delayed accordingly.
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(r.buckets) == 0 {
+		return r.reader.Read(p)
+	}
+
+	if size := chunkSize(r.buckets); len(p) > size {
+		p = p[:size]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := wait(r.ctx, r.buckets, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
+
+// Writer is an io.Writer throttled by a set of token buckets.
+type Writer struct {
+	ctx     context.Context
+	writer  io.Writer
+	buckets []*rate.Limiter
+}
+
+// NewWriter returns a Writer throttling the given writer with the given buckets.
+// The nil buckets are ignored.
+// The writes waiting for tokens are interrupted when the context is done.
+func NewWriter(ctx context.Context, writer io.Writer, buckets ...*rate.Limiter) *Writer {
+	return &Writer{ctx: ctx, writer: writer, buckets: nonNil(buckets)}
+}
+
+// Write writes the given bytes by chunks of at most the burst of the buckets,
+// each of them once its tokens are available.
+func (w *Writer) Write(p []byte) (int, error) {
+	if len(w.buckets) == 0 {
+		return w.writer.Write(p)
+	}
+
+	size := chunkSize(w.buckets)
+
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		if err := wait(w.ctx, w.buckets, len(chunk)); err != nil {
+			return written, err
+		}
+
+		n, err := w.writer.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// Sources holds the token buckets shared by the connections from the same source,
+// one for each direction, as long as at least one of these connections is open.
+type Sources struct {
+	rate  int64
+	burst int64
+
+	mu      sync.Mutex
+	buckets map[string]*sourceBuckets
+}
+
+type sourceBuckets struct {
+	read  *rate.Limiter
+	write *rate.Limiter
+	refs  int
+}
+
+// NewSources creates a set of source buckets with the given rate and burst.
+// It returns nil if the rate is zero, i.e. when there is no limit.
+func NewSources(bytesPerSecond, burst int64) *Sources {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+
+	return &Sources{
+		rate:    bytesPerSecond,
+		burst:   burst,
+		buckets: make(map[string]*sourceBuckets),
+	}
+}
+
+// Acquire returns the read and write buckets of the given source, which must be released once the connection is closed.
+// It returns nil buckets on a nil Sources.
+func (s *Sources) Acquire(source string) (*rate.Limiter, *rate.Limiter) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buckets, ok := s.buckets[source]
+	if !ok {
+		buckets = &sourceBuckets{
+			read:  NewBucket(s.rate, s.burst),
+			write: NewBucket(s.rate, s.burst),
+		}
+		s.buckets[source] = buckets
+	}
+
+	buckets.refs++
+
+	return buckets.read, buckets.write
+}
+
+// Len returns the number of sources whose buckets are in use.
+func (s *Sources) Len() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
+
+// Release releases the buckets of the given source,
+// which are removed when they are not used by any connection anymore.
+func (s *Sources) Release(source string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buckets, ok := s.buckets[source]
+	if !ok {
+		return
+	}
+
+	buckets.refs--
+	if buckets.refs <= 0 {
+		delete(s.buckets, source)
+	}
+}
+
+func wait(ctx context.Context, buckets []*rate.Limiter, n int) error {
+	for _, bucket := range buckets {
+		if err := bucket.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunkSize returns the maximum number of bytes which can be transferred at once, i.e. the smallest burst.
+func chunkSize(buckets []*rate.Limiter) int {
+	size := buckets[0].Burst()
+	for _, bucket := range buckets[1:] {
+		size = min(size, bucket.Burst())
+	}
+
+	return size
+}
+
+func nonNil(buckets []*rate.Limiter) []*rate.Limiter {
+	var result []*rate.Limiter
+	for _, bucket := range buckets {
+		if bucket != nil {
+			result = append(result, bucket)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+package shaper
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewWriter(context.Background(), &buf, NewBucket(1000, 100), nil)
+
+	start := time.Now()
+
+	// The first 100 bytes are sent at once, the next 200 bytes need 200ms worth of tokens.
+	n, err := writer.Write(bytes.Repeat([]byte("a"), 300))
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, n)
+	assert.Equal(t, 300, buf.Len())
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestWriter_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	writer := NewWriter(ctx, &buf, NewBucket(10, 10))
+
+	n, err := writer.Write(bytes.Repeat([]byte("a"), 30))
+	require.Error(t, err)
+
+	assert.Equal(t, 0, n)
+}
+
+func TestReader(t *testing.T) {
+	reader := NewReader(context.Background(), bytes.NewReader(bytes.Repeat([]byte("a"), 300)), NewBucket(1000, 100))
+
+	start := time.Now()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Len(t, data, 300)
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestReader_noBuckets(t *testing.T) {
+	reader := NewReader(context.Background(), bytes.NewReader([]byte("foo")), nil)
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", string(data))
+}
+
+func TestSources(t *testing.T) {
+	sources := NewSources(1000, 0)
+
+	read, write := sources.Acquire("10.0.0.1")
+	require.NotNil(t, read)
+	require.NotNil(t, write)
+	assert.NotSame(t, read, write)
+	assert.Equal(t, 1000, read.Burst())
+
+	// The connections from the same source share the same buckets.
+	otherRead, otherWrite := sources.Acquire("10.0.0.1")
+	assert.Same(t, read, otherRead)
+	assert.Same(t, write, otherWrite)
+
+	// The connections from another source have their own buckets.
+	otherRead, _ = sources.Acquire("10.0.0.2")
+	assert.NotSame(t, read, otherRead)
+
+	sources.Release("10.0.0.1")
+	assert.Len(t, sources.buckets, 2)
+
+	sources.Release("10.0.0.1")
+	sources.Release("10.0.0.2")
+	assert.Empty(t, sources.buckets)
+}
+
+func TestSources_nil(t *testing.T) {
+	sources := NewSources(0, 0)
+	require.Nil(t, sources)
+
+	read, write := sources.Acquire("10.0.0.1")
+	assert.Nil(t, read)
+	assert.Nil(t, write)
+
+	sources.Release("10.0.0.1")
+}
@@ -0,0 +1,173 @@
+package bodyrewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "BodyRewrite"
+
+// defaultMaxBodyBytes is the default maximum size of a response body kept in memory.
+const defaultMaxBodyBytes = 1024 * 1024
+
+// rule is a replacement of a literal value, or of the matches of a regular expression.
+type rule struct {
+	literal     []byte
+	regex       *regexp.Regexp
+	replacement []byte
+}
+
+func (r rule) apply(body []byte) []byte {
+	if r.regex != nil {
+		return r.regex.ReplaceAll(body, r.replacement)
+	}
+
+	return bytes.ReplaceAll(body, r.literal, r.replacement)
+}
+
+// bodyRewrite is a middleware rewriting the response bodies.
+type bodyRewrite struct {
+	next         http.Handler
+	name         string
+	contentTypes []string
+	rules        []rule
+	maxBodySize  int64
+
+	// streamable is whether all the rules can be applied to a stream, i.e. they all replace a literal value.
+	streamable bool
+}
+
+// New creates a new body rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.BodyRewrite, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if len(config.Rewrites) == 0 {
+		return nil, errors.New("at least one rewrite must be defined")
+	}
+
+	if len(config.ContentTypes) == 0 {
+		return nil, errors.New("at least one content type must be defined")
+	}
+
+	if config.MaxBodyBytes < 0 {
+		return nil, errors.New("maxBodyBytes must be positive")
+	}
+
+	var contentTypes []string
+	for _, contentType := range config.ContentTypes {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("parsing content type %q: %w", contentType, err)
+		}
+
+		contentTypes = append(contentTypes, mediaType)
+	}
+
+	streamable := true
+
+	var rules []rule
+	for i, rewrite := range config.Rewrites {
+		r := rule{replacement: []byte(rewrite.Replacement)}
+
+		switch {
+		case rewrite.Literal != "" && rewrite.Regex != "":
+			return nil, fmt.Errorf("rewrite %d: literal and regex options are mutually exclusive", i)
+
+		case rewrite.Regex != "":
+			var err error
+			r.regex, err = regexp.Compile(rewrite.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("rewrite %d: compiling regex: %w", i, err)
+			}
+
+			streamable = false
+
+		case rewrite.Literal != "":
+			r.literal = []byte(rewrite.Literal)
+
+		default:
+			return nil, fmt.Errorf("rewrite %d: literal or regex option must be defined", i)
+		}
+
+		rules = append(rules, r)
+	}
+
+	maxBodySize := config.MaxBodyBytes
+	if maxBodySize == 0 {
+		maxBodySize = defaultMaxBodyBytes
+	}
+
+	return &bodyRewrite{
+		next:         next,
+		name:         name,
+		contentTypes: contentTypes,
+		rules:        rules,
+		maxBodySize:  maxBodySize,
+		streamable:   streamable,
+	}, nil
+}
+
+func (b *bodyRewrite) GetTracingInformation() (string, string, trace.SpanKind) {
+	return b.name, typeName, trace.SpanKindInternal
+}
+
+func (b *bodyRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodHead {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), b.name, typeName)
+
+	w := &responseWriter{
+		rw:     rw,
+		config: b,
+		logger: logger,
+		code:   http.StatusOK,
+	}
+
+	b.next.ServeHTTP(middlewares.NewResponseModifier(w, req, w.modify), req)
+
+	if err := w.close(); err != nil {
+		logger.Debug().Err(err).Msg("Error while writing the rewritten response body")
+	}
+}
+
+// rewrite applies the rules, in order, to the given body.
+func (b *bodyRewrite) rewrite(body []byte) []byte {
+	for _, r := range b.rules {
+		body = r.apply(body)
+	}
+
+	return body
+}
+
+// matchContentType returns whether the given Content-Type header value matches one of the configured media types.
+func (b *bodyRewrite) matchContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, ct := range b.contentTypes {
+		if ct == mediaType {
+			return true
+		}
+
+		if typ, ok := strings.CutSuffix(ct, "/*"); ok && strings.HasPrefix(mediaType, typ+"/") {
+			return true
+		}
+	}
+
+	return false
+}
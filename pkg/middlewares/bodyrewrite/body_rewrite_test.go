@@ -0,0 +1,529 @@
+package bodyrewrite
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.BodyRewrite
+		expErr string
+	}{
+		{
+			desc: "valid configuration",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html", "application/*"},
+				Rewrites: []dynamic.BodyRewriteRule{
+					{Literal: "foo", Replacement: "bar"},
+					{Regex: "f(o+)", Replacement: "b$1"},
+				},
+			},
+		},
+		{
+			desc:   "no rewrites",
+			config: dynamic.BodyRewrite{ContentTypes: []string{"text/html"}},
+			expErr: "at least one rewrite must be defined",
+		},
+		{
+			desc: "no content types",
+			config: dynamic.BodyRewrite{
+				Rewrites: []dynamic.BodyRewriteRule{{Literal: "foo"}},
+			},
+			expErr: "at least one content type must be defined",
+		},
+		{
+			desc: "invalid content type",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html; charset"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Literal: "foo"}},
+			},
+			expErr: `parsing content type "text/html; charset": mime: invalid media parameter`,
+		},
+		{
+			desc: "negative max body bytes",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Literal: "foo"}},
+				MaxBodyBytes: -1,
+			},
+			expErr: "maxBodyBytes must be positive",
+		},
+		{
+			desc: "literal and regex",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Literal: "foo", Regex: "foo"}},
+			},
+			expErr: "rewrite 0: literal and regex options are mutually exclusive",
+		},
+		{
+			desc: "neither literal nor regex",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Literal: "foo"}, {Replacement: "bar"}},
+			},
+			expErr: "rewrite 1: literal or regex option must be defined",
+		},
+		{
+			desc: "invalid regex",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Regex: "foo("}},
+			},
+			expErr: "rewrite 0: compiling regex: error parsing regexp: missing closing ): `foo(`",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "rewrite")
+			if test.expErr != "" {
+				require.EqualError(t, err, test.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBodyRewrite(t *testing.T) {
+	const (
+		html      = `<a href="http://legacy.local/app/index.html">index</a> <a href="http://legacy.local/app/about.html">about</a>`
+		rewritten = `<a href="/legacy/app/index.html">index</a> <a href="/legacy/app/about.html">about</a>`
+	)
+
+	literal := []dynamic.BodyRewriteRule{{Literal: "http://legacy.local/", Replacement: "/legacy/"}}
+
+	testCases := []struct {
+		desc           string
+		method         string
+		config         dynamic.BodyRewrite
+		statusCode     int
+		header         http.Header
+		body           []byte
+		byteByByte     bool
+		expStatusCode  int
+		expBody        string
+		expHeader      http.Header
+		expContentLen  bool
+		expDecodedBody string
+		expOriginalLen bool
+	}{
+		{
+			desc:          "literal",
+			config:        dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:        http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			body:          []byte(html),
+			expStatusCode: http.StatusOK,
+			expBody:       rewritten,
+		},
+		{
+			desc:          "literal split across writes",
+			config:        dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:        http.Header{"Content-Type": {"text/html"}},
+			body:          []byte(html),
+			byteByByte:    true,
+			expStatusCode: http.StatusOK,
+			expBody:       rewritten,
+		},
+		{
+			desc: "literals in order",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites: []dynamic.BodyRewriteRule{
+					{Literal: "http://legacy.local/", Replacement: "/legacy/"},
+					{Literal: "/legacy/app/", Replacement: "/v2/"},
+				},
+			},
+			header:        http.Header{"Content-Type": {"text/html"}},
+			body:          []byte(html),
+			byteByByte:    true,
+			expStatusCode: http.StatusOK,
+			expBody:       `<a href="/v2/index.html">index</a> <a href="/v2/about.html">about</a>`,
+		},
+		{
+			desc: "regex",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"application/json"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Regex: `"http://[^/]+/(\w+)/`, Replacement: `"/$1/`}},
+			},
+			statusCode:    http.StatusCreated,
+			header:        http.Header{"Content-Type": {"application/json"}},
+			body:          []byte(`{"self":"http://legacy.local/app/1","next":"http://legacy.local/app/2"}`),
+			byteByByte:    true,
+			expStatusCode: http.StatusCreated,
+			expBody:       `{"self":"/app/1","next":"/app/2"}`,
+			expContentLen: true,
+		},
+		{
+			desc:          "wildcard content type",
+			config:        dynamic.BodyRewrite{ContentTypes: []string{"text/*"}, Rewrites: literal},
+			header:        http.Header{"Content-Type": {"text/html"}},
+			body:          []byte(html),
+			expStatusCode: http.StatusOK,
+			expBody:       rewritten,
+		},
+		{
+			desc:           "content type not matching",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/plain"}},
+			body:           []byte(html),
+			expStatusCode:  http.StatusOK,
+			expBody:        html,
+			expOriginalLen: true,
+		},
+		{
+			desc:           "HEAD request",
+			method:         http.MethodHead,
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}},
+			expStatusCode:  http.StatusOK,
+			expOriginalLen: true,
+		},
+		{
+			desc:          "not modified",
+			config:        dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			statusCode:    http.StatusNotModified,
+			header:        http.Header{"Content-Type": {"text/html"}, "Etag": {`"v1"`}},
+			expStatusCode: http.StatusNotModified,
+			expHeader:     http.Header{"Etag": {`"v1"`}},
+		},
+		{
+			desc:          "strong ETag",
+			config:        dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:        http.Header{"Content-Type": {"text/html"}, "Etag": {`"v1"`}},
+			body:          []byte(html),
+			expStatusCode: http.StatusOK,
+			expBody:       rewritten,
+			expHeader:     http.Header{"Etag": {`W/"v1"`}},
+		},
+		{
+			desc:           "gzip body",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"gzip"}},
+			body:           gzipEncode(t, []byte(html)),
+			expStatusCode:  http.StatusOK,
+			expContentLen:  true,
+			expHeader:      http.Header{"Content-Encoding": {"gzip"}},
+			expDecodedBody: rewritten,
+		},
+		{
+			desc:           "brotli body",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"br"}},
+			body:           brotliEncode(t, []byte(html)),
+			byteByByte:     true,
+			expStatusCode:  http.StatusOK,
+			expContentLen:  true,
+			expHeader:      http.Header{"Content-Encoding": {"br"}},
+			expDecodedBody: rewritten,
+		},
+		{
+			desc:           "zstd body",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"zstd"}},
+			body:           zstdEncode(t, []byte(html)),
+			expStatusCode:  http.StatusOK,
+			expContentLen:  true,
+			expHeader:      http.Header{"Content-Encoding": {"zstd"}},
+			expDecodedBody: rewritten,
+		},
+		{
+			desc:           "unsupported encoding",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"deflate"}},
+			body:           []byte(html),
+			expStatusCode:  http.StatusOK,
+			expBody:        html,
+			expOriginalLen: true,
+		},
+		{
+			desc:           "invalid gzip body",
+			config:         dynamic.BodyRewrite{ContentTypes: []string{"text/html"}, Rewrites: literal},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"gzip"}},
+			body:           []byte(html),
+			expStatusCode:  http.StatusOK,
+			expBody:        html,
+			expOriginalLen: true,
+		},
+		{
+			desc: "body larger than max body bytes",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     []dynamic.BodyRewriteRule{{Regex: "http://legacy.local/", Replacement: "/legacy/"}},
+				MaxBodyBytes: 20,
+			},
+			header:         http.Header{"Content-Type": {"text/html"}},
+			body:           []byte(html),
+			byteByByte:     true,
+			expStatusCode:  http.StatusOK,
+			expBody:        html,
+			expOriginalLen: true,
+		},
+		{
+			desc: "decoded body larger than max body bytes",
+			config: dynamic.BodyRewrite{
+				ContentTypes: []string{"text/html"},
+				Rewrites:     literal,
+				MaxBodyBytes: int64(len(html) - 1),
+			},
+			header:         http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"zstd"}},
+			body:           zstdEncode(t, []byte(html)),
+			expStatusCode:  http.StatusOK,
+			expHeader:      http.Header{"Content-Encoding": {"zstd"}},
+			expDecodedBody: html,
+			expOriginalLen: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for k, v := range test.header {
+					rw.Header()[k] = v
+				}
+				rw.Header().Set("Content-Length", strconv.Itoa(len(test.body)))
+
+				if test.statusCode != 0 {
+					rw.WriteHeader(test.statusCode)
+				}
+
+				if req.Method == http.MethodHead {
+					return
+				}
+
+				if !test.byteByByte {
+					_, _ = rw.Write(test.body)
+					return
+				}
+
+				for i := range test.body {
+					_, _ = rw.Write(test.body[i : i+1])
+					rw.(http.Flusher).Flush()
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "rewrite")
+			require.NoError(t, err)
+
+			method := http.MethodGet
+			if test.method != "" {
+				method = test.method
+			}
+
+			req := httptest.NewRequest(method, "http://localhost", nil)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expStatusCode, rw.Code)
+
+			for k, v := range test.expHeader {
+				assert.Equal(t, v, rw.Header().Values(k))
+			}
+
+			switch {
+			case test.expOriginalLen:
+				assert.Equal(t, strconv.Itoa(len(test.body)), rw.Header().Get("Content-Length"))
+			case test.expContentLen:
+				assert.Equal(t, strconv.Itoa(rw.Body.Len()), rw.Header().Get("Content-Length"))
+			case test.expStatusCode != http.StatusNotModified:
+				assert.Empty(t, rw.Header().Get("Content-Length"))
+			}
+
+			if test.expDecodedBody != "" {
+				assert.Equal(t, test.expDecodedBody, decodeBody(t, rw.Header().Get("Content-Encoding"), rw.Body.Bytes()))
+				return
+			}
+
+			assert.Equal(t, test.expBody, rw.Body.String())
+		})
+	}
+}
+
+func TestLiteralWriter(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		writes []string
+		old    string
+		new    string
+		exp    string
+	}{
+		{
+			desc:   "occurrence in a single write",
+			writes: []string{"aaa foo bbb"},
+			old:    "foo",
+			new:    "bar",
+			exp:    "aaa bar bbb",
+		},
+		{
+			desc:   "occurrence across writes",
+			writes: []string{"aaa f", "o", "o bbb f", "oo"},
+			old:    "foo",
+			new:    "bar",
+			exp:    "aaa bar bbb bar",
+		},
+		{
+			desc:   "partial occurrence at the end",
+			writes: []string{"aaa fo"},
+			old:    "foo",
+			new:    "bar",
+			exp:    "aaa fo",
+		},
+		{
+			desc:   "replacement containing the literal",
+			writes: []string{"foo", "foo"},
+			old:    "foo",
+			new:    "foofoo",
+			exp:    "foofoofoofoo",
+		},
+		{
+			desc:   "overlapping occurrences",
+			writes: []string{"aa", "aa", "a"},
+			old:    "aa",
+			new:    "b",
+			exp:    "bba",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := &literalWriter{w: &buf, old: []byte(test.old), new: []byte(test.new)}
+
+			for _, write := range test.writes {
+				n, err := w.Write([]byte(write))
+				require.NoError(t, err)
+				assert.Equal(t, len(write), n)
+			}
+
+			require.NoError(t, w.close())
+			assert.Equal(t, test.exp, buf.String())
+		})
+	}
+}
+
+func TestBodyRewrite_streaming(t *testing.T) {
+	written := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain")
+
+		_, _ = rw.Write([]byte(strings.Repeat("http://legacy.local/ ", 10)))
+		rw.(http.Flusher).Flush()
+
+		<-written
+	})
+
+	handler, err := New(context.Background(), next, dynamic.BodyRewrite{
+		ContentTypes: []string{"text/plain"},
+		Rewrites:     []dynamic.BodyRewriteRule{{Literal: "http://legacy.local/", Replacement: "/"}},
+	}, "rewrite")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The rewritten beginning of the body is received before the response is complete.
+	buf := make([]byte, 20)
+	_, err = io.ReadFull(resp.Body, buf)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("/ ", 10), string(buf))
+
+	close(written)
+
+	rest, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+}
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		r = gr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		r = bytes.NewReader(body)
+	}
+
+	decoded, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(decoded)
+}
+
+func gzipEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func brotliEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := brotli.NewWriter(&buf)
+
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func zstdEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	w, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	return w.EncodeAll(content, nil)
+}
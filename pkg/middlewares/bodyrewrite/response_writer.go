@@ -0,0 +1,362 @@
+package bodyrewrite
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+)
+
+type mode int
+
+const (
+	// passthrough forwards the response body unmodified.
+	passthrough mode = iota
+	// streaming rewrites the response body while it is forwarded.
+	streaming
+	// buffering keeps the response body in memory, to rewrite it once complete.
+	buffering
+)
+
+// responseWriter rewrites the response body, according to the response headers inspected by modify.
+type responseWriter struct {
+	rw     http.ResponseWriter
+	config *bodyRewrite
+	logger *zerolog.Logger
+
+	mode     mode
+	code     int
+	encoding string
+
+	// stages are the literal replacements applied in streaming mode, each one writing to the next.
+	stages []*literalWriter
+	buf    bytes.Buffer
+}
+
+// modify chooses how to rewrite the response body, before the response headers are sent.
+func (w *responseWriter) modify(resp *http.Response) error {
+	if !w.rewritable(resp) {
+		return nil
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "identity" {
+		encoding = ""
+	}
+
+	if encoding != "" && !supportedEncoding(encoding) {
+		w.logger.Debug().Msgf("Unsupported content encoding %q, the response body is not rewritten", encoding)
+		return nil
+	}
+
+	// The rewritten body no longer matches a strong validator.
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		resp.Header.Set("ETag", "W/"+etag)
+	}
+
+	w.encoding = encoding
+
+	if encoding == "" && w.config.streamable {
+		w.mode = streaming
+
+		// The length of the rewritten body is unknown until it is complete.
+		resp.Header.Del("Content-Length")
+
+		var next io.Writer = w.rw
+		w.stages = make([]*literalWriter, len(w.config.rules))
+		for i := len(w.config.rules) - 1; i >= 0; i-- {
+			w.stages[i] = &literalWriter{
+				w:   next,
+				old: w.config.rules[i].literal,
+				new: w.config.rules[i].replacement,
+			}
+			next = w.stages[i]
+		}
+
+		return nil
+	}
+
+	w.mode = buffering
+
+	return nil
+}
+
+// rewritable returns whether the response has a body to rewrite.
+func (w *responseWriter) rewritable(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode < http.StatusOK,
+		resp.StatusCode == http.StatusNoContent,
+		resp.StatusCode == http.StatusNotModified,
+		resp.StatusCode == http.StatusPartialContent:
+		return false
+	case resp.Header.Get("Content-Range") != "":
+		return false
+	default:
+		return w.config.matchContentType(resp.Header.Get("Content-Type"))
+	}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	// The status code is sent along with the rewritten body.
+	if w.mode == buffering && code >= http.StatusOK {
+		w.code = code
+		return
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	switch w.mode {
+	case streaming:
+		return w.stages[0].Write(p)
+
+	case buffering:
+		if int64(w.buf.Len()+len(p)) <= w.config.maxBodySize {
+			return w.buf.Write(p)
+		}
+
+		w.logger.Debug().Msg("Response body too large, it is forwarded without being rewritten")
+
+		w.mode = passthrough
+		w.rw.WriteHeader(w.code)
+
+		if _, err := w.rw.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		w.buf = bytes.Buffer{}
+
+		return w.rw.Write(p)
+
+	default:
+		return w.rw.Write(p)
+	}
+}
+
+// Flush sends the data written so far to the client.
+// In buffering mode, nothing is sent before the body is complete.
+// In streaming mode, the bytes which could be the beginning of a literal value are kept.
+func (w *responseWriter) Flush() {
+	if w.mode == buffering {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
+
+// close writes what is left of the response body, once the response is complete.
+func (w *responseWriter) close() error {
+	switch w.mode {
+	case streaming:
+		for _, stage := range w.stages {
+			if err := stage.close(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case buffering:
+		body := w.buf.Bytes()
+
+		if len(body) > 0 {
+			rewritten, err := w.rewrite(body)
+			if err != nil {
+				w.logger.Debug().Err(err).Msg("Unable to rewrite the response body, it is forwarded without being rewritten")
+			} else {
+				body = rewritten
+				w.rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			}
+		}
+
+		w.rw.WriteHeader(w.code)
+
+		_, err := w.rw.Write(body)
+		return err
+
+	default:
+		return nil
+	}
+}
+
+// rewrite returns the rewritten body, decoding it beforehand and encoding it again afterward, when it is compressed.
+func (w *responseWriter) rewrite(body []byte) ([]byte, error) {
+	if w.encoding == "" {
+		return w.config.rewrite(body), nil
+	}
+
+	decoded, err := decode(w.encoding, body, w.config.maxBodySize)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s body: %w", w.encoding, err)
+	}
+
+	encoded, err := encode(w.encoding, w.config.rewrite(decoded))
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s body: %w", w.encoding, err)
+	}
+
+	return encoded, nil
+}
+
+// literalWriter replaces the occurrences of a literal value in a stream.
+// It keeps back the trailing bytes which could be the beginning of an occurrence, until the next write.
+type literalWriter struct {
+	w        io.Writer
+	old, new []byte
+	pending  []byte
+}
+
+func (l *literalWriter) Write(p []byte) (int, error) {
+	l.pending = append(l.pending, p...)
+
+	var out []byte
+	for {
+		i := bytes.Index(l.pending, l.old)
+		if i < 0 {
+			break
+		}
+
+		out = append(out, l.pending[:i]...)
+		out = append(out, l.new...)
+		l.pending = l.pending[i+len(l.old):]
+	}
+
+	keep := partialOccurrence(l.pending, l.old)
+	out = append(out, l.pending[:len(l.pending)-keep]...)
+	l.pending = bytes.Clone(l.pending[len(l.pending)-keep:])
+
+	if len(out) > 0 {
+		if _, err := l.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// partialOccurrence returns the length of the longest suffix of data which is the beginning of the literal.
+func partialOccurrence(data, literal []byte) int {
+	for n := min(len(data), len(literal)-1); n > 0; n-- {
+		if bytes.HasSuffix(data, literal[:n]) {
+			return n
+		}
+	}
+
+	return 0
+}
+
+// close writes the bytes kept back, which cannot be an occurrence anymore.
+func (l *literalWriter) close() error {
+	if len(l.pending) == 0 {
+		return nil
+	}
+
+	_, err := l.w.Write(l.pending)
+	l.pending = nil
+
+	return err
+}
+
+func supportedEncoding(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "br", "zstd":
+		return true
+	default:
+		return false
+	}
+}
+
+// decode returns the decoded body, which must not be larger than the given limit.
+func decode(encoding string, body []byte, limit int64) ([]byte, error) {
+	var r io.Reader
+
+	switch encoding {
+	case "gzip", "x-gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gr.Close() }()
+
+		r = gr
+
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		r = zr
+	}
+
+	decoded, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(decoded)) > limit {
+		return nil, errors.New("decoded body too large")
+	}
+
+	return decoded, nil
+}
+
+func encode(encoding string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		w = gzip.NewWriter(&buf)
+
+	case "br":
+		w = brotli.NewWriter(&buf)
+
+	case "zstd":
+		zw, err := zstd.NewWriter(&buf, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		w = zw
+	}
+
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
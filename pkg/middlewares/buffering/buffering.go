@@ -2,6 +2,7 @@ package buffering
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/rs/zerolog"
@@ -9,6 +10,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
 	oxybuffer "github.com/vulcand/oxy/v2/buffer"
+	"github.com/vulcand/oxy/v2/utils"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -37,6 +39,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 		oxybuffer.Logger(logs.NewOxyWrapper(*logger)),
 		oxybuffer.Verbose(logger.GetLevel() == zerolog.TraceLevel),
 		oxybuffer.Cond(len(config.RetryExpression) > 0, oxybuffer.Retry(config.RetryExpression)),
+		oxybuffer.ErrorHandler(utils.ErrorHandlerFunc(errorHandler)),
 	)
 	if err != nil {
 		return nil, err
@@ -48,6 +51,18 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 	}, nil
 }
 
+// errorHandler answers with a 413 status code when reading the request body fails
+// because of the limit of a previous middleware, e.g. the decompress middleware.
+func errorHandler(rw http.ResponseWriter, req *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	(&oxybuffer.SizeErrHandler{}).ServeHTTP(rw, req, err)
+}
+
 func (b *buffer) GetTracingInformation() (string, string, trace.SpanKind) {
 	return b.name, typeName, trace.SpanKindInternal
 }
@@ -0,0 +1,625 @@
+// Package cache implements an HTTP cache middleware, following the caching rules of a shared cache (RFC 9111).
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "Cache"
+
+	defaultMaxSize              = 64 * 1024 * 1024
+	defaultMaxResponseBodyBytes = 1024 * 1024
+
+	// cacheStatusName identifies Traefik in the Cache-Status header (RFC 9211).
+	cacheStatusName = "traefik"
+)
+
+// notUpdatedHeaders holds the headers of a stored response which are not updated by a 304 (Not Modified) response.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-3.2.
+var notUpdatedHeaders = []string{"Content-Length", "Content-Encoding", "Content-Range", "Transfer-Encoding"}
+
+var (
+	storesMu sync.Mutex
+	// stores holds the stores, keyed by middleware name,
+	// so that the stored responses are kept across the configuration reloads.
+	stores = make(map[string]*registeredStore)
+)
+
+type registeredStore struct {
+	config string
+	store  store
+}
+
+// cache is a middleware which stores the responses of the next handler, and serves them again.
+type cache struct {
+	name        string
+	next        http.Handler
+	store       store
+	maxBodySize int64
+
+	// revalidations holds the keys of the responses being revalidated in the background.
+	revalidations sync.Map
+}
+
+// New creates a new cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxSize < 0 || config.MaxResponseBodyBytes < 0 {
+		return nil, errors.New("maxSize and maxResponseBodyBytes must be positive")
+	}
+
+	if config.MaxSize == 0 {
+		config.MaxSize = defaultMaxSize
+	}
+
+	if config.MaxResponseBodyBytes == 0 {
+		config.MaxResponseBodyBytes = defaultMaxResponseBodyBytes
+	}
+
+	s, err := getStore(name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache{
+		name:        name,
+		next:        next,
+		store:       s,
+		maxBodySize: config.MaxResponseBodyBytes,
+	}, nil
+}
+
+// getStore returns the store of the middleware with the given name,
+// which is only created again when the configuration of the middleware changes.
+func getStore(name string, config dynamic.Cache) (store, error) {
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	previous, ok := stores[name]
+	if ok && previous.config == string(key) {
+		return previous.store, nil
+	}
+
+	var s store = newMemoryStore(config.MaxSize)
+	if config.Disk != nil {
+		if config.Disk.Path == "" {
+			return nil, errors.New("disk path must be set")
+		}
+
+		path := filepath.Clean(config.Disk.Path)
+
+		// The files of a directory are removed when a store is created in it,
+		// which must not happen while the directory is used by another middleware.
+		for otherName, rs := range stores {
+			if ds, isDisk := rs.store.(*diskStore); isDisk && otherName != name && ds.path == path {
+				return nil, fmt.Errorf("disk path %q is already used by the cache middleware %s", config.Disk.Path, otherName)
+			}
+		}
+
+		// The previous store of the middleware keeps serving the requests until the new configuration is applied,
+		// so the files it stored in the same directory are taken over rather than removed.
+		var previousDisk *diskStore
+		if ok {
+			if ds, isDisk := previous.store.(*diskStore); isDisk && ds.path == path {
+				previousDisk = ds
+			}
+		}
+
+		s, err = newDiskStore(path, config.MaxSize, previousDisk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stores[name] = &registeredStore{config: string(key), store: s}
+
+	return s, nil
+}
+
+// Retain drops the stores of the cache middlewares which are not in the given list,
+// i.e. which have been removed from the configuration.
+func Retain(names map[string]struct{}) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	for name := range stores {
+		if _, ok := names[name]; !ok {
+			delete(stores, name)
+		}
+	}
+}
+
+// Purge removes the responses stored by the cache middleware with the given name.
+// When rawURL is not empty, only the response stored for this URL is removed.
+func Purge(name, rawURL string) error {
+	storesMu.Lock()
+	rs, ok := stores[name]
+	storesMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if rawURL == "" {
+		return rs.store.purge()
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing URL: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("URL must be absolute: %s", rawURL)
+	}
+
+	return rs.store.delete(urlKey(u.Scheme, u.Host, u.RequestURI()))
+}
+
+func (c *cache) GetTracingInformation() (string, string, trace.SpanKind) {
+	return c.name, typeName, trace.SpanKindInternal
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), c.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	key := requestKey(req)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.serveUnsafe(ctx, rw, req, key)
+		return
+	}
+
+	// The protocol upgrades are not cacheable.
+	if req.Header.Get("Upgrade") != "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if len(reqCC) == 0 && strings.EqualFold(req.Header.Get("Pragma"), "no-cache") {
+		reqCC["no-cache"] = ""
+	}
+
+	e, err := c.lookup(req, key)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error while looking up stored response")
+	}
+
+	if e == nil {
+		if reqCC.has("only-if-cached") {
+			serveGatewayTimeout(ctx, rw, "fwd=miss")
+			return
+		}
+
+		c.forward(ctx, rw, req, key, nil, "miss")
+		return
+	}
+
+	age := e.age(time.Now())
+	lifetime := freshnessLifetime(e.StatusCode, e.Header)
+	respCC := parseCacheControl(e.Header)
+
+	fresh := age < lifetime && !respCC.has("no-cache")
+	if fresh && acceptable(reqCC, age, lifetime) {
+		serveEntry(ctx, rw, req, e, age, "hit")
+		return
+	}
+
+	// Serving stale responses is forbidden by the revalidation directives,
+	// s-maxage implying proxy-revalidate.
+	if !fresh && !reqCC.has("no-cache") && !respCC.has("no-cache", "must-revalidate", "proxy-revalidate", "s-maxage") {
+		staleness := age - lifetime
+
+		if maxStale, ok := reqCC["max-stale"]; ok {
+			limit, valid := reqCC.duration("max-stale")
+			if maxStale == "" || valid && staleness <= limit {
+				serveEntry(ctx, rw, req, e, age, "hit")
+				return
+			}
+		}
+
+		if swr, ok := respCC.duration("stale-while-revalidate"); ok && staleness <= swr && req.Method == http.MethodGet {
+			serveEntry(ctx, rw, req, e, age, "hit; detail=stale-while-revalidate")
+			c.revalidateInBackground(ctx, req, key, e)
+			return
+		}
+	}
+
+	fwd := "stale"
+	if fresh {
+		fwd = "request"
+	}
+
+	if reqCC.has("only-if-cached") {
+		serveGatewayTimeout(ctx, rw, "fwd="+fwd)
+		return
+	}
+
+	// The responses to HEAD requests can not be stored, so they are not used to revalidate the stored response.
+	if req.Method == http.MethodHead {
+		c.forward(ctx, rw, req, key, nil, fwd)
+		return
+	}
+
+	c.forward(ctx, rw, req, key, e, fwd)
+}
+
+// serveUnsafe forwards a request with an unsafe method,
+// and invalidates the response stored for its URL when it succeeds.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-4.4.
+func (c *cache) serveUnsafe(ctx context.Context, rw http.ResponseWriter, req *http.Request, key string) {
+	if req.Method == http.MethodOptions || req.Method == http.MethodTrace {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	c.next.ServeHTTP(middlewares.NewResponseModifier(rw, req, func(resp *http.Response) error {
+		if resp.StatusCode < http.StatusBadRequest {
+			if err := c.store.delete(key); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Error while invalidating stored response")
+			}
+		}
+
+		return nil
+	}), req)
+}
+
+// lookup returns the response stored for the request, or nil if there is none.
+func (c *cache) lookup(req *http.Request, key string) (*entry, error) {
+	e, err := c.store.get(key)
+	if err != nil || e == nil || len(e.Vary) == 0 {
+		return e, err
+	}
+
+	return c.store.get(variantKey(key, e.Vary, req))
+}
+
+// set stores the response to the request.
+// The responses with a Vary header are stored for the values of the request headers they vary on,
+// and an entry listing these headers is stored for the request URL.
+func (c *cache) set(req *http.Request, key string, e *entry) error {
+	vary := varyNames(e.Header)
+	if len(vary) == 0 {
+		return c.store.set(key, e)
+	}
+
+	if err := c.store.set(key, &entry{Vary: vary, ResponseTime: e.ResponseTime}); err != nil {
+		return err
+	}
+
+	return c.store.set(variantKey(key, vary, req), e)
+}
+
+// forward forwards the request to the next handler, and stores the response when it is storable.
+// When a stale response is given, the request is made conditional to revalidate it,
+// and it is served again if it is not modified.
+// The response is not written when rw is nil.
+func (c *cache) forward(ctx context.Context, rw http.ResponseWriter, req *http.Request, key string, stale *entry, fwd string) {
+	outReq := req
+	if stale != nil {
+		outReq = revalidationRequest(req, stale)
+	}
+
+	rec := &recorder{
+		rw:          rw,
+		header:      make(http.Header),
+		maxBodySize: c.maxBodySize,
+	}
+
+	requestTime := time.Now()
+
+	w := middlewares.NewResponseModifier(rec, outReq, func(resp *http.Response) error {
+		if stale != nil && resp.StatusCode == http.StatusNotModified {
+			return nil
+		}
+
+		rec.forward = rw != nil
+		rec.store = storable(req, resp.StatusCode, resp.Header)
+		rec.cacheStatus = fmt.Sprintf("%s; fwd=%s; fwd-status=%d", cacheStatusName, fwd, resp.StatusCode)
+
+		return nil
+	})
+
+	c.next.ServeHTTP(w, outReq)
+
+	// Makes sure the modifier is called when the next handler does not write anything.
+	w.WriteHeader(http.StatusOK)
+
+	responseTime := time.Now()
+
+	if stale != nil && rec.code == http.StatusNotModified {
+		updated := updateEntry(stale, rec.header, requestTime, responseTime)
+
+		if storable(req, updated.StatusCode, updated.Header) {
+			if err := c.set(req, key, updated); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Error while storing response")
+			}
+		} else if err := c.store.delete(key); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Error while invalidating stored response")
+		}
+
+		if rw != nil {
+			serveEntry(ctx, rw, req, updated, updated.age(responseTime), "fwd="+fwd+"; fwd-status=304")
+		}
+
+		return
+	}
+
+	if !rec.store || rec.tooLarge {
+		return
+	}
+
+	e := &entry{
+		StatusCode:   rec.code,
+		Header:       rec.header,
+		Body:         rec.body.Bytes(),
+		ResponseTime: responseTime,
+		InitialAge:   initialAge(rec.header, requestTime, responseTime),
+	}
+
+	if err := c.set(req, key, e); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Error while storing response")
+	}
+}
+
+// revalidateInBackground revalidates the stale response, without blocking the request.
+func (c *cache) revalidateInBackground(ctx context.Context, req *http.Request, key string, stale *entry) {
+	if _, loaded := c.revalidations.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	outReq := req.Clone(context.WithoutCancel(ctx))
+	outReq.Body = http.NoBody
+	outReq.ContentLength = 0
+
+	go func() {
+		defer c.revalidations.Delete(key)
+		defer func() {
+			if err := recover(); err != nil {
+				log.Ctx(ctx).Error().Msgf("Error while revalidating stored response: %v", err)
+			}
+		}()
+
+		c.forward(outReq.Context(), nil, outReq, key, stale, "stale")
+	}()
+}
+
+// revalidationRequest returns a copy of the request, made conditional with the validators of the stale response.
+func revalidationRequest(req *http.Request, stale *entry) *http.Request {
+	outReq := req.Clone(req.Context())
+
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range"} {
+		outReq.Header.Del(name)
+	}
+
+	if etag := stale.Header.Get("ETag"); etag != "" {
+		outReq.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := stale.Header.Get("Last-Modified"); lastModified != "" {
+		outReq.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return outReq
+}
+
+// updateEntry returns a copy of the stale response, updated with the headers of a 304 (Not Modified) response.
+func updateEntry(stale *entry, header http.Header, requestTime, responseTime time.Time) *entry {
+	updated := *stale
+	updated.Header = stale.Header.Clone()
+
+	for name, values := range header {
+		if !slices.Contains(notUpdatedHeaders, name) {
+			updated.Header[name] = values
+		}
+	}
+
+	updated.ResponseTime = responseTime
+	updated.InitialAge = initialAge(header, requestTime, responseTime)
+
+	return &updated
+}
+
+// acceptable returns whether the request directives allow to serve the fresh response.
+func acceptable(reqCC cacheControl, age, lifetime time.Duration) bool {
+	if reqCC.has("no-cache") {
+		return false
+	}
+
+	if maxAge, ok := reqCC.duration("max-age"); ok && age > maxAge {
+		return false
+	}
+
+	if minFresh, ok := reqCC.duration("min-fresh"); ok && lifetime-age < minFresh {
+		return false
+	}
+
+	return true
+}
+
+func serveEntry(ctx context.Context, rw http.ResponseWriter, req *http.Request, e *entry, age time.Duration, status string) {
+	header := rw.Header()
+	for name, values := range e.Header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	header.Set("Age", strconv.FormatInt(int64(age.Seconds()), 10))
+	header.Add("Cache-Status", cacheStatusName+"; "+status)
+
+	if e.StatusCode == http.StatusOK && notModified(req, e.Header) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.WriteHeader(e.StatusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(e.Body); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Error while writing stored response")
+	}
+}
+
+func serveGatewayTimeout(ctx context.Context, rw http.ResponseWriter, status string) {
+	rw.Header().Add("Cache-Status", cacheStatusName+"; "+status)
+	rw.WriteHeader(http.StatusGatewayTimeout)
+
+	if _, err := rw.Write([]byte(http.StatusText(http.StatusGatewayTimeout))); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Error while writing response")
+	}
+}
+
+// requestKey returns the key of the response to the request, its target URI.
+func requestKey(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return urlKey(scheme, req.Host, req.URL.RequestURI())
+}
+
+func urlKey(scheme, host, requestURI string) string {
+	return strings.ToLower(scheme) + "://" + strings.ToLower(host) + requestURI
+}
+
+// variantKey returns the key of the response stored for the values of the request headers it varies on.
+func variantKey(key string, vary []string, req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(key)
+
+	for _, name := range vary {
+		b.WriteString("\n")
+		b.WriteString(name)
+		b.WriteString(": ")
+		b.WriteString(strings.Join(req.Header.Values(name), ", "))
+	}
+
+	return b.String()
+}
+
+// varyNames returns the canonical names of the request headers listed in the Vary header.
+func varyNames(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// recorder records the response of the next handler to store it,
+// and writes it to the client unless it is a 304 (Not Modified) response to a revalidation request.
+type recorder struct {
+	rw     http.ResponseWriter
+	header http.Header
+
+	code        int
+	wroteHeader bool
+	cacheStatus string
+
+	// forward and store are set by the response modifier, when the headers are written.
+	forward bool
+	store   bool
+
+	body        bytes.Buffer
+	maxBodySize int64
+	tooLarge    bool
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	// Handling informational headers.
+	if code >= 100 && code <= 199 {
+		if r.rw != nil {
+			copyHeader(r.rw.Header(), r.header)
+			r.rw.WriteHeader(code)
+		}
+		return
+	}
+
+	r.code = code
+	r.wroteHeader = true
+
+	if !r.forward {
+		return
+	}
+
+	copyHeader(r.rw.Header(), r.header)
+	r.rw.Header().Add("Cache-Status", r.cacheStatus)
+	r.rw.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.store && !r.tooLarge {
+		if int64(r.body.Len()+len(b)) > r.maxBodySize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	if !r.forward {
+		return len(b), nil
+	}
+
+	return r.rw.Write(b)
+}
+
+// Flush sends any buffered data to the client.
+func (r *recorder) Flush() {
+	if !r.forward {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = append([]string(nil), values...)
+	}
+}
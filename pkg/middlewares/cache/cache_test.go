@@ -0,0 +1,523 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type testRequest struct {
+	method string
+	header map[string]string
+
+	expectedCode        int
+	expectedBody        string
+	expectedCacheStatus string
+}
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Cache
+		handler       func(rw http.ResponseWriter, req *http.Request)
+		requests      []testRequest
+		expectedCalls int
+	}{
+		{
+			desc: "fresh response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "no-store response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60, no-store")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "private response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "private, max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "response without freshness nor validator",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "response with cookie",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Set-Cookie", "foo=bar")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "response larger than maxResponseBodyBytes",
+			config: dynamic.Cache{
+				MaxResponseBodyBytes: 2,
+			},
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "revalidation with ETag",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "no-cache")
+				rw.Header().Set("ETag", `"v1"`)
+				if req.Header.Get("If-None-Match") == `"v1"` {
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=stale; fwd-status=304"},
+				{
+					header:              map[string]string{"If-None-Match": `"v1"`},
+					expectedCode:        http.StatusNotModified,
+					expectedCacheStatus: "traefik; fwd=stale; fwd-status=304",
+				},
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc: "revalidation with Last-Modified",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=0")
+				rw.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+				if req.Header.Get("If-Modified-Since") != "" {
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=stale; fwd-status=304"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "revalidation with modified response",
+			handler: func() func(rw http.ResponseWriter, req *http.Request) {
+				var calls int
+				return func(rw http.ResponseWriter, req *http.Request) {
+					calls++
+					rw.Header().Set("Cache-Control", "no-cache")
+					rw.Header().Set("ETag", strconv.Quote(strconv.Itoa(calls)))
+					_, _ = rw.Write([]byte(strconv.Itoa(calls)))
+				}
+			}(),
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "1"},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedCacheStatus: "traefik; fwd=stale; fwd-status=200"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "conditional request on a fresh response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("ETag", `W/"v1"`)
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{
+					header:              map[string]string{"If-None-Match": `"v0", "v1"`},
+					expectedCode:        http.StatusNotModified,
+					expectedCacheStatus: "traefik; hit",
+				},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "request with no-cache",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{
+					header:              map[string]string{"Cache-Control": "no-cache"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; fwd=request; fwd-status=200",
+				},
+				{
+					header:              map[string]string{"Pragma": "no-cache"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; fwd=request; fwd-status=200",
+				},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc: "request with max-age",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Age", "30")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{
+					header:              map[string]string{"Cache-Control": "max-age=40"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; hit",
+				},
+				{
+					header:              map[string]string{"Cache-Control": "max-age=10"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; fwd=request; fwd-status=200",
+				},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "request with max-stale",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Age", "90")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{
+					header:              map[string]string{"Cache-Control": "max-stale=60"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; hit",
+				},
+				{
+					header:              map[string]string{"Cache-Control": "max-stale=10"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; fwd=stale; fwd-status=200",
+				},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "request with only-if-cached",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{
+					header:              map[string]string{"Cache-Control": "only-if-cached"},
+					expectedCode:        http.StatusGatewayTimeout,
+					expectedBody:        "Gateway Timeout",
+					expectedCacheStatus: "traefik; fwd=miss",
+				},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{
+					header:              map[string]string{"Cache-Control": "only-if-cached"},
+					expectedCode:        http.StatusOK,
+					expectedBody:        "foo",
+					expectedCacheStatus: "traefik; hit",
+				},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "response with Vary",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Vary", "Accept-Language")
+				_, _ = rw.Write([]byte(req.Header.Get("Accept-Language")))
+			},
+			requests: []testRequest{
+				{header: map[string]string{"Accept-Language": "en"}, expectedCode: http.StatusOK, expectedBody: "en"},
+				{header: map[string]string{"Accept-Language": "fr"}, expectedCode: http.StatusOK, expectedBody: "fr"},
+				{header: map[string]string{"Accept-Language": "fr"}, expectedCode: http.StatusOK, expectedBody: "fr", expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "response with Vary *",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.Header().Set("Vary", "*")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "request with Authorization",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{header: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="}, expectedCode: http.StatusOK, expectedBody: "foo"},
+				{header: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="}, expectedCode: http.StatusOK, expectedBody: "foo"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "request with Authorization and public response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "public, max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{header: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="}, expectedCode: http.StatusOK, expectedBody: "foo"},
+				{header: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="}, expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "HEAD request served from the stored response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				if req.Method != http.MethodHead {
+					_, _ = rw.Write([]byte("foo"))
+				}
+			},
+			requests: []testRequest{
+				{method: http.MethodHead, expectedCode: http.StatusOK},
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{method: http.MethodHead, expectedCode: http.StatusOK, expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc: "unsafe request invalidates the stored response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{method: http.MethodPost, expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; fwd=miss; fwd-status=200"},
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc: "stored not found response",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				rw.WriteHeader(http.StatusNotFound)
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusNotFound},
+				{expectedCode: http.StatusNotFound, expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "disk storage",
+			config: dynamic.Cache{
+				Disk: &dynamic.CacheDisk{Path: t.TempDir()},
+			},
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("foo"))
+			},
+			requests: []testRequest{
+				{expectedCode: http.StatusOK, expectedBody: "foo"},
+				{expectedCode: http.StatusOK, expectedBody: "foo", expectedCacheStatus: "traefik; hit"},
+			},
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				calls++
+				test.handler(rw, req)
+			})
+
+			handler := newTestCache(t, next, test.config, test.desc)
+
+			for i, r := range test.requests {
+				method := r.method
+				if method == "" {
+					method = http.MethodGet
+				}
+
+				req := httptest.NewRequest(method, "http://localhost/foo", nil)
+				for name, value := range r.header {
+					req.Header.Set(name, value)
+				}
+
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, r.expectedCode, rw.Code, "request %d", i)
+				assert.Equal(t, r.expectedBody, strings.TrimSpace(rw.Body.String()), "request %d", i)
+
+				if r.expectedCacheStatus != "" {
+					assert.Equal(t, r.expectedCacheStatus, rw.Header().Get("Cache-Status"), "request %d", i)
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestCache_staleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int64
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		n := calls.Add(1)
+
+		rw.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+		_, _ = rw.Write([]byte(strconv.FormatInt(n, 10)))
+	})
+
+	handler := newTestCache(t, next, dynamic.Cache{}, "stale-while-revalidate")
+
+	serve := func() *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+		return rw
+	}
+
+	assert.Equal(t, "1", serve().Body.String())
+
+	// The stale response is served, while it is revalidated in the background.
+	rw := serve()
+	assert.Equal(t, "1", rw.Body.String())
+	assert.Equal(t, "traefik; hit; detail=stale-while-revalidate", rw.Header().Get("Cache-Status"))
+
+	assert.Eventually(t, func() bool {
+		return serve().Body.String() == "2"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCache_reload(t *testing.T) {
+	var calls int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	serve := func(config dynamic.Cache) {
+		handler := newTestCache(t, next, config, "reload")
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	}
+
+	serve(dynamic.Cache{})
+	serve(dynamic.Cache{})
+	assert.Equal(t, 1, calls)
+
+	// The responses stored with another configuration are dropped.
+	serve(dynamic.Cache{MaxSize: 1024})
+	assert.Equal(t, 2, calls)
+}
+
+func TestGetStore_diskPath(t *testing.T) {
+	dir := t.TempDir()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler := newTestCache(t, next, dynamic.Cache{Disk: &dynamic.CacheDisk{Path: dir}}, "disk")
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	// The directory of a live store is not shared with another middleware.
+	_, err := New(context.Background(), next, dynamic.Cache{Disk: &dynamic.CacheDisk{Path: dir + "/"}}, "other")
+	require.Error(t, err)
+
+	// The files of the previous store of the middleware are kept when its configuration changes.
+	newTestCache(t, next, dynamic.Cache{Disk: &dynamic.CacheDisk{Path: dir}, MaxSize: 1024 * 1024}, "disk")
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// The store of a middleware removed from the configuration is dropped.
+	Retain(map[string]struct{}{})
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	assert.Empty(t, stores)
+}
+
+// newTestCache creates a cache middleware, whose store is dropped at the end of the test.
+func newTestCache(t *testing.T, next http.Handler, config dynamic.Cache, name string) http.Handler {
+	t.Helper()
+
+	handler, err := New(context.Background(), next, config, name)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		storesMu.Lock()
+		delete(stores, name)
+		storesMu.Unlock()
+	})
+
+	return handler
+}
@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// heuristicFreshnessMax caps the heuristic freshness lifetime of the responses without explicit expiration time.
+const heuristicFreshnessMax = 24 * time.Hour
+
+// heuristicallyCacheable holds the status codes defined as heuristically cacheable.
+// See https://www.rfc-editor.org/rfc/rfc9110#section-15.1.
+var heuristicallyCacheable = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusPartialContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+// cacheControl holds the directives of a Cache-Control header, keyed by lowercase name.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := make(cacheControl)
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+
+			cc[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+
+	return cc
+}
+
+// has returns whether one of the given directives is set.
+func (cc cacheControl) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := cc[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// duration returns the value of the given delta-seconds directive.
+// It returns false when the directive is not set or its value is invalid.
+func (cc cacheControl) duration(name string) (time.Duration, bool) {
+	arg, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// storable returns whether a shared cache may store the response to the given request.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-3.
+func storable(req *http.Request, statusCode int, header http.Header) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	respCC := parseCacheControl(header)
+
+	if reqCC.has("no-store") || respCC.has("no-store", "private") {
+		return false
+	}
+
+	// Partial responses are not supported.
+	if statusCode == http.StatusPartialContent || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	// The cookies are specific to a client.
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+
+	if slices.Contains(header.Values("Vary"), "*") {
+		return false
+	}
+
+	if req.Header.Get("Authorization") != "" && !respCC.has("public", "s-maxage", "must-revalidate") {
+		return false
+	}
+
+	explicit := respCC.has("public", "s-maxage", "max-age") || header.Get("Expires") != ""
+	if !explicit && !slices.Contains(heuristicallyCacheable, statusCode) {
+		return false
+	}
+
+	if statusCode < http.StatusOK || statusCode == http.StatusNotModified {
+		return false
+	}
+
+	// A response which is never fresh is only useful when it can be revalidated, or served stale.
+	return freshnessLifetime(statusCode, header) > 0 || respCC.has("stale-while-revalidate") ||
+		header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
+
+// freshnessLifetime returns the duration during which the response can be served without revalidation.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-4.2.1.
+func freshnessLifetime(statusCode int, header http.Header) time.Duration {
+	cc := parseCacheControl(header)
+
+	if lifetime, ok := cc.duration("s-maxage"); ok {
+		return lifetime
+	}
+
+	if lifetime, ok := cc.duration("max-age"); ok {
+		return lifetime
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = time.Now()
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		// An invalid date, like "0", represents a time in the past.
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+
+		return max(expiresAt.Sub(date), 0)
+	}
+
+	if !slices.Contains(heuristicallyCacheable, statusCode) {
+		return 0
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return 0
+	}
+
+	return min(max(date.Sub(lastModified)/10, 0), heuristicFreshnessMax)
+}
+
+// initialAge returns the age of the response when it is received.
+// See https://www.rfc-editor.org/rfc/rfc9111#section-4.2.3.
+func initialAge(header http.Header, requestTime, responseTime time.Time) time.Duration {
+	var apparentAge time.Duration
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		apparentAge = max(responseTime.Sub(date), 0)
+	}
+
+	var ageValue time.Duration
+	if age, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && age > 0 {
+		ageValue = time.Duration(age) * time.Second
+	}
+
+	return max(apparentAge, ageValue+responseTime.Sub(requestTime))
+}
+
+// notModified returns whether the conditional headers of the request match the given response headers.
+// See https://www.rfc-editor.org/rfc/rfc9110#section-13.2.2.
+func notModified(req *http.Request, header http.Header) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ims)
+}
@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreshnessLifetime(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		statusCode int
+		header     http.Header
+		expected   time.Duration
+	}{
+		{
+			desc:       "no freshness information",
+			statusCode: http.StatusOK,
+			header:     http.Header{},
+		},
+		{
+			desc:       "max-age",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"public, max-age=60"}},
+			expected:   time.Minute,
+		},
+		{
+			desc:       "s-maxage takes precedence over max-age",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"max-age=60, s-maxage=120"}},
+			expected:   2 * time.Minute,
+		},
+		{
+			desc:       "max-age takes precedence over Expires",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Date":          {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				"Expires":       {"Mon, 02 Jan 2006 16:04:05 GMT"},
+			},
+			expected: time.Minute,
+		},
+		{
+			desc:       "invalid max-age",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"max-age=foo"}},
+		},
+		{
+			desc:       "Expires",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Date":    {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				"Expires": {"Mon, 02 Jan 2006 16:04:05 GMT"},
+			},
+			expected: time.Hour,
+		},
+		{
+			desc:       "invalid Expires",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Expires":       {"0"},
+				"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+			},
+		},
+		{
+			desc:       "heuristic freshness",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Date":          {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				"Last-Modified": {"Mon, 02 Jan 2006 05:04:05 GMT"},
+			},
+			expected: time.Hour,
+		},
+		{
+			desc:       "capped heuristic freshness",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Date":          {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				"Last-Modified": {"Mon, 02 Jan 2000 15:04:05 GMT"},
+			},
+			expected: heuristicFreshnessMax,
+		},
+		{
+			desc:       "no heuristic freshness for a status code which is not heuristically cacheable",
+			statusCode: http.StatusFound,
+			header: http.Header{
+				"Date":          {"Mon, 02 Jan 2006 15:04:05 GMT"},
+				"Last-Modified": {"Mon, 02 Jan 2006 05:04:05 GMT"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, freshnessLifetime(test.statusCode, test.header))
+		})
+	}
+}
+
+func TestInitialAge(t *testing.T) {
+	requestTime := time.Date(2006, time.January, 2, 15, 4, 0, 0, time.UTC)
+	responseTime := requestTime.Add(time.Second)
+
+	testCases := []struct {
+		desc     string
+		header   http.Header
+		expected time.Duration
+	}{
+		{
+			desc:     "response delay",
+			header:   http.Header{},
+			expected: time.Second,
+		},
+		{
+			desc:     "Age header",
+			header:   http.Header{"Age": {"30"}},
+			expected: 31 * time.Second,
+		},
+		{
+			desc:     "apparent age",
+			header:   http.Header{"Date": {"Mon, 02 Jan 2006 15:03:01 GMT"}},
+			expected: time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, initialAge(test.header, requestTime, responseTime))
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	header := http.Header{
+		"Etag":          {`"v1"`},
+		"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"},
+	}
+
+	testCases := []struct {
+		desc      string
+		reqHeader map[string]string
+		expected  bool
+	}{
+		{
+			desc: "no conditional header",
+		},
+		{
+			desc:      "matching ETag",
+			reqHeader: map[string]string{"If-None-Match": `"v0", W/"v1"`},
+			expected:  true,
+		},
+		{
+			desc:      "wildcard ETag",
+			reqHeader: map[string]string{"If-None-Match": "*"},
+			expected:  true,
+		},
+		{
+			desc: "If-None-Match takes precedence over If-Modified-Since",
+			reqHeader: map[string]string{
+				"If-None-Match":     `"v0"`,
+				"If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT",
+			},
+		},
+		{
+			desc:      "not modified since",
+			reqHeader: map[string]string{"If-Modified-Since": "Mon, 02 Jan 2006 15:04:05 GMT"},
+			expected:  true,
+		},
+		{
+			desc:      "modified since",
+			reqHeader: map[string]string{"If-Modified-Since": "Mon, 02 Jan 2006 15:04:04 GMT"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for name, value := range test.reqHeader {
+				req.Header.Set(name, value)
+			}
+
+			assert.Equal(t, test.expected, notModified(req, header))
+		})
+	}
+}
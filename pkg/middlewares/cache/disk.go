@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const diskFileExt = ".cache"
+
+// diskStore stores the responses in files on the local disk.
+// The index of the files is kept in memory, so the files written by a previous process are removed on creation,
+// unless they are taken over from the previous store of the middleware.
+type diskStore struct {
+	path string
+
+	mu  sync.Mutex
+	lru *lru
+}
+
+// newDiskStore creates a store in the given directory.
+// The files indexed by the previous store, if not nil, are taken over instead of removing the files of the directory.
+func newDiskStore(path string, maxSize int64, previous *diskStore) (*diskStore, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	s := &diskStore{
+		path: path,
+		lru:  newLRU(maxSize),
+	}
+
+	if previous != nil {
+		if err := s.takeOver(previous); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	}
+
+	if err := s.removeFiles(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// takeOver indexes the files of the given store, from the least recently used one,
+// and removes the ones which do not fit in the maximum size.
+func (s *diskStore) takeOver(previous *diskStore) error {
+	previous.mu.Lock()
+	var items []lruItem
+	for elt := previous.lru.order.Back(); elt != nil; elt = elt.Prev() {
+		items = append(items, *elt.Value.(*lruItem))
+	}
+	previous.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range items {
+		for _, evicted := range s.lru.add(item.key, item.size, nil) {
+			if err := os.Remove(s.filename(evicted)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("removing cache file: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *diskStore) get(key string) (*entry, error) {
+	s.mu.Lock()
+	_, ok := s.lru.get(key)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(s.filename(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		// The entry has been evicted in the meantime.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache file: %w", err)
+	}
+
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return nil, fmt.Errorf("decoding cache file: %w", err)
+	}
+
+	return &e, nil
+}
+
+func (s *diskStore) set(key string, e *entry) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	// The entry is written to a temporary file first, so that it is never read partially written.
+	tmp, err := os.CreateTemp(s.path, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+
+	_, err = tmp.Write(buf.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), s.filename(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	for _, evicted := range s.lru.add(key, int64(buf.Len()), nil) {
+		if err := os.Remove(s.filename(evicted)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing cache file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *diskStore) delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lru.remove(key) {
+		return nil
+	}
+
+	if err := os.Remove(s.filename(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing cache file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *diskStore) purge() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lru.clear()
+
+	return s.removeFiles()
+}
+
+// removeFiles removes the cache files, and the temporary files, from the cache directory.
+func (s *diskStore) removeFiles() error {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	for _, dirEntry := range entries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || filepath.Ext(name) != diskFileExt && !strings.HasPrefix(name, "tmp-") {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(s.path, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing cache file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// filename returns the path of the file storing the entry with the given key.
+func (s *diskStore) filename(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.path, hex.EncodeToString(hash[:])+diskFileExt)
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// entry is a stored response.
+// Its fields are exported to be encoded by the disk store.
+type entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Vary holds the names of the request headers selecting the stored response,
+	// when the entry only points to the responses stored for each of their values.
+	Vary []string
+
+	// ResponseTime is the time when the response was received.
+	ResponseTime time.Time
+	// InitialAge is the age of the response when it was received.
+	InitialAge time.Duration
+}
+
+// age returns the current age of the stored response.
+func (e *entry) age(now time.Time) time.Duration {
+	return e.InitialAge + max(now.Sub(e.ResponseTime), 0)
+}
+
+// size returns an estimation of the memory used by the entry.
+func (e *entry) size() int64 {
+	size := int64(len(e.Body))
+	for name, values := range e.Header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+
+	for _, name := range e.Vary {
+		size += int64(len(name))
+	}
+
+	return size
+}
+
+// store stores the responses, keyed by request.
+type store interface {
+	// get returns the entry with the given key, or nil if there is none.
+	get(key string) (*entry, error)
+	set(key string, e *entry) error
+	delete(key string) error
+	purge() error
+}
+
+// lru tracks the size of the stored entries, and evicts the least recently used ones when the maximum size is exceeded.
+// It is not safe for concurrent use.
+type lru struct {
+	maxSize int64
+	size    int64
+	items   map[string]*list.Element
+	order   *list.List
+}
+
+type lruItem struct {
+	key   string
+	size  int64
+	value *entry
+}
+
+func newLRU(maxSize int64) *lru {
+	return &lru{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the item with the given key, and marks it as the most recently used.
+func (l *lru) get(key string) (*lruItem, bool) {
+	elt, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	l.order.MoveToFront(elt)
+
+	return elt.Value.(*lruItem), true
+}
+
+// add adds or replaces the item with the given key, and returns the keys of the evicted items.
+func (l *lru) add(key string, size int64, value *entry) []string {
+	l.remove(key)
+
+	l.items[key] = l.order.PushFront(&lruItem{key: key, size: size, value: value})
+	l.size += size
+
+	var evicted []string
+	for l.size > l.maxSize {
+		item := l.order.Back().Value.(*lruItem)
+		l.remove(item.key)
+		evicted = append(evicted, item.key)
+	}
+
+	return evicted
+}
+
+// remove removes the item with the given key, and returns whether it existed.
+func (l *lru) remove(key string) bool {
+	elt, ok := l.items[key]
+	if !ok {
+		return false
+	}
+
+	l.order.Remove(elt)
+	delete(l.items, key)
+	l.size -= elt.Value.(*lruItem).size
+
+	return true
+}
+
+func (l *lru) clear() {
+	l.items = make(map[string]*list.Element)
+	l.order.Init()
+	l.size = 0
+}
+
+// memoryStore stores the responses in memory.
+type memoryStore struct {
+	mu  sync.Mutex
+	lru *lru
+}
+
+func newMemoryStore(maxSize int64) *memoryStore {
+	return &memoryStore{lru: newLRU(maxSize)}
+}
+
+func (s *memoryStore) get(key string) (*entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.lru.get(key)
+	if !ok {
+		return nil, nil
+	}
+
+	return item.value, nil
+}
+
+func (s *memoryStore) set(key string, e *entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lru.add(key, e.size()+int64(len(key)), e)
+
+	return nil
+}
+
+func (s *memoryStore) delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lru.remove(key)
+
+	return nil
+}
+
+func (s *memoryStore) purge() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lru.clear()
+
+	return nil
+}
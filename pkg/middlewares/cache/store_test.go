@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		newStore func(t *testing.T, maxSize int64) store
+		// maxSize fits two of the three entries, given the size of their encoding.
+		maxSize int64
+	}{
+		{
+			desc:    "memory",
+			maxSize: 1100,
+			newStore: func(t *testing.T, maxSize int64) store {
+				t.Helper()
+
+				return newMemoryStore(maxSize)
+			},
+		},
+		{
+			desc:    "disk",
+			maxSize: 1500,
+			newStore: func(t *testing.T, maxSize int64) store {
+				t.Helper()
+
+				s, err := newDiskStore(t.TempDir(), maxSize, nil)
+				require.NoError(t, err)
+
+				return s
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s := test.newStore(t, test.maxSize)
+
+			newEntry := func(body string) *entry {
+				return &entry{
+					StatusCode:   http.StatusOK,
+					Header:       http.Header{"Content-Type": {"text/plain"}},
+					Body:         []byte(body),
+					ResponseTime: time.Now().Truncate(time.Second),
+				}
+			}
+
+			e, err := s.get("foo")
+			require.NoError(t, err)
+			assert.Nil(t, e)
+
+			require.NoError(t, s.set("foo", newEntry("foo")))
+			require.NoError(t, s.set("bar", newEntry("bar")))
+
+			e, err = s.get("foo")
+			require.NoError(t, err)
+			require.NotNil(t, e)
+			assert.Equal(t, "foo", string(e.Body))
+			assert.Equal(t, "text/plain", e.Header.Get("Content-Type"))
+
+			// Evicts bar, the least recently used entry.
+			require.NoError(t, s.set("baz", newEntry(string(make([]byte, 1024)))))
+
+			e, err = s.get("bar")
+			require.NoError(t, err)
+			assert.Nil(t, e)
+
+			e, err = s.get("foo")
+			require.NoError(t, err)
+			assert.NotNil(t, e)
+
+			require.NoError(t, s.delete("foo"))
+
+			e, err = s.get("foo")
+			require.NoError(t, err)
+			assert.Nil(t, e)
+
+			require.NoError(t, s.purge())
+
+			e, err = s.get("baz")
+			require.NoError(t, err)
+			assert.Nil(t, e)
+		})
+	}
+}
+
+func TestNewDiskStore_removesFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := newDiskStore(dir, 1024, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.set("foo", &entry{StatusCode: http.StatusOK}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), nil, 0o600))
+
+	_, err = newDiskStore(dir, 1024, nil)
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "other", files[0].Name())
+}
+
+func TestNewDiskStore_takesOverFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	previous, err := newDiskStore(dir, 1024, nil)
+	require.NoError(t, err)
+	require.NoError(t, previous.set("foo", &entry{StatusCode: http.StatusOK}))
+	require.NoError(t, previous.set("bar", &entry{StatusCode: http.StatusNotFound}))
+
+	s, err := newDiskStore(dir, 1024, previous)
+	require.NoError(t, err)
+
+	// The previous store keeps serving its files.
+	e, err := previous.get("foo")
+	require.NoError(t, err)
+	require.NotNil(t, e)
+
+	e, err = s.get("bar")
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	assert.Equal(t, http.StatusNotFound, e.StatusCode)
+}
@@ -7,12 +7,13 @@ import (
 	"mime"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/klauspost/compress/gzhttp"
+	"github.com/klauspost/compress/gzip"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
-	"github.com/traefik/traefik/v3/pkg/middlewares/compress/brotli"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -22,6 +23,9 @@ const typeName = "Compress"
 // See https://github.com/klauspost/compress/blob/9559b037e79ad673c71f6ef7c732c00949014cd2/gzhttp/compress.go#L47.
 const DefaultMinSize = 1024
 
+// defaultEncodings is the default list of supported encodings, by order of preference.
+var defaultEncodings = []string{brotliName, zstdName, gzipName}
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
 	next     http.Handler
@@ -29,9 +33,12 @@ type compress struct {
 	excludes []string
 	includes []string
 	minSize  int
+	// encodings is the list of supported encodings, by order of preference.
+	encodings []string
 
 	brotliHandler http.Handler
 	gzipHandler   http.Handler
+	zstdHandler   http.Handler
 }
 
 // New creates a new compress middleware.
@@ -67,23 +74,35 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		minSize = conf.MinResponseBodyBytes
 	}
 
-	c := &compress{
-		next:     next,
-		name:     name,
-		excludes: excludes,
-		includes: includes,
-		minSize:  minSize,
+	encodings := defaultEncodings
+	if len(conf.Encodings) > 0 {
+		encodings = conf.Encodings
 	}
 
-	var err error
-	c.brotliHandler, err = c.newBrotliHandler()
-	if err != nil {
-		return nil, err
-	}
-
-	c.gzipHandler, err = c.newGzipHandler()
-	if err != nil {
-		return nil, err
+	c := &compress{
+		next:      next,
+		name:      name,
+		excludes:  excludes,
+		includes:  includes,
+		minSize:   minSize,
+		encodings: encodings,
+	}
+
+	for _, encoding := range encodings {
+		var err error
+		switch encoding {
+		case brotliName:
+			c.brotliHandler, err = c.newCompressionHandler(brotliName, conf.BrotliLevel)
+		case zstdName:
+			c.zstdHandler, err = c.newCompressionHandler(zstdName, conf.ZstdLevel)
+		case gzipName:
+			c.gzipHandler, err = c.newGzipHandler(conf.GzipLevel)
+		default:
+			err = fmt.Errorf("unsupported encoding: %q", encoding)
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return c, nil
@@ -117,24 +136,29 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if encodingAccepts(acceptEncoding, "br") {
+	switch c.chooseEncoding(acceptEncoding) {
+	case brotliName:
 		c.brotliHandler.ServeHTTP(rw, req)
-		return
-	}
-
-	if encodingAccepts(acceptEncoding, "gzip") {
+	case zstdName:
+		c.zstdHandler.ServeHTTP(rw, req)
+	case gzipName:
 		c.gzipHandler.ServeHTTP(rw, req)
-		return
+	default:
+		c.next.ServeHTTP(rw, req)
 	}
-
-	c.next.ServeHTTP(rw, req)
 }
 
 func (c *compress) GetTracingInformation() (string, string, trace.SpanKind) {
 	return c.name, typeName, trace.SpanKindInternal
 }
 
-func (c *compress) newGzipHandler() (http.Handler, error) {
+func (c *compress) newGzipHandler(level int) (http.Handler, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	} else if level < gzip.BestSpeed || level > gzip.BestCompression {
+		return nil, fmt.Errorf("invalid gzip compression level: %d", level)
+	}
+
 	var wrapper func(http.Handler) http.HandlerFunc
 	var err error
 
@@ -142,11 +166,13 @@ func (c *compress) newGzipHandler() (http.Handler, error) {
 		wrapper, err = gzhttp.NewWrapper(
 			gzhttp.ContentTypes(c.includes),
 			gzhttp.MinSize(c.minSize),
+			gzhttp.CompressionLevel(level),
 		)
 	} else {
 		wrapper, err = gzhttp.NewWrapper(
 			gzhttp.ExceptContentTypes(c.excludes),
 			gzhttp.MinSize(c.minSize),
+			gzhttp.CompressionLevel(level),
 		)
 	}
 
@@ -157,34 +183,72 @@ func (c *compress) newGzipHandler() (http.Handler, error) {
 	return wrapper(c.next), nil
 }
 
-func (c *compress) newBrotliHandler() (http.Handler, error) {
-	cfg := brotli.Config{MinSize: c.minSize}
+func (c *compress) newCompressionHandler(algorithm string, level int) (http.Handler, error) {
+	cfg := Config{Algorithm: algorithm, Level: level, MinSize: c.minSize}
 	if len(c.includes) > 0 {
 		cfg.IncludedContentTypes = c.includes
 	} else {
 		cfg.ExcludedContentTypes = c.excludes
 	}
 
-	wrapper, err := brotli.NewWrapper(cfg)
+	wrapper, err := NewWrapper(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("new brotli wrapper: %w", err)
+		return nil, fmt.Errorf("new %s wrapper: %w", algorithm, err)
 	}
 
 	return wrapper(c.next), nil
 }
 
-func encodingAccepts(acceptEncoding []string, typ string) bool {
+// chooseEncoding returns the first supported encoding accepted by the client, if any.
+// The encodings with a zero quality value are not acceptable,
+// and the * value matches the encodings not explicitly listed by the client.
+func (c *compress) chooseEncoding(acceptEncoding []string) string {
+	accepted := parseAcceptEncoding(acceptEncoding)
+
+	for _, encoding := range c.encodings {
+		if q, ok := accepted[encoding]; ok {
+			if q > 0 {
+				return encoding
+			}
+
+			continue
+		}
+
+		if q, ok := accepted["*"]; ok && q > 0 {
+			return encoding
+		}
+	}
+
+	return ""
+}
+
+// parseAcceptEncoding returns the quality values of the encodings listed in the Accept-Encoding header values.
+func parseAcceptEncoding(acceptEncoding []string) map[string]float64 {
+	accepted := make(map[string]float64)
+
 	for _, ae := range acceptEncoding {
 		for _, e := range strings.Split(ae, ",") {
-			parsed := strings.Split(strings.TrimSpace(e), ";")
-			if len(parsed) == 0 {
+			encoding, params, _ := strings.Cut(strings.TrimSpace(e), ";")
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding == "" {
 				continue
 			}
-			if parsed[0] == typ || parsed[0] == "*" {
-				return true
+
+			q := 1.0
+			for _, param := range strings.Split(params, ";") {
+				name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || strings.TrimSpace(name) != "q" {
+					continue
+				}
+
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
 			}
+
+			accepted[encoding] = q
 		}
 	}
 
-	return false
+	return accepted
 }
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/klauspost/compress/gzhttp"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -24,11 +25,13 @@ const (
 	varyHeader            = "Vary"
 	gzipValue             = "gzip"
 	brotliValue           = "br"
+	zstdValue             = "zstd"
 )
 
 func TestNegotiation(t *testing.T) {
 	testCases := []struct {
 		desc            string
+		encodings       []string
 		acceptEncHeader string
 		expEncoding     string
 	}{
@@ -71,6 +74,43 @@ func TestNegotiation(t *testing.T) {
 			acceptEncHeader: "gzip, br",
 			expEncoding:     "br",
 		},
+		{
+			desc:            "zstd accept header",
+			acceptEncHeader: "zstd",
+			expEncoding:     "zstd",
+		},
+		{
+			desc:            "multi accept header list, prefer br over zstd",
+			acceptEncHeader: "zstd, br, gzip",
+			expEncoding:     "br",
+		},
+		{
+			desc:            "zero quality value",
+			acceptEncHeader: "br;q=0, zstd;q=0, gzip",
+			expEncoding:     "gzip",
+		},
+		{
+			desc:            "any with zero quality value",
+			acceptEncHeader: "gzip, *;q=0",
+			expEncoding:     "gzip",
+		},
+		{
+			desc:            "any except br",
+			acceptEncHeader: "*, br;q=0",
+			expEncoding:     "zstd",
+		},
+		{
+			desc:            "configured preference order",
+			encodings:       []string{"zstd", "gzip"},
+			acceptEncHeader: "gzip, br, zstd",
+			expEncoding:     "zstd",
+		},
+		{
+			desc:            "configured encodings only",
+			encodings:       []string{"gzip"},
+			acceptEncHeader: "br, zstd",
+			expEncoding:     "",
+		},
 	}
 
 	for _, test := range testCases {
@@ -87,7 +127,11 @@ func TestNegotiation(t *testing.T) {
 			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 				_, _ = rw.Write(generateBytes(10))
 			})
-			handler, err := New(context.Background(), next, dynamic.Compress{MinResponseBodyBytes: 1}, "testing")
+			conf := dynamic.Compress{
+				MinResponseBodyBytes: 1,
+				Encodings:            test.encodings,
+			}
+			handler, err := New(context.Background(), next, conf, "testing")
 			require.NoError(t, err)
 
 			rw := httptest.NewRecorder()
@@ -98,6 +142,76 @@ func TestNegotiation(t *testing.T) {
 	}
 }
 
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		conf   dynamic.Compress
+		expErr string
+	}{
+		{
+			desc:   "unsupported encoding",
+			conf:   dynamic.Compress{Encodings: []string{"zstd", "deflate"}},
+			expErr: `unsupported encoding: "deflate"`,
+		},
+		{
+			desc:   "invalid gzip level",
+			conf:   dynamic.Compress{GzipLevel: 10},
+			expErr: "invalid gzip compression level: 10",
+		},
+		{
+			desc:   "invalid brotli level",
+			conf:   dynamic.Compress{BrotliLevel: 12},
+			expErr: "new br wrapper: invalid brotli compression level: 12",
+		},
+		{
+			desc:   "invalid zstd level",
+			conf:   dynamic.Compress{ZstdLevel: -1},
+			expErr: "new zstd wrapper: invalid zstd compression level: -1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "testing")
+			require.EqualError(t, err, test.expErr)
+		})
+	}
+}
+
+func TestShouldCompressWithZstd(t *testing.T) {
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Add(acceptEncodingHeader, zstdValue)
+
+	baseBody := generateBytes(DefaultMinSize)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(contentTypeHeader, "text/plain")
+
+		_, err := rw.Write(baseBody)
+		assert.NoError(t, err)
+	})
+	handler, err := New(context.Background(), next, dynamic.Compress{ZstdLevel: 19}, "testing")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, zstdValue, rw.Header().Get(contentEncodingHeader))
+	assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+	zr, err := zstd.NewReader(rw.Body)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	got, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, baseBody, got)
+}
+
 func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
@@ -1,4 +1,4 @@
-package brotli
+package compress
 
 import (
 	"bufio"
@@ -8,8 +8,10 @@ import (
 	"mime"
 	"net"
 	"net/http"
+	"sync"
 
 	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 )
 
 const (
@@ -20,8 +22,18 @@ const (
 	contentType     = "Content-Type"
 )
 
-// Config is the Brotli handler configuration.
+const (
+	brotliName = "br"
+	gzipName   = "gzip"
+	zstdName   = "zstd"
+)
+
+// Config is the compression handler configuration.
 type Config struct {
+	// Algorithm is the compression algorithm, either br or zstd.
+	Algorithm string
+	// Level is the compression level of the algorithm, zero meaning its default level.
+	Level int
 	// ExcludedContentTypes is the list of content types for which we should not compress.
 	// Mutually exclusive with the IncludedContentTypes option.
 	ExcludedContentTypes []string
@@ -32,7 +44,7 @@ type Config struct {
 	MinSize int
 }
 
-// NewWrapper returns a new Brotli compressing wrapper.
+// NewWrapper returns a new compressing wrapper, for the algorithm given in the configuration.
 func NewWrapper(cfg Config) (func(http.Handler) http.HandlerFunc, error) {
 	if cfg.MinSize < 0 {
 		return nil, errors.New("minimum size must be greater than or equal to zero")
@@ -62,21 +74,27 @@ func NewWrapper(cfg Config) (func(http.Handler) http.HandlerFunc, error) {
 		includedContentTypes = append(includedContentTypes, parsedContentType{mediaType, params})
 	}
 
+	pool, err := newWriterPool(cfg.Algorithm, cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
 	return func(h http.Handler) http.HandlerFunc {
 		return func(rw http.ResponseWriter, r *http.Request) {
 			rw.Header().Add(vary, acceptEncoding)
 
-			brw := &responseWriter{
+			crw := &responseWriter{
 				rw:                   rw,
-				bw:                   brotli.NewWriter(rw),
+				pool:                 pool,
+				encoding:             cfg.Algorithm,
 				minSize:              cfg.MinSize,
 				statusCode:           http.StatusOK,
 				excludedContentTypes: excludedContentTypes,
 				includedContentTypes: includedContentTypes,
 			}
-			defer brw.close()
+			defer crw.close()
 
-			h.ServeHTTP(brw, r)
+			h.ServeHTTP(crw, r)
 		}
 	}, nil
 }
@@ -84,8 +102,11 @@ func NewWrapper(cfg Config) (func(http.Handler) http.HandlerFunc, error) {
 // TODO: check whether we want to implement content-type sniffing (as gzip does)
 // TODO: check whether we should support Accept-Ranges (as gzip does, see https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Accept-Ranges)
 type responseWriter struct {
-	rw http.ResponseWriter
-	bw *brotli.Writer
+	rw   http.ResponseWriter
+	pool *writerPool
+	// cw is the compressed writer, taken from the pool when the compression starts.
+	cw       compressionWriter
+	encoding string
 
 	minSize              int
 	excludedContentTypes []parsedContentType
@@ -97,8 +118,8 @@ type responseWriter struct {
 	compressionDisabled bool
 	headersSent         bool
 
-	// Mostly needed to avoid calling bw.Flush/bw.Close when no data was
-	// written in bw.
+	// Mostly needed to avoid calling cw.Flush/cw.Close when no data was
+	// written in cw.
 	seenData bool
 
 	statusCodeSet bool
@@ -133,7 +154,7 @@ func (r *responseWriter) Write(p []byte) (int, error) {
 	// We are now in compression cruise mode until the end of times.
 	if r.compressionStarted {
 		// If compressionStarted we assume we have sent headers already
-		return r.bw.Write(p)
+		return r.cw.Write(p)
 	}
 
 	// If we detect a contentEncoding, we know we are never going to compress.
@@ -185,13 +206,15 @@ func (r *responseWriter) Write(p []byte) (int, error) {
 	// Since we know we are going to compress we will never be able to know the actual length.
 	r.rw.Header().Del(contentLength)
 
-	r.rw.Header().Set(contentEncoding, "br")
+	r.rw.Header().Set(contentEncoding, r.encoding)
 	r.rw.WriteHeader(r.statusCode)
 	r.headersSent = true
 
+	r.cw = r.pool.get(r.rw)
+
 	// Start with sending what we have previously buffered, before actually writing
 	// the bytes in argument.
-	n, err := r.bw.Write(r.buf)
+	n, err := r.cw.Write(r.buf)
 	if err != nil {
 		r.buf = r.buf[n:]
 		// Return zero because we haven't taken care of the bytes in argument yet.
@@ -210,7 +233,7 @@ func (r *responseWriter) Write(p []byte) (int, error) {
 	r.buf = r.buf[:0]
 
 	// Now that we emptied the buffer, we can actually write the given bytes.
-	return r.bw.Write(p)
+	return r.cw.Write(p)
 }
 
 // Flush flushes data to the appropriate underlying writer(s), although it does
@@ -219,7 +242,7 @@ func (r *responseWriter) Write(p []byte) (int, error) {
 // no flushing will take place.
 func (r *responseWriter) Flush() {
 	if !r.seenData {
-		// we should not flush if there never was any data, because flushing the bw
+		// we should not flush if there never was any data, because flushing the cw
 		// (just like closing) would send some extra end of compressionStarted stream bytes.
 		return
 	}
@@ -234,7 +257,7 @@ func (r *responseWriter) Flush() {
 		return
 	}
 
-	// Here, nothing was ever written either to rw or to bw (since we're still
+	// Here, nothing was ever written either to rw or to cw (since we're still
 	// waiting to decide whether to compress), so we do not need to flush anything.
 	// Note that we diverge with klauspost's gzip behavior, where they instead
 	// force compression and flush whatever was in the buffer in this case.
@@ -242,13 +265,13 @@ func (r *responseWriter) Flush() {
 		return
 	}
 
-	// Conversely, we here know that something was already written to bw (or is
-	// going to be written right after anyway), so bw will have to be flushed.
-	// Also, since we know that bw writes to rw, but (apparently) never flushes it,
+	// Conversely, we here know that something was already written to cw (or is
+	// going to be written right after anyway), so cw will have to be flushed.
+	// Also, since we know that cw writes to rw, but (apparently) never flushes it,
 	// we have to do it ourselves.
 	defer func() {
 		// because we also ignore the error returned by Write anyway
-		_ = r.bw.Flush()
+		_ = r.cw.Flush()
 
 		if rw, ok := r.rw.(http.Flusher); ok {
 			rw.Flush()
@@ -256,7 +279,7 @@ func (r *responseWriter) Flush() {
 	}()
 
 	// We empty whatever is left of the buffer that Write never took care of.
-	n, err := r.bw.Write(r.buf)
+	n, err := r.cw.Write(r.buf)
 	if err != nil {
 		return
 	}
@@ -304,14 +327,14 @@ func (r *responseWriter) close() error {
 	}
 
 	// If compression was disabled, there never was anything in the buffer to flush,
-	// and nothing was ever written to bw.
+	// and nothing was ever written to cw.
 	if r.compressionDisabled {
 		return nil
 	}
 
 	if len(r.buf) == 0 {
-		// If we got here we know compression has started, so we can safely flush on bw.
-		return r.bw.Close()
+		// If we got here we know compression has started, so we can safely flush on cw.
+		return r.closeWriter()
 	}
 
 	// There is still data in the buffer, because we never reached minSize (to
@@ -329,16 +352,94 @@ func (r *responseWriter) close() error {
 
 	// There is still data in the buffer, simply because Write did not take care of it all.
 	// We flush it to the compressed writer.
-	n, err := r.bw.Write(r.buf)
+	n, err := r.cw.Write(r.buf)
 	if err != nil {
-		r.bw.Close()
+		_ = r.closeWriter()
 		return err
 	}
 	if n < len(r.buf) {
-		r.bw.Close()
+		_ = r.closeWriter()
 		return io.ErrShortWrite
 	}
-	return r.bw.Close()
+	return r.closeWriter()
+}
+
+// closeWriter closes the compressed writer, and puts it back in the pool.
+func (r *responseWriter) closeWriter() error {
+	err := r.cw.Close()
+	r.pool.put(r.cw)
+	r.cw = nil
+
+	return err
+}
+
+// compressionWriter is implemented by the Brotli and Zstandard writers.
+type compressionWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// writerPool reuses the compression writers across responses,
+// as their allocation is costly, especially for Zstandard.
+type writerPool struct {
+	pool sync.Pool
+}
+
+func newWriterPool(algorithm string, level int) (*writerPool, error) {
+	p := &writerPool{}
+
+	switch algorithm {
+	case brotliName:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+
+		if level < brotli.BestSpeed || level > brotli.BestCompression {
+			return nil, fmt.Errorf("invalid brotli compression level: %d", level)
+		}
+
+		p.pool.New = func() any {
+			return brotli.NewWriterLevel(nil, level)
+		}
+
+	case zstdName:
+		encoderLevel := zstd.SpeedDefault
+		if level != 0 {
+			if level < 1 || level > 22 {
+				return nil, fmt.Errorf("invalid zstd compression level: %d", level)
+			}
+
+			encoderLevel = zstd.EncoderLevelFromZstd(level)
+		}
+
+		// The options are checked once, so that the pool never fails to create a writer.
+		options := []zstd.EOption{zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderConcurrency(1)}
+		if _, err := zstd.NewWriter(nil, options...); err != nil {
+			return nil, fmt.Errorf("creating zstd writer: %w", err)
+		}
+
+		p.pool.New = func() any {
+			w, _ := zstd.NewWriter(nil, options...)
+			return w
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm: %q", algorithm)
+	}
+
+	return p, nil
+}
+
+func (p *writerPool) get(w io.Writer) compressionWriter {
+	cw := p.pool.Get().(compressionWriter)
+	cw.Reset(w)
+
+	return cw
+}
+
+func (p *writerPool) put(cw compressionWriter) {
+	p.pool.Put(cw)
 }
 
 // parsedContentType is the parsed representation of one of the inputs to ContentTypes.
@@ -1,4 +1,4 @@
-package brotli
+package compress
 
 import (
 	"bytes"
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -92,7 +93,7 @@ func Test_NoBody(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			h := mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			h := mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 				rw.WriteHeader(test.statusCode)
 
 				_, err := rw.Write(test.body)
@@ -116,7 +117,8 @@ func Test_NoBody(t *testing.T) {
 
 func Test_MinSize(t *testing.T) {
 	cfg := Config{
-		MinSize: 128,
+		Algorithm: brotliName,
+		MinSize:   128,
 	}
 
 	var bodySize int
@@ -151,7 +153,7 @@ func Test_MinSize(t *testing.T) {
 }
 
 func Test_MultipleWriteHeader(t *testing.T) {
-	h := mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	h := mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		// We ensure that the subsequent call to WriteHeader is a noop.
 		rw.WriteHeader(http.StatusInternalServerError)
 		rw.WriteHeader(http.StatusNotFound)
@@ -167,7 +169,7 @@ func Test_MultipleWriteHeader(t *testing.T) {
 }
 
 func Test_FlushBeforeWrite(t *testing.T) {
-	srv := httptest.NewServer(mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	srv := httptest.NewServer(mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
 		rw.(http.Flusher).Flush()
 
@@ -195,7 +197,7 @@ func Test_FlushBeforeWrite(t *testing.T) {
 }
 
 func Test_FlushAfterWrite(t *testing.T) {
-	srv := httptest.NewServer(mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	srv := httptest.NewServer(mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
 
 		_, err := rw.Write(bigTestBody[0:1])
@@ -228,7 +230,7 @@ func Test_FlushAfterWrite(t *testing.T) {
 }
 
 func Test_FlushAfterWriteNil(t *testing.T) {
-	srv := httptest.NewServer(mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	srv := httptest.NewServer(mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
 
 		_, err := rw.Write(nil)
@@ -257,7 +259,7 @@ func Test_FlushAfterWriteNil(t *testing.T) {
 }
 
 func Test_FlushAfterAllWrites(t *testing.T) {
-	srv := httptest.NewServer(mustNewWrapper(t, Config{MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	srv := httptest.NewServer(mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		for i := range bigTestBody {
 			_, err := rw.Write(bigTestBody[i : i+1])
 			require.NoError(t, err)
@@ -352,6 +354,7 @@ func Test_ExcludedContentTypes(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{
+				Algorithm:            brotliName,
 				MinSize:              1024,
 				ExcludedContentTypes: test.excludedContentTypes,
 			}
@@ -457,6 +460,7 @@ func Test_IncludedContentTypes(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{
+				Algorithm:            brotliName,
 				MinSize:              1024,
 				IncludedContentTypes: test.includedContentTypes,
 			}
@@ -562,6 +566,7 @@ func Test_FlushExcludedContentTypes(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{
+				Algorithm:            brotliName,
 				MinSize:              1024,
 				ExcludedContentTypes: test.excludedContentTypes,
 			}
@@ -681,6 +686,7 @@ func Test_FlushIncludedContentTypes(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{
+				Algorithm:            brotliName,
 				MinSize:              1024,
 				IncludedContentTypes: test.includedContentTypes,
 			}
@@ -732,6 +738,95 @@ func Test_FlushIncludedContentTypes(t *testing.T) {
 	}
 }
 
+func Test_Algorithms(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		algorithm string
+		level     int
+		decode    func(t *testing.T, r io.Reader) []byte
+	}{
+		{
+			desc:      "brotli with default level",
+			algorithm: brotliName,
+			decode:    decodeBrotli,
+		},
+		{
+			desc:      "brotli with best compression",
+			algorithm: brotliName,
+			level:     brotli.BestCompression,
+			decode:    decodeBrotli,
+		},
+		{
+			desc:      "zstd with default level",
+			algorithm: zstdName,
+			decode:    decodeZstd,
+		},
+		{
+			desc:      "zstd with best speed",
+			algorithm: zstdName,
+			level:     1,
+			decode:    decodeZstd,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{
+				Algorithm: test.algorithm,
+				Level:     test.level,
+				MinSize:   1024,
+			}
+			h := mustNewWrapper(t, cfg)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, err := rw.Write(bigTestBody)
+				require.NoError(t, err)
+			}))
+
+			// The compression writers are reused across responses.
+			for i := 0; i < 3; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set(acceptEncoding, test.algorithm)
+
+				rw := httptest.NewRecorder()
+				h.ServeHTTP(rw, req)
+
+				assert.Equal(t, test.algorithm, rw.Header().Get(contentEncoding))
+				assert.Equal(t, bigTestBody, test.decode(t, rw.Body))
+			}
+		})
+	}
+}
+
+func Test_UnsupportedAlgorithm(t *testing.T) {
+	_, err := NewWrapper(Config{Algorithm: "deflate"})
+	require.EqualError(t, err, `unsupported compression algorithm: "deflate"`)
+}
+
+func decodeBrotli(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+
+	got, err := io.ReadAll(brotli.NewReader(r))
+	require.NoError(t, err)
+
+	return got
+}
+
+func decodeZstd(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+
+	zr, err := zstd.NewReader(r)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	got, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	return got
+}
+
 func mustNewWrapper(t *testing.T, cfg Config) func(http.Handler) http.HandlerFunc {
 	t.Helper()
 
@@ -744,7 +839,7 @@ func mustNewWrapper(t *testing.T, cfg Config) func(http.Handler) http.HandlerFun
 func newTestHandler(t *testing.T, body []byte) http.Handler {
 	t.Helper()
 
-	return mustNewWrapper(t, Config{MinSize: 1024})(
+	return mustNewWrapper(t, Config{Algorithm: brotliName, MinSize: 1024})(
 		http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			if req.URL.Path == "/compressed" {
 				rw.Header().Set("Content-Encoding", "br")
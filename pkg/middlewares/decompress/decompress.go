@@ -0,0 +1,176 @@
+package decompress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "Decompress"
+
+// defaultMaxDecompressedBodyBytes is the default maximum size of a decompressed request body.
+const defaultMaxDecompressedBodyBytes = 10 * 1024 * 1024
+
+// acceptedEncodings is the value of the Accept-Encoding header sent with the 415 responses.
+// See https://www.rfc-editor.org/rfc/rfc9110#section-12.5.3.
+const acceptedEncodings = "gzip, br, zstd"
+
+// decompress is a middleware that decompresses the request bodies.
+type decompress struct {
+	next    http.Handler
+	name    string
+	maxSize int64
+}
+
+// New creates a new decompress middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Decompress, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.MaxDecompressedBodyBytes < 0 {
+		return nil, errors.New("maxDecompressedBodyBytes must be positive")
+	}
+
+	maxSize := config.MaxDecompressedBodyBytes
+	if maxSize == 0 {
+		maxSize = defaultMaxDecompressedBodyBytes
+	}
+
+	return &decompress{
+		next:    next,
+		name:    name,
+		maxSize: maxSize,
+	}, nil
+}
+
+func (d *decompress) GetTracingInformation() (string, string, trace.SpanKind) {
+	return d.name, typeName, trace.SpanKindInternal
+}
+
+func (d *decompress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), d.name, typeName)
+
+	encodings := parseContentEncoding(req.Header.Values("Content-Encoding"))
+	if len(encodings) == 0 {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	for _, encoding := range encodings {
+		if !supported(encoding) {
+			logger.Debug().Msgf("Unsupported content encoding: %q", encoding)
+
+			rw.Header().Set("Accept-Encoding", acceptedEncodings)
+			http.Error(rw, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+
+	req.Header.Del("Content-Encoding")
+
+	if req.Body == nil || req.Body == http.NoBody {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	body, err := newDecodedBody(req.Body, encodings)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Unable to decode request body")
+
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	// The decompressed length is only known once the whole body is read.
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	req.Body = http.MaxBytesReader(rw, body, d.maxSize)
+
+	d.next.ServeHTTP(rw, req)
+}
+
+// parseContentEncoding returns the content codings applied to the request body, in the order they were applied.
+// The identity coding is ignored.
+func parseContentEncoding(values []string) []string {
+	var encodings []string
+	for _, value := range values {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding == "" || encoding == "identity" {
+				continue
+			}
+
+			encodings = append(encodings, encoding)
+		}
+	}
+
+	return encodings
+}
+
+func supported(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "br", "zstd":
+		return true
+	default:
+		return false
+	}
+}
+
+// decodedBody reads the decoded request body, and closes the decoders along with the original body.
+type decodedBody struct {
+	io.Reader
+
+	body    io.ReadCloser
+	closers []func()
+}
+
+// newDecodedBody returns the body decoded from the given encodings, which are undone in the reverse order.
+func newDecodedBody(body io.ReadCloser, encodings []string) (*decodedBody, error) {
+	db := &decodedBody{Reader: body, body: body}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		switch encodings[i] {
+		case "gzip", "x-gzip":
+			gr, err := gzip.NewReader(db.Reader)
+			if err != nil {
+				_ = db.Close()
+				return nil, fmt.Errorf("creating gzip reader: %w", err)
+			}
+
+			db.Reader = gr
+			db.closers = append(db.closers, func() { _ = gr.Close() })
+
+		case "br":
+			db.Reader = brotli.NewReader(db.Reader)
+
+		case "zstd":
+			zr, err := zstd.NewReader(db.Reader, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+			if err != nil {
+				_ = db.Close()
+				return nil, fmt.Errorf("creating zstd reader: %w", err)
+			}
+
+			db.Reader = zr
+			db.closers = append(db.closers, zr.Close)
+		}
+	}
+
+	return db, nil
+}
+
+func (b *decodedBody) Close() error {
+	for _, closeFn := range b.closers {
+		closeFn()
+	}
+
+	return b.body.Close()
+}
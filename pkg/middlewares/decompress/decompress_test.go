@@ -0,0 +1,258 @@
+package decompress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/buffering"
+)
+
+func TestDecompress(t *testing.T) {
+	content := []byte(strings.Repeat("traefik", 100))
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.Decompress
+		contentEncoding string
+		body            []byte
+		expEncoding     string
+		expStatusCode   int
+		expBody         []byte
+		expAccept       string
+	}{
+		{
+			desc:          "no content encoding",
+			body:          content,
+			expStatusCode: http.StatusOK,
+			expBody:       content,
+		},
+		{
+			desc:            "identity",
+			contentEncoding: "identity",
+			body:            content,
+			expEncoding:     "identity",
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "gzip",
+			contentEncoding: "gzip",
+			body:            gzipEncode(t, content),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "x-gzip",
+			contentEncoding: "x-gzip",
+			body:            gzipEncode(t, content),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "brotli",
+			contentEncoding: "br",
+			body:            brotliEncode(t, content),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "zstd",
+			contentEncoding: "ZSTD",
+			body:            zstdEncode(t, content),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "several encodings",
+			contentEncoding: "gzip, zstd",
+			body:            zstdEncode(t, gzipEncode(t, content)),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+		{
+			desc:            "unsupported encoding",
+			contentEncoding: "deflate",
+			body:            content,
+			expStatusCode:   http.StatusUnsupportedMediaType,
+			expAccept:       "gzip, br, zstd",
+		},
+		{
+			desc:            "unsupported encoding among supported ones",
+			contentEncoding: "gzip, compress",
+			body:            content,
+			expStatusCode:   http.StatusUnsupportedMediaType,
+			expAccept:       "gzip, br, zstd",
+		},
+		{
+			desc:            "invalid gzip body",
+			contentEncoding: "gzip",
+			body:            content,
+			expStatusCode:   http.StatusBadRequest,
+		},
+		{
+			desc:            "decompressed body too large",
+			config:          dynamic.Decompress{MaxDecompressedBodyBytes: 100},
+			contentEncoding: "gzip",
+			body:            gzipEncode(t, content),
+			expStatusCode:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:            "decompressed body at the limit",
+			config:          dynamic.Decompress{MaxDecompressedBodyBytes: int64(len(content))},
+			contentEncoding: "zstd",
+			body:            zstdEncode(t, content),
+			expStatusCode:   http.StatusOK,
+			expBody:         content,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, test.expEncoding, req.Header.Get("Content-Encoding"))
+
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					var maxBytesErr *http.MaxBytesError
+					if errors.As(err, &maxBytesErr) {
+						rw.WriteHeader(http.StatusRequestEntityTooLarge)
+						return
+					}
+
+					rw.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				_, _ = rw.Write(body)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "decompress")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", bytes.NewReader(test.body))
+			if test.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expStatusCode, rw.Code)
+			assert.Equal(t, test.expAccept, rw.Header().Get("Accept-Encoding"))
+			if test.expBody != nil {
+				assert.Equal(t, test.expBody, rw.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestDecompress_buffering(t *testing.T) {
+	content := []byte(strings.Repeat("traefik", 100))
+
+	testCases := []struct {
+		desc          string
+		decompress    dynamic.Decompress
+		buffering     dynamic.Buffering
+		expStatusCode int
+	}{
+		{
+			desc:          "within limits",
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc:          "decompressed body larger than maxRequestBodyBytes",
+			buffering:     dynamic.Buffering{MaxRequestBodyBytes: 100},
+			expStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "decompressed body larger than maxDecompressedBodyBytes",
+			decompress:    dynamic.Decompress{MaxDecompressedBodyBytes: 100},
+			expStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, int64(len(content)), req.ContentLength)
+				assert.Equal(t, content, body)
+
+				rw.WriteHeader(http.StatusOK)
+				_, err = rw.Write(body)
+				require.NoError(t, err)
+			})
+
+			bufferingHandler, err := buffering.New(context.Background(), next, test.buffering, "buffering")
+			require.NoError(t, err)
+
+			handler, err := New(context.Background(), bufferingHandler, test.decompress, "decompress")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", bytes.NewReader(gzipEncode(t, content)))
+			req.Header.Set("Content-Encoding", "gzip")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expStatusCode, rw.Code)
+		})
+	}
+}
+
+func gzipEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func brotliEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := brotli.NewWriter(&buf)
+
+	_, err := w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func zstdEncode(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	w, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	return w.EncodeAll(content, nil)
+}
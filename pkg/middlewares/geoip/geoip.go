@@ -0,0 +1,184 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefikgeoip "github.com/traefik/traefik/v3/pkg/geoip"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "GeoIP"
+)
+
+const (
+	defaultCountryHeader = "X-Client-Country"
+	defaultASNHeader     = "X-Client-ASN"
+)
+
+// geoIP is a middleware that allows or denies requests based on the country of the client IP,
+// and forwards the country and the ASN of the client IP in request headers.
+type geoIP struct {
+	next             http.Handler
+	db               *traefikgeoip.Database
+	strategy         ip.Strategy
+	name             string
+	allowed          map[string]struct{}
+	denied           map[string]struct{}
+	countryHeader    string
+	asnHeader        string
+	rejectStatusCode int
+}
+
+// New builds a new GeoIP middleware, looking up the client IPs in the given database.
+func New(ctx context.Context, next http.Handler, config dynamic.GeoIP, db *traefikgeoip.Database, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if db == nil {
+		return nil, errors.New("no GeoIP database defined in the static configuration")
+	}
+
+	rejectStatusCode := config.RejectStatusCode
+	// If RejectStatusCode is not given, default to Forbidden (403).
+	if rejectStatusCode == 0 {
+		rejectStatusCode = http.StatusForbidden
+	} else if http.StatusText(rejectStatusCode) == "" {
+		return nil, fmt.Errorf("invalid HTTP status code %d", rejectStatusCode)
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := parseCountries(config.AllowedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("parsing allowed countries: %w", err)
+	}
+
+	denied, err := parseCountries(config.DeniedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("parsing denied countries: %w", err)
+	}
+
+	countryHeader := config.CountryHeader
+	if countryHeader == "" {
+		countryHeader = defaultCountryHeader
+	}
+
+	asnHeader := config.ASNHeader
+	if asnHeader == "" {
+		asnHeader = defaultASNHeader
+	}
+
+	logger.Debug().Msgf("Setting up GeoIP with allowedCountries: %s, deniedCountries: %s",
+		config.AllowedCountries, config.DeniedCountries)
+
+	return &geoIP{
+		next:             next,
+		db:               db,
+		strategy:         strategy,
+		name:             name,
+		allowed:          allowed,
+		denied:           denied,
+		countryHeader:    http.CanonicalHeaderKey(countryHeader),
+		asnHeader:        http.CanonicalHeaderKey(asnHeader),
+		rejectStatusCode: rejectStatusCode,
+	}, nil
+}
+
+func (g *geoIP) GetTracingInformation() (string, string, trace.SpanKind) {
+	return g.name, typeName, trace.SpanKindInternal
+}
+
+func (g *geoIP) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), g.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	// The headers sent by the client are never forwarded, as they would be trusted by the backends.
+	req.Header.Del(g.countryHeader)
+	req.Header.Del(g.asnHeader)
+
+	clientIP := g.strategy.GetIP(req)
+	record, err := g.db.LookupString(clientIP)
+	if err != nil {
+		if g.filters() {
+			msg := fmt.Sprintf("Rejecting IP %s: %v", clientIP, err)
+			logger.Debug().Msg(msg)
+			tracing.SetStatusErrorf(req.Context(), msg)
+			reject(ctx, g.rejectStatusCode, rw)
+			return
+		}
+
+		logger.Debug().Err(err).Msgf("Unable to look up IP %s", clientIP)
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if !g.allows(record.Country) {
+		msg := fmt.Sprintf("Rejecting IP %s: country %q not allowed", clientIP, record.Country)
+		logger.Debug().Msg(msg)
+		tracing.SetStatusErrorf(req.Context(), msg)
+		reject(ctx, g.rejectStatusCode, rw)
+		return
+	}
+
+	if record.Country != "" {
+		req.Header.Set(g.countryHeader, record.Country)
+	}
+	if asn := record.ASNString(); asn != "" {
+		req.Header.Set(g.asnHeader, asn)
+	}
+
+	g.next.ServeHTTP(rw, req)
+}
+
+// filters returns whether the middleware allows or denies requests based on their country.
+func (g *geoIP) filters() bool {
+	return len(g.allowed) > 0 || len(g.denied) > 0
+}
+
+// allows returns whether the requests from the given country, which is empty if unknown, are allowed.
+func (g *geoIP) allows(country string) bool {
+	if _, ok := g.denied[country]; ok {
+		return false
+	}
+
+	if len(g.allowed) == 0 {
+		return true
+	}
+
+	_, ok := g.allowed[country]
+	return ok
+}
+
+func parseCountries(countries []string) (map[string]struct{}, error) {
+	parsed := make(map[string]struct{}, len(countries))
+	for _, country := range countries {
+		code, err := traefikgeoip.ParseCountry(country)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[code] = struct{}{}
+	}
+
+	return parsed, nil
+}
+
+func reject(ctx context.Context, statusCode int, rw http.ResponseWriter) {
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Send()
+	}
+}
@@ -0,0 +1,253 @@
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefikgeoip "github.com/traefik/traefik/v3/pkg/geoip"
+)
+
+func TestNew(t *testing.T) {
+	db, err := traefikgeoip.NewDatabase([]string{"../../geoip/fixtures/test.mmdb"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		config        dynamic.GeoIP
+		noDatabase    bool
+		expectedError bool
+	}{
+		{
+			desc: "empty config",
+		},
+		{
+			desc:          "no database",
+			noDatabase:    true,
+			expectedError: true,
+		},
+		{
+			desc: "invalid allowed country",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"France"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid denied country",
+			config: dynamic.GeoIP{
+				DeniedCountries: []string{"F"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid HTTP status code",
+			config: dynamic.GeoIP{
+				RejectStatusCode: 600,
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid config",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"fr", "DE"},
+				DeniedCountries:  []string{"GB"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			database := db
+			if test.noDatabase {
+				database = nil
+			}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, database, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestGeoIP_ServeHTTP(t *testing.T) {
+	db, err := traefikgeoip.NewDatabase([]string{"../../geoip/fixtures/test.mmdb"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.GeoIP
+		remoteAddr      string
+		xForwardedFor   string
+		reqHeaders      map[string]string
+		expected        int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:       "headers only",
+			remoteAddr: "1.2.3.4:1234",
+			expected:   http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Client-Country": "FR",
+				"X-Client-Asn":     "64500",
+			},
+		},
+		{
+			desc: "custom headers",
+			config: dynamic.GeoIP{
+				CountryHeader: "X-Country",
+				ASNHeader:     "X-ASN",
+			},
+			remoteAddr: "[2001:db8::1]:1234",
+			expected:   http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Country": "DE",
+				"X-Asn":     "64502",
+			},
+		},
+		{
+			desc:       "spoofed headers are removed",
+			remoteAddr: "8.8.8.8:1234",
+			reqHeaders: map[string]string{
+				"X-Client-Country": "FR",
+				"X-Client-ASN":     "64500",
+			},
+			expected: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Client-Country": "",
+				"X-Client-Asn":     "",
+			},
+		},
+		{
+			desc:       "country only",
+			remoteAddr: "10.1.1.1:1234",
+			expected:   http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Client-Country": "US",
+				"X-Client-Asn":     "",
+			},
+		},
+		{
+			desc: "allowed country",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"FR", "DE"},
+			},
+			remoteAddr: "1.2.3.4:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc: "not allowed country",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"FR", "DE"},
+			},
+			remoteAddr: "81.2.69.1:1234",
+			expected:   http.StatusForbidden,
+		},
+		{
+			desc: "unknown country with allowed countries",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"FR"},
+			},
+			remoteAddr: "8.8.8.8:1234",
+			expected:   http.StatusForbidden,
+		},
+		{
+			desc: "denied country",
+			config: dynamic.GeoIP{
+				DeniedCountries: []string{"gb"},
+			},
+			remoteAddr: "81.2.69.1:1234",
+			expected:   http.StatusForbidden,
+		},
+		{
+			desc: "denied country, reject 451",
+			config: dynamic.GeoIP{
+				DeniedCountries:  []string{"GB"},
+				RejectStatusCode: http.StatusUnavailableForLegalReasons,
+			},
+			remoteAddr: "81.2.69.1:1234",
+			expected:   http.StatusUnavailableForLegalReasons,
+		},
+		{
+			desc: "unknown country with denied countries",
+			config: dynamic.GeoIP{
+				DeniedCountries: []string{"GB"},
+			},
+			remoteAddr: "8.8.8.8:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc: "invalid remote address with denied countries",
+			config: dynamic.GeoIP{
+				DeniedCountries: []string{"GB"},
+			},
+			remoteAddr: "foo",
+			expected:   http.StatusForbidden,
+		},
+		{
+			desc:       "invalid remote address without filtering",
+			remoteAddr: "foo",
+			expected:   http.StatusOK,
+		},
+		{
+			desc: "denied with X-Forwarded-For",
+			config: dynamic.GeoIP{
+				DeniedCountries: []string{"GB"},
+				IPStrategy: &dynamic.IPStrategy{
+					Depth: 1,
+				},
+			},
+			remoteAddr:    "1.2.3.4:1234",
+			xForwardedFor: "81.2.69.1",
+			expected:      http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				forwarded = r.Header.Clone()
+			})
+
+			handler, err := New(context.Background(), next, test.config, db, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, "http://10.10.10.10", nil)
+			req.RemoteAddr = test.remoteAddr
+
+			if test.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+			}
+			for name, value := range test.reqHeaders {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Code)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name), name)
+			}
+		})
+	}
+}
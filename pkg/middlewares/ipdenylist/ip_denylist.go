@@ -0,0 +1,105 @@
+package ipdenylist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	typeName = "IPDenyLister"
+)
+
+// registry holds the denied ranges of the middlewares across the configuration reloads.
+var registry = NewSourceRangesRegistry()
+
+// ipDenyLister is a middleware that provides Checks of the Requesting IP against a set of Denylists.
+type ipDenyLister struct {
+	next             http.Handler
+	denyLister       *SourceRanges
+	strategy         ip.Strategy
+	name             string
+	rejectStatusCode int
+}
+
+// New builds a new IPDenyLister given a list of CIDR-Strings, files, and URLs to deny.
+func New(ctx context.Context, next http.Handler, config dynamic.IPDenyList, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	rejectStatusCode := config.RejectStatusCode
+	// If RejectStatusCode is not given, default to Forbidden (403).
+	if rejectStatusCode == 0 {
+		rejectStatusCode = http.StatusForbidden
+	} else if http.StatusText(rejectStatusCode) == "" {
+		return nil, fmt.Errorf("invalid HTTP status code %d", rejectStatusCode)
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	denyLister, err := registry.Get(logger.WithContext(ctx), name, config.SourceRange, config.SourceRangeFiles, config.SourceRangeURLs, time.Duration(config.RefreshInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().Msgf("Setting up IPDenyLister with sourceRange: %s, sourceRangeFiles: %s, sourceRangeURLs: %s",
+		config.SourceRange, config.SourceRangeFiles, config.SourceRangeURLs)
+
+	return &ipDenyLister{
+		strategy:         strategy,
+		denyLister:       denyLister,
+		next:             next,
+		name:             name,
+		rejectStatusCode: rejectStatusCode,
+	}, nil
+}
+
+// Retain drops the denied ranges of the IPDenyList middlewares which are not in the given list,
+// i.e. which have been removed from the configuration.
+func Retain(names map[string]struct{}) {
+	registry.Retain(names)
+}
+
+func (dl *ipDenyLister) GetTracingInformation() (string, string, trace.SpanKind) {
+	return dl.name, typeName, trace.SpanKindInternal
+}
+
+func (dl *ipDenyLister) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), dl.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	clientIP := dl.strategy.GetIP(req)
+	denied, err := dl.denyLister.Contains(ctx, clientIP)
+	if err != nil || denied {
+		msg := fmt.Sprintf("Rejecting IP %s: denied", clientIP)
+		if err != nil {
+			msg = fmt.Sprintf("Rejecting IP %s: %v", clientIP, err)
+		}
+		logger.Debug().Msg(msg)
+		tracing.SetStatusErrorf(req.Context(), msg)
+		reject(ctx, dl.rejectStatusCode, rw)
+		return
+	}
+	logger.Debug().Msgf("Accepting IP %s", clientIP)
+
+	dl.next.ServeHTTP(rw, req)
+}
+
+func reject(ctx context.Context, statusCode int, rw http.ResponseWriter) {
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Send()
+	}
+}
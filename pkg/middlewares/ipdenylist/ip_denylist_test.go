@@ -0,0 +1,165 @@
+package ipdenylist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewIPDenyLister(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		denyList      dynamic.IPDenyList
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			denyList:      dynamic.IPDenyList{},
+			expectedError: true,
+		},
+		{
+			desc: "invalid IP",
+			denyList: dynamic.IPDenyList{
+				SourceRange: []string{"foo"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid IP",
+			denyList: dynamic.IPDenyList{
+				SourceRange: []string{"10.10.10.10"},
+			},
+		},
+		{
+			desc: "missing file",
+			denyList: dynamic.IPDenyList{
+				SourceRangeFiles: []string{"does-not-exist.txt"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid HTTP status code",
+			denyList: dynamic.IPDenyList{
+				SourceRange:      []string{"10.10.10.10"},
+				RejectStatusCode: 600,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			denyLister, err := New(context.Background(), next, test.denyList, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, denyLister)
+			}
+		})
+	}
+}
+
+func TestIPDenyLister_ServeHTTP(t *testing.T) {
+	listFile := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(listFile, []byte("# Denied ranges\n30.30.30.0/24\n"), 0o644)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		denyList      dynamic.IPDenyList
+		remoteAddr    string
+		xForwardedFor string
+		expected      int
+	}{
+		{
+			desc: "authorized with remote address",
+			denyList: dynamic.IPDenyList{
+				SourceRange: []string{"20.20.20.20"},
+			},
+			remoteAddr: "20.20.20.21:1234",
+			expected:   200,
+		},
+		{
+			desc: "denied with remote address",
+			denyList: dynamic.IPDenyList{
+				SourceRange: []string{"20.20.20.20"},
+			},
+			remoteAddr: "20.20.20.20:1234",
+			expected:   403,
+		},
+		{
+			desc: "denied with remote address, reject 404",
+			denyList: dynamic.IPDenyList{
+				SourceRange:      []string{"20.20.20.20"},
+				RejectStatusCode: 404,
+			},
+			remoteAddr: "20.20.20.20:1234",
+			expected:   404,
+		},
+		{
+			desc: "denied by file",
+			denyList: dynamic.IPDenyList{
+				SourceRangeFiles: []string{listFile},
+			},
+			remoteAddr: "30.30.30.30:1234",
+			expected:   403,
+		},
+		{
+			desc: "authorized by file",
+			denyList: dynamic.IPDenyList{
+				SourceRangeFiles: []string{listFile},
+			},
+			remoteAddr: "30.30.31.30:1234",
+			expected:   200,
+		},
+		{
+			desc: "denied with X-Forwarded-For",
+			denyList: dynamic.IPDenyList{
+				SourceRange: []string{"30.30.30.30"},
+				IPStrategy: &dynamic.IPStrategy{
+					Depth: 1,
+				},
+			},
+			remoteAddr:    "20.20.20.20:1234",
+			xForwardedFor: "30.30.30.30",
+			expected:      403,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			denyLister, err := New(context.Background(), next, test.denyList, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, "http://10.10.10.10", nil)
+			req.RemoteAddr = test.remoteAddr
+
+			if test.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+			}
+
+			denyLister.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package ipdenylist
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+const defaultRefreshInterval = time.Hour
+
+// SourceRanges holds the denied IP ranges, defined inline, and loaded from files and URLs.
+// The ranges are reloaded from the files and URLs once the refresh interval has elapsed, when they are looked up,
+// and swapped only once they have all been loaded successfully.
+type SourceRanges struct {
+	inline          []string
+	files           []string
+	urls            []string
+	refreshInterval time.Duration
+	client          *http.Client
+	timeNow         func() time.Time
+
+	trie       atomic.Pointer[ip.Trie]
+	refreshing atomic.Bool
+
+	mu       sync.Mutex
+	loadedAt time.Time
+}
+
+// NewSourceRanges creates the denied ranges, and loads the inline ranges and the files.
+// The URLs are fetched in the background, meanwhile only the inline ranges and the files are denied.
+func NewSourceRanges(ctx context.Context, inline, files, urls []string, refreshInterval time.Duration) (*SourceRanges, error) {
+	if len(inline) == 0 && len(files) == 0 && len(urls) == 0 {
+		return nil, errors.New("one of sourceRange, sourceRangeFiles or sourceRangeURLs must be set")
+	}
+
+	if refreshInterval < 0 {
+		return nil, fmt.Errorf("negative value not valid for refreshInterval: %v", refreshInterval)
+	}
+	if refreshInterval == 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	s := &SourceRanges{
+		inline:          inline,
+		files:           files,
+		urls:            urls,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 30 * time.Second},
+		timeNow:         time.Now,
+	}
+
+	trie, err := s.build(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.trie.Store(trie)
+	s.loadedAt = s.timeNow()
+
+	if len(urls) > 0 {
+		s.refreshing.Store(true)
+
+		logger := log.Ctx(ctx).With().Logger()
+		safe.Go(func() {
+			defer s.refreshing.Store(false)
+
+			if err := s.refresh(context.Background()); err != nil {
+				logger.Error().Err(err).Msg("Unable to load the denied IP ranges")
+			}
+		})
+	}
+
+	return s, nil
+}
+
+// SourceRangesRegistry holds the denied ranges of the middlewares, keyed by middleware name,
+// so that the files and URLs are not loaded again when the configuration is reloaded without changing them.
+type SourceRangesRegistry struct {
+	mu     sync.Mutex
+	ranges map[string]*registeredSourceRanges
+}
+
+type registeredSourceRanges struct {
+	config string
+	ranges *SourceRanges
+}
+
+// NewSourceRangesRegistry creates a new SourceRangesRegistry.
+func NewSourceRangesRegistry() *SourceRangesRegistry {
+	return &SourceRangesRegistry{ranges: make(map[string]*registeredSourceRanges)}
+}
+
+// Get returns the denied ranges of the middleware with the given name,
+// which are created only if the middleware did not have ranges with the same configuration yet.
+func (r *SourceRangesRegistry) Get(ctx context.Context, name string, inline, files, urls []string, refreshInterval time.Duration) (*SourceRanges, error) {
+	key, err := json.Marshal(struct {
+		Inline          []string
+		Files           []string
+		URLs            []string
+		RefreshInterval time.Duration
+	}{inline, files, urls, refreshInterval})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.ranges[name]; ok && previous.config == string(key) {
+		return previous.ranges, nil
+	}
+
+	ranges, err := NewSourceRanges(ctx, inline, files, urls, refreshInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ranges[name] = &registeredSourceRanges{config: string(key), ranges: ranges}
+
+	return ranges, nil
+}
+
+// Retain drops the denied ranges of the middlewares which are not in the given list,
+// i.e. which have been removed from the configuration.
+func (r *SourceRangesRegistry) Retain(names map[string]struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range r.ranges {
+		if _, ok := names[name]; !ok {
+			delete(r.ranges, name)
+		}
+	}
+}
+
+// Contains checks if provided address is in the denied ranges,
+// and triggers the reloading of the ranges if the refresh interval has elapsed.
+func (s *SourceRanges) Contains(ctx context.Context, addr string) (bool, error) {
+	if len(s.files) > 0 || len(s.urls) > 0 {
+		s.mu.Lock()
+		due := s.timeNow().Sub(s.loadedAt) >= s.refreshInterval
+		s.mu.Unlock()
+
+		if due && s.refreshing.CompareAndSwap(false, true) {
+			logger := log.Ctx(ctx).With().Logger()
+			safe.Go(func() {
+				defer s.refreshing.Store(false)
+
+				if err := s.refresh(context.Background()); err != nil {
+					logger.Error().Err(err).Msg("Unable to reload the denied IP ranges, keeping the previous ones")
+				}
+			})
+		}
+	}
+
+	return s.trie.Load().Contains(addr)
+}
+
+// Len returns the number of denied ranges.
+func (s *SourceRanges) Len() int {
+	return s.trie.Load().Len()
+}
+
+// refresh reloads the ranges, and swaps them if they have all been loaded successfully.
+// Following a failure, the next reload is attempted after the refresh interval too.
+func (s *SourceRanges) refresh(ctx context.Context) error {
+	trie, err := s.build(ctx, true)
+
+	s.mu.Lock()
+	s.loadedAt = s.timeNow()
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.trie.Store(trie)
+
+	log.Ctx(ctx).Debug().Msgf("Loaded %d denied IP ranges", trie.Len())
+
+	return nil
+}
+
+// build builds the trie of the inline ranges, the files, and, if withURLs is true, the URLs.
+func (s *SourceRanges) build(ctx context.Context, withURLs bool) (*ip.Trie, error) {
+	trie, err := ip.NewTrie(s.inline)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CIDRs %s: %w", s.inline, err)
+	}
+
+	for _, file := range s.files {
+		if err := loadFile(trie, file); err != nil {
+			return nil, err
+		}
+	}
+
+	if !withURLs {
+		return trie, nil
+	}
+
+	for _, url := range s.urls {
+		if err := s.loadURL(ctx, trie, url); err != nil {
+			return nil, err
+		}
+	}
+
+	return trie, nil
+}
+
+func loadFile(trie *ip.Trie, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening denied IP ranges file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := parseList(trie, file); err != nil {
+		return fmt.Errorf("reading denied IP ranges file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *SourceRanges) loadURL(ctx context.Context, trie *ip.Trie, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request for denied IP ranges URL %s: %w", url, err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching denied IP ranges URL %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching denied IP ranges URL %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	if err := parseList(trie, resp.Body); err != nil {
+		return fmt.Errorf("reading denied IP ranges URL %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// parseList inserts in the trie the IPs and CIDR ranges listed, one per line, by the given reader.
+// The empty lines are ignored, and so are the comments, starting with a # or a ; character,
+// which allows to use the common threat intelligence feed formats.
+func parseList(trie *ip.Trie, reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+
+	var line int
+	for scanner.Scan() {
+		line++
+
+		entry := scanner.Text()
+		if i := strings.IndexAny(entry, "#;"); i >= 0 {
+			entry = entry[:i]
+		}
+
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if err := trie.Insert(fields[0]); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,191 @@
+package ipdenylist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/ip"
+)
+
+func TestParseList(t *testing.T) {
+	list := `# FireHOL-like comment
+1.10.16.0/20 ; SBL256894
+
+2.56.192.0/22	# inline comment
+   10.0.0.1
+2001:db8::/32
+`
+
+	trie := &ip.Trie{}
+	err := parseList(trie, strings.NewReader(list))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, trie.Len())
+
+	for _, addr := range []string{"1.10.20.1", "2.56.193.1", "10.0.0.1", "2001:db8::1"} {
+		contains, err := trie.Contains(addr)
+		require.NoError(t, err)
+		assert.True(t, contains, addr)
+	}
+
+	err = parseList(trie, strings.NewReader("10.0.0.0/8\nfoo\n"))
+	require.ErrorContains(t, err, "line 2")
+}
+
+func TestSourceRanges_url(t *testing.T) {
+	var mu sync.Mutex
+	list := "10.0.0.0/8\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if list == "" {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write([]byte(list))
+	}))
+	t.Cleanup(server.Close)
+
+	ranges, err := NewSourceRanges(context.Background(), []string{"192.168.1.1"}, nil, []string{server.URL}, time.Hour)
+	require.NoError(t, err)
+
+	// The inline ranges are denied right away, and the URL ones once they have been fetched.
+	requireContains(t, ranges, "192.168.1.1", true)
+	assert.Eventually(t, func() bool {
+		contains, _ := ranges.Contains(context.Background(), "10.1.1.1")
+		return contains
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return !ranges.refreshing.Load()
+	}, time.Second, 10*time.Millisecond)
+
+	now := time.Now()
+	ranges.timeNow = func() time.Time { return now }
+
+	// The list is only reloaded once the refresh interval has elapsed.
+	mu.Lock()
+	list = "172.16.0.0/12\n"
+	mu.Unlock()
+
+	requireContains(t, ranges, "172.16.1.1", false)
+
+	now = now.Add(time.Hour)
+	requireContains(t, ranges, "10.1.1.1", true)
+
+	assert.Eventually(t, func() bool {
+		contains, _ := ranges.Contains(context.Background(), "172.16.1.1")
+		return contains
+	}, time.Second, 10*time.Millisecond)
+	requireContains(t, ranges, "10.1.1.1", false)
+
+	// The previous ranges are kept when the list cannot be fetched.
+	mu.Lock()
+	list = ""
+	mu.Unlock()
+
+	now = now.Add(time.Hour)
+	requireContains(t, ranges, "172.16.1.1", true)
+
+	assert.Eventually(t, func() bool {
+		return !ranges.refreshing.Load()
+	}, time.Second, 10*time.Millisecond)
+	requireContains(t, ranges, "172.16.1.1", true)
+	requireContains(t, ranges, "192.168.1.1", true)
+}
+
+func TestSourceRanges_file(t *testing.T) {
+	listFile := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(listFile, []byte("10.0.0.0/8\n"), 0o644)
+	require.NoError(t, err)
+
+	ranges, err := NewSourceRanges(context.Background(), nil, []string{listFile}, nil, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	ranges.timeNow = func() time.Time { return now }
+
+	requireContains(t, ranges, "10.1.1.1", true)
+
+	err = os.WriteFile(listFile, []byte("172.16.0.0/12\n"), 0o644)
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	requireContains(t, ranges, "10.1.1.1", true)
+
+	assert.Eventually(t, func() bool {
+		contains, _ := ranges.Contains(context.Background(), "172.16.1.1")
+		return contains
+	}, time.Second, 10*time.Millisecond)
+	requireContains(t, ranges, "10.1.1.1", false)
+}
+
+func TestNewSourceRanges_invalid(t *testing.T) {
+	_, err := NewSourceRanges(context.Background(), nil, nil, nil, 0)
+	require.Error(t, err)
+
+	_, err = NewSourceRanges(context.Background(), []string{"10.0.0.1"}, nil, nil, -time.Second)
+	require.Error(t, err)
+
+	_, err = NewSourceRanges(context.Background(), []string{"10.0.0.1/33"}, nil, nil, 0)
+	require.Error(t, err)
+}
+
+func TestSourceRangesRegistry(t *testing.T) {
+	listFile := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(listFile, []byte("10.0.0.0/8\n"), 0o644)
+	require.NoError(t, err)
+
+	registry := NewSourceRangesRegistry()
+
+	ranges, err := registry.Get(context.Background(), "foo", nil, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+
+	// The file is not read again when the configuration is reloaded without change.
+	err = os.WriteFile(listFile, []byte("172.16.0.0/12\n"), 0o644)
+	require.NoError(t, err)
+
+	reloaded, err := registry.Get(context.Background(), "foo", nil, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+	assert.Same(t, ranges, reloaded)
+	requireContains(t, reloaded, "10.1.1.1", true)
+
+	other, err := registry.Get(context.Background(), "bar", nil, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+	assert.NotSame(t, ranges, other)
+	requireContains(t, other, "172.16.1.1", true)
+
+	changed, err := registry.Get(context.Background(), "foo", []string{"192.168.1.1"}, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+	assert.NotSame(t, ranges, changed)
+	requireContains(t, changed, "172.16.1.1", true)
+
+	registry.Retain(map[string]struct{}{"bar": {}})
+
+	recreated, err := registry.Get(context.Background(), "foo", []string{"192.168.1.1"}, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+	assert.NotSame(t, changed, recreated)
+
+	kept, err := registry.Get(context.Background(), "bar", nil, []string{listFile}, nil, 0)
+	require.NoError(t, err)
+	assert.Same(t, other, kept)
+}
+
+func requireContains(t *testing.T, ranges *SourceRanges, addr string, expected bool) {
+	t.Helper()
+
+	contains, err := ranges.Contains(context.Background(), addr)
+	require.NoError(t, err)
+	require.Equal(t, expected, contains, addr)
+}
@@ -3,11 +3,14 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/mailgun/ttlmap"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -21,11 +24,33 @@ import (
 const (
 	typeName   = "RateLimiter"
 	maxSources = 65536
+
+	// defaultLimitName is the name of the rate limit defined at the root of the middleware configuration.
+	defaultLimitName = "default"
+	// limitHeader is the response header naming the rate limit which rejected the request.
+	limitHeader = "X-RateLimit-Tier"
 )
 
-// rateLimiter implements rate limiting and traffic shaping with a set of token buckets;
-// one for each traffic source. The same parameters are applied to all the buckets.
+var errNoBurst = errors.New("no bursty traffic allowed")
+
+// rateLimiter implements rate limiting and traffic shaping with a list of rate limits.
+// A request has to conform to all of them to be forwarded.
 type rateLimiter struct {
+	name   string
+	next   http.Handler
+	limits []*limit
+	// tiered is whether the middleware has tiers,
+	// in which case the rejections name the exceeded rate limit in the limitHeader.
+	tiered bool
+
+	sendHeaders bool
+	// rejectionsCounter counts the rejected requests, when the metrics are enabled.
+	rejectionsCounter metrics.Counter
+}
+
+// limit implements a rate limit with a set of token buckets;
+// one for each traffic source. The same parameters are applied to all the buckets.
+type limit struct {
 	name  string
 	rate  rate.Limit // reqs/s
 	burst int64
@@ -38,18 +63,90 @@ type rateLimiter struct {
 	// It is considered expired after it hasn't been used for ttl seconds.
 	ttl           int
 	sourceMatcher utils.SourceExtractor
-	next          http.Handler
 
 	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
+	// redis, when set, holds the buckets shared with the other Traefik instances.
+	// The local buckets are used as a fallback when Redis is unreachable.
+	redis *redisLimiter
+}
+
+// quota is the state of the token bucket of a source, after a reservation.
+type quota struct {
+	// limit is the size of the token bucket.
+	limit int64
+	// remaining is the number of requests which can be sent without delay.
+	remaining int64
+	// reset is the duration until the token bucket is full again.
+	reset time.Duration
+}
+
+// reservation is a token reserved in the token bucket of a source.
+type reservation struct {
+	delay time.Duration
+	quota quota
+	// cancel gives the token back to the bucket.
+	cancel func()
 }
 
 // New returns a rate limiter middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string) (http.Handler, error) {
+// The rejectionsCounter, if not nil, counts the requests rejected by the middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, rejectionsCounter metrics.Counter, name string) (http.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
 	ctxLog := logger.WithContext(ctx)
 
+	rl := &rateLimiter{
+		name:        name,
+		next:        next,
+		tiered:      len(config.Tiers) > 0,
+		sendHeaders: config.SendHeaders,
+	}
+
+	if rejectionsCounter != nil {
+		rl.rejectionsCounter = rejectionsCounter.With("middleware", name)
+	}
+
+	// The default rate limit does not limit anything when its average is not set,
+	// so it is only needed when there is no tier.
+	if config.Average > 0 || len(config.Tiers) == 0 {
+		defaultLimit := dynamic.RateLimitTier{
+			Name:            defaultLimitName,
+			Average:         config.Average,
+			Period:          config.Period,
+			Burst:           config.Burst,
+			SourceCriterion: config.SourceCriterion,
+		}
+
+		l, err := newLimit(ctxLog, name, defaultLimit, config.Redis)
+		if err != nil {
+			return nil, err
+		}
+		rl.limits = append(rl.limits, l)
+	}
+
+	names := map[string]struct{}{defaultLimitName: {}}
+	for _, tier := range config.Tiers {
+		if tier.Name == "" {
+			return nil, errors.New("rate limit tier name must be set")
+		}
+
+		if _, exists := names[tier.Name]; exists {
+			return nil, fmt.Errorf("duplicate rate limit tier name: %s", tier.Name)
+		}
+		names[tier.Name] = struct{}{}
+
+		l, err := newLimit(ctxLog, name, tier, config.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit tier %s: %w", tier.Name, err)
+		}
+		rl.limits = append(rl.limits, l)
+	}
+
+	return rl, nil
+}
+
+func newLimit(ctx context.Context, middlewareName string, config dynamic.RateLimitTier, redisConfig *dynamic.Redis) (*limit, error) {
 	if config.SourceCriterion == nil ||
 		config.SourceCriterion.IPStrategy == nil &&
 			config.SourceCriterion.RequestHeaderName == "" && !config.SourceCriterion.RequestHost {
@@ -58,7 +155,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		}
 	}
 
-	sourceMatcher, err := middlewares.GetSourceExtractor(ctxLog, config.SourceCriterion)
+	sourceMatcher, err := middlewares.GetSourceExtractor(ctx, config.SourceCriterion)
 	if err != nil {
 		return nil, err
 	}
@@ -110,16 +207,27 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		ttl += int(1 / rtl)
 	}
 
-	return &rateLimiter{
-		name:          name,
+	l := &limit{
+		name:          config.Name,
 		rate:          rate.Limit(rtl),
 		burst:         burst,
 		maxDelay:      maxDelay,
-		next:          next,
 		sourceMatcher: sourceMatcher,
 		buckets:       buckets,
 		ttl:           ttl,
-	}, nil
+	}
+
+	// There is nothing to share between the instances when the rate is infinite.
+	if redisConfig != nil && config.Average > 0 {
+		interval := max(time.Duration(float64(time.Second)/rtl), time.Microsecond)
+
+		l.redis, err = newRedisLimiter(ctx, redisConfig, middlewareName, middlewareName+":"+config.Name, interval, burst, maxDelay)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
 }
 
 func (rl *rateLimiter) GetTracingInformation() (string, string, trace.SpanKind) {
@@ -130,53 +238,170 @@ func (rl *rateLimiter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := middlewares.GetLogger(req.Context(), rl.name, typeName)
 	ctx := logger.WithContext(req.Context())
 
-	source, amount, err := rl.sourceMatcher.Extract(req)
+	var (
+		reservations []*reservation
+		delay        time.Duration
+		// q is the quota of the most restrictive rate limit, reported in the headers.
+		q *quota
+	)
+
+	for _, l := range rl.limits {
+		res, err := l.reserve(ctx, req)
+		if err != nil {
+			cancelReservations(reservations)
+
+			if errors.Is(err, errNoBurst) {
+				rl.countRejection()
+				tracing.SetStatusErrorf(req.Context(), "No bursty traffic allowed")
+				rl.setLimitHeader(rw, l.name)
+				http.Error(rw, "No bursty traffic allowed", http.StatusTooManyRequests)
+				return
+			}
+
+			logger.Error().Err(err).Msg("Could not reserve token")
+			tracing.SetStatusErrorf(req.Context(), "Could not reserve token")
+			http.Error(rw, "could not reserve token", http.StatusInternalServerError)
+			return
+		}
+
+		if res.delay > l.maxDelay {
+			cancelReservations(reservations)
+
+			var resQuota *quota
+			if l.rate != rate.Inf {
+				resQuota = &res.quota
+			}
+
+			rl.serveDelayError(ctx, rw, l.name, res.delay, resQuota)
+			return
+		}
+
+		reservations = append(reservations, res)
+		delay = max(delay, res.delay)
+
+		// Nothing is limited with an infinite rate.
+		if l.rate != rate.Inf && (q == nil || res.quota.remaining < q.remaining) {
+			q = &res.quota
+		}
+	}
+
+	rl.setQuotaHeaders(rw, q)
+	time.Sleep(delay)
+	rl.next.ServeHTTP(rw, req)
+}
+
+// reserve reserves a token in the bucket of the source of the request.
+// When the delay of the reservation exceeds the maximum delay, no token is reserved.
+func (l *limit) reserve(ctx context.Context, req *http.Request) (*reservation, error) {
+	logger := log.Ctx(ctx)
+
+	source, amount, err := l.sourceMatcher.Extract(req)
 	if err != nil {
-		logger.Error().Err(err).Msg("Could not extract source of request")
-		http.Error(rw, "could not extract source of request", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("could not extract source of request: %w", err)
 	}
 
 	if amount != 1 {
 		logger.Info().Msgf("ignoring token bucket amount > 1: %d", amount)
 	}
 
+	if l.redis != nil {
+		res, err := l.redis.reserve(ctx, source)
+		if err == nil {
+			return res, nil
+		}
+
+		if !errors.Is(err, errRedisUnavailable) {
+			logger.Warn().Err(err).Msg("Could not reserve token from Redis, falling back to local rate limiting")
+		}
+	}
+
 	var bucket *rate.Limiter
-	if rlSource, exists := rl.buckets.Get(source); exists {
+	if rlSource, exists := l.buckets.Get(source); exists {
 		bucket = rlSource.(*rate.Limiter)
 	} else {
-		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
+		bucket = rate.NewLimiter(l.rate, int(l.burst))
 	}
 
 	// We Set even in the case where the source already exists,
 	// because we want to update the expiryTime everytime we get the source,
 	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
-	if err := rl.buckets.Set(source, bucket, rl.ttl); err != nil {
-		logger.Error().Err(err).Msg("Could not insert/update bucket")
-		tracing.SetStatusErrorf(req.Context(), "Could not insert/update bucket")
-		http.Error(rw, "could not insert/update bucket", http.StatusInternalServerError)
-		return
+	if err := l.buckets.Set(source, bucket, l.ttl); err != nil {
+		return nil, fmt.Errorf("could not insert/update bucket: %w", err)
 	}
 
-	res := bucket.Reserve()
+	// The reservation is cancelled at the time it was made,
+	// as a reservation which already took effect can not give its token back.
+	now := time.Now()
+
+	res := bucket.ReserveN(now, 1)
 	if !res.OK() {
-		tracing.SetStatusErrorf(req.Context(), "No bursty traffic allowed")
-		http.Error(rw, "No bursty traffic allowed", http.StatusTooManyRequests)
-		return
+		return nil, errNoBurst
+	}
+
+	delay := res.DelayFrom(now)
+	if delay > l.maxDelay {
+		res.CancelAt(now)
+
+		return &reservation{
+			delay:  delay,
+			quota:  l.localQuota(bucket),
+			cancel: func() {},
+		}, nil
 	}
 
-	delay := res.Delay()
-	if delay > rl.maxDelay {
-		res.Cancel()
-		rl.serveDelayError(ctx, rw, delay)
+	return &reservation{
+		delay:  delay,
+		quota:  l.localQuota(bucket),
+		cancel: func() { res.CancelAt(now) },
+	}, nil
+}
+
+// localQuota returns the quota left in the given local token bucket.
+func (l *limit) localQuota(bucket *rate.Limiter) quota {
+	tokens := bucket.Tokens()
+
+	return quota{
+		limit:     l.burst,
+		remaining: max(int64(math.Floor(tokens)), 0),
+		reset:     time.Duration((float64(l.burst) - tokens) / float64(l.rate) * float64(time.Second)),
+	}
+}
+
+func cancelReservations(reservations []*reservation) {
+	for _, res := range reservations {
+		res.cancel()
+	}
+}
+
+// setQuotaHeaders reports the given quota in the rate limit headers of the response.
+// The limit is the size of the token bucket, i.e. the number of requests which can be sent at once.
+func (rl *rateLimiter) setQuotaHeaders(w http.ResponseWriter, q *quota) {
+	if !rl.sendHeaders || q == nil {
 		return
 	}
 
-	time.Sleep(delay)
-	rl.next.ServeHTTP(rw, req)
+	w.Header().Set("RateLimit-Limit", strconv.FormatInt(q.limit, 10))
+	w.Header().Set("RateLimit-Remaining", strconv.FormatInt(q.remaining, 10))
+	w.Header().Set("RateLimit-Reset", fmt.Sprintf("%.0f", math.Ceil(q.reset.Seconds())))
+}
+
+func (rl *rateLimiter) countRejection() {
+	if rl.rejectionsCounter != nil {
+		rl.rejectionsCounter.Add(1)
+	}
 }
 
-func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, delay time.Duration) {
+func (rl *rateLimiter) setLimitHeader(w http.ResponseWriter, limitName string) {
+	if rl.tiered {
+		w.Header().Set(limitHeader, limitName)
+	}
+}
+
+func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, limitName string, delay time.Duration, q *quota) {
+	rl.countRejection()
+	rl.setQuotaHeaders(w, q)
+
+	rl.setLimitHeader(w, limitName)
 	w.Header().Set("Retry-After", fmt.Sprintf("%.0f", math.Ceil(delay.Seconds())))
 	w.Header().Set("X-Retry-In", delay.String())
 	w.WriteHeader(http.StatusTooManyRequests)
@@ -3,12 +3,15 @@ package ratelimiter
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
@@ -86,6 +89,44 @@ func TestNewRateLimiter(t *testing.T) {
 			},
 			expectedError: "iPStrategy and RequestHeaderName are mutually exclusive",
 		},
+		{
+			desc: "tier name must be set",
+			config: dynamic.RateLimit{
+				Tiers: []dynamic.RateLimitTier{
+					{Average: 200},
+				},
+			},
+			expectedError: "rate limit tier name must be set",
+		},
+		{
+			desc: "tier names are unique",
+			config: dynamic.RateLimit{
+				Tiers: []dynamic.RateLimitTier{
+					{Name: "foo", Average: 200},
+					{Name: "foo", Average: 100},
+				},
+			},
+			expectedError: "duplicate rate limit tier name: foo",
+		},
+		{
+			desc: "tier name cannot be default",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Tiers: []dynamic.RateLimitTier{
+					{Name: "default", Average: 100},
+				},
+			},
+			expectedError: "duplicate rate limit tier name: default",
+		},
+		{
+			desc: "invalid tier period",
+			config: dynamic.RateLimit{
+				Tiers: []dynamic.RateLimitTier{
+					{Name: "foo", Average: 100, Period: ptypes.Duration(-time.Second)},
+				},
+			},
+			expectedError: "rate limit tier foo: negative value not valid for period: -1s",
+		},
 	}
 
 	for _, test := range testCases {
@@ -95,7 +136,7 @@ func TestNewRateLimiter(t *testing.T) {
 
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
-			h, err := New(context.Background(), next, test.config, "rate-limiter")
+			h, err := New(context.Background(), next, test.config, nil, "rate-limiter")
 			if test.expectedError != "" {
 				assert.EqualError(t, err, test.expectedError)
 			} else {
@@ -104,11 +145,11 @@ func TestNewRateLimiter(t *testing.T) {
 
 			rtl, _ := h.(*rateLimiter)
 			if test.expectedMaxDelay != 0 {
-				assert.Equal(t, test.expectedMaxDelay, rtl.maxDelay)
+				assert.Equal(t, test.expectedMaxDelay, rtl.limits[0].maxDelay)
 			}
 
 			if test.expectedSourceIP != "" {
-				extractor, ok := rtl.sourceMatcher.(utils.ExtractorFunc)
+				extractor, ok := rtl.limits[0].sourceMatcher.(utils.ExtractorFunc)
 				require.True(t, ok, "Not an ExtractorFunc")
 
 				req := http.Request{
@@ -120,7 +161,7 @@ func TestNewRateLimiter(t *testing.T) {
 				assert.Equal(t, test.expectedSourceIP, ip)
 			}
 			if test.requestHeader != "" {
-				extractor, ok := rtl.sourceMatcher.(utils.ExtractorFunc)
+				extractor, ok := rtl.limits[0].sourceMatcher.(utils.ExtractorFunc)
 				require.True(t, ok, "Not an ExtractorFunc")
 
 				req := http.Request{
@@ -133,7 +174,7 @@ func TestNewRateLimiter(t *testing.T) {
 				assert.Equal(t, test.requestHeader, hd)
 			}
 			if test.expectedRTL != 0 {
-				assert.InDelta(t, float64(test.expectedRTL), float64(rtl.rate), delta)
+				assert.InDelta(t, float64(test.expectedRTL), float64(rtl.limits[0].rate), delta)
 			}
 		})
 	}
@@ -260,7 +301,7 @@ func TestRateLimit(t *testing.T) {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				reqCount++
 			})
-			h, err := New(context.Background(), next, test.config, "rate-limiter")
+			h, err := New(context.Background(), next, test.config, nil, "rate-limiter")
 			require.NoError(t, err)
 
 			loadPeriod := time.Duration(1e9 / test.incomingLoad)
@@ -344,3 +385,226 @@ func computeMinCount(wantCount int) int {
 
 	return wantCount * 95 / 100
 }
+
+func TestRateLimit_redisUnavailable(t *testing.T) {
+	// Reserve a port, and release it, so that nothing listens on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	h, err := New(context.Background(), next, dynamic.RateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Minute),
+		Burst:   2,
+		Redis:   &dynamic.Redis{Endpoints: []string{addr}},
+	}, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	var codes []int
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		codes = append(codes, rw.Code)
+	}
+
+	// The local token buckets enforce the rate limit.
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestGetRedisClient(t *testing.T) {
+	t.Cleanup(func() {
+		redisClientsMu.Lock()
+		defer redisClientsMu.Unlock()
+
+		delete(redisClients, "redis-client")
+	})
+
+	client, err := getRedisClient(context.Background(), "redis-client", &dynamic.Redis{Endpoints: []string{"127.0.0.1:6379"}})
+	require.NoError(t, err)
+
+	// The client is kept while the Redis configuration of the middleware is unchanged.
+	sameClient, err := getRedisClient(context.Background(), "redis-client", &dynamic.Redis{Endpoints: []string{"127.0.0.1:6379"}})
+	require.NoError(t, err)
+	assert.Same(t, client, sameClient)
+
+	// The client is closed and replaced once the Redis configuration of the middleware changed.
+	newClient, err := getRedisClient(context.Background(), "redis-client", &dynamic.Redis{Endpoints: []string{"127.0.0.1:6380"}})
+	require.NoError(t, err)
+	assert.NotSame(t, client, newClient)
+	assert.ErrorIs(t, client.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	// The client is closed and dropped once the middleware has been removed.
+	Retain(map[string]struct{}{})
+	assert.ErrorIs(t, newClient.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	redisClientsMu.Lock()
+	assert.NotContains(t, redisClients, "redis-client")
+	redisClientsMu.Unlock()
+}
+
+func TestRateLimit_headers(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rejectionsCounter := &testhelpers.CollectingCounter{}
+
+	h, err := New(context.Background(), next, dynamic.RateLimit{
+		Average:     1,
+		Period:      ptypes.Duration(time.Minute),
+		Burst:       2,
+		SendHeaders: true,
+	}, rejectionsCounter, "rate-limiter")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		expectedStatus     int
+		expectedRemaining  string
+		expectedReset      int
+		expectedRetryAfter string
+	}{
+		{expectedStatus: http.StatusOK, expectedRemaining: "1", expectedReset: 60},
+		{expectedStatus: http.StatusOK, expectedRemaining: "0", expectedReset: 120},
+		{expectedStatus: http.StatusTooManyRequests, expectedRemaining: "0", expectedReset: 120, expectedRetryAfter: "60"},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		assert.Equal(t, test.expectedStatus, rw.Code)
+		assert.Equal(t, "2", rw.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, test.expectedRemaining, rw.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, test.expectedRetryAfter, rw.Header().Get("Retry-After"))
+		// Without tiers, the rejections do not name the exceeded rate limit.
+		assert.Empty(t, rw.Header().Get("X-RateLimit-Tier"))
+
+		reset, err := strconv.Atoi(rw.Header().Get("RateLimit-Reset"))
+		require.NoError(t, err)
+		assert.InDelta(t, test.expectedReset, reset, 1)
+	}
+
+	assert.Equal(t, float64(1), rejectionsCounter.CounterValue)
+	assert.Equal(t, []string{"middleware", "rate-limiter"}, rejectionsCounter.LastLabelValues)
+}
+
+func TestRateLimit_tiers(t *testing.T) {
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	})
+
+	h, err := New(context.Background(), next, dynamic.RateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Minute),
+		Burst:   3,
+		Tiers: []dynamic.RateLimitTier{
+			{
+				Name:    "apikey",
+				Average: 1,
+				Period:  ptypes.Duration(time.Minute),
+				Burst:   1,
+				SourceCriterion: &dynamic.SourceCriterion{
+					RequestHeaderName: "X-Api-Key",
+				},
+			},
+		},
+	}, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		apiKey         string
+		expectedStatus int
+		expectedTier   string
+	}{
+		{
+			desc:           "first request with foo key",
+			apiKey:         "foo",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "apikey tier exhausted for foo key",
+			apiKey:         "foo",
+			expectedStatus: http.StatusTooManyRequests,
+			expectedTier:   "apikey",
+		},
+		{
+			desc:           "first request with bar key",
+			apiKey:         "bar",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			// The token reserved in the default tier by the rejected request has been given back.
+			desc:           "first request with baz key",
+			apiKey:         "baz",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "default tier exhausted",
+			apiKey:         "qux",
+			expectedStatus: http.StatusTooManyRequests,
+			expectedTier:   "default",
+		},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Api-Key", test.apiKey)
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		assert.Equal(t, test.expectedStatus, rw.Code, test.desc)
+		assert.Equal(t, test.expectedTier, rw.Header().Get("X-RateLimit-Tier"), test.desc)
+	}
+
+	assert.Equal(t, 3, served)
+}
+
+func TestRateLimit_noBurstTier(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	h, err := New(context.Background(), next, dynamic.RateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Minute),
+		Burst:   3,
+		Tiers: []dynamic.RateLimitTier{
+			{
+				Name:    "apikey",
+				Average: 1,
+				Period:  ptypes.Duration(time.Minute),
+				Burst:   1,
+				SourceCriterion: &dynamic.SourceCriterion{
+					RequestHeaderName: "X-Api-Key",
+				},
+			},
+		},
+	}, nil, "rate-limiter")
+	require.NoError(t, err)
+
+	// A bucket without burst rejects all the requests.
+	for _, l := range h.(*rateLimiter).limits {
+		if l.name == "apikey" {
+			require.NoError(t, l.buckets.Set("foo", rate.NewLimiter(rate.Every(time.Minute), 0), l.ttl))
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Api-Key", "foo")
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "apikey", rw.Header().Get("X-RateLimit-Tier"))
+}
@@ -0,0 +1,233 @@
+package ratelimiter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/logs"
+)
+
+const (
+	// redisTimeout is the timeout of the Redis operations.
+	// It is kept short, as the requests wait for the rate limiting decision.
+	redisTimeout = 500 * time.Millisecond
+	// redisRetryInterval is the duration during which the local token buckets are used after a Redis failure.
+	redisRetryInterval = 5 * time.Second
+	redisKeyPrefix     = "traefik:ratelimit:"
+)
+
+var errRedisUnavailable = errors.New("redis unavailable")
+
+// reserveScript implements the token bucket as a Generic Cell Rate Algorithm (GCRA).
+// The key holds the theoretical arrival time (TAT) of the next request, in microseconds.
+// A request is allowed when it arrives at most burst emission intervals before the TAT,
+// and it is delayed until then if it arrives later than that.
+// The script returns whether the request is allowed, the delay before it conforms,
+// and the duration until the bucket is full again, in microseconds.
+var reserveScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local max_delay = tonumber(ARGV[3])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local delay = new_tat - burst * interval - now
+if delay > max_delay then
+	return {0, delay, tat - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+
+return {1, math.max(delay, 0), new_tat - now}
+`)
+
+// cancelScript gives back a token reserved by the reserveScript, by moving the TAT back by one emission interval.
+var cancelScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+local ttl = redis.call('PTTL', KEYS[1])
+if tat and ttl > 0 then
+	redis.call('SET', KEYS[1], string.format('%.0f', tat - interval), 'PX', ttl)
+end
+
+return 0
+`)
+
+var (
+	redisClientsMu sync.Mutex
+	// redisClients holds the Redis clients, keyed by middleware name,
+	// so that a middleware keeps its connection pool across the configuration reloads while its Redis configuration is unchanged.
+	redisClients = make(map[string]*redisClient)
+)
+
+// redisClient is a Redis client along with the serialized configuration it was created with.
+type redisClient struct {
+	redis.UniversalClient
+
+	config string
+}
+
+// redisLimiter stores the token buckets in Redis.
+type redisLimiter struct {
+	client redis.UniversalClient
+	name   string
+	// interval is the emission interval of the tokens, in microseconds.
+	interval int64
+	burst    int64
+	// maxDelay is the maximum delay of a request, in microseconds.
+	maxDelay int64
+
+	// retryAt holds the time, in Unix nanoseconds, after which Redis is used again following a failure.
+	retryAt atomic.Int64
+}
+
+func newRedisLimiter(ctx context.Context, config *dynamic.Redis, middlewareName, name string, interval time.Duration, burst int64, maxDelay time.Duration) (*redisLimiter, error) {
+	client, err := getRedisClient(ctx, middlewareName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redisLimiter{
+		client:   client,
+		name:     name,
+		interval: interval.Microseconds(),
+		burst:    burst,
+		maxDelay: maxDelay.Microseconds(),
+	}, nil
+}
+
+// getRedisClient returns the Redis client of the given middleware,
+// replacing the previous one if the Redis configuration of the middleware changed.
+func getRedisClient(ctx context.Context, middlewareName string, config *dynamic.Redis) (redis.UniversalClient, error) {
+	key, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	if client, ok := redisClients[middlewareName]; ok {
+		if client.config == string(key) {
+			return client.UniversalClient, nil
+		}
+
+		if err = client.Close(); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Unable to close the previous Redis client")
+		}
+		delete(redisClients, middlewareName)
+	}
+
+	options := &redis.UniversalOptions{
+		Addrs:        config.Endpoints,
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           config.DB,
+		DialTimeout:  redisTimeout,
+		ReadTimeout:  redisTimeout,
+		WriteTimeout: redisTimeout,
+		MaxRetries:   -1,
+	}
+
+	if len(options.Addrs) == 0 {
+		options.Addrs = []string{"127.0.0.1:6379"}
+	}
+
+	if config.TLS != nil {
+		options.TLSConfig, err = config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+		}
+	}
+
+	client := redis.NewUniversalClient(options)
+	redisClients[middlewareName] = &redisClient{UniversalClient: client, config: string(key)}
+
+	return client, nil
+}
+
+// Retain closes and drops the Redis clients of the rate limit middlewares which are not in the given list,
+// i.e. which have been removed from the configuration, or do not use Redis anymore.
+func Retain(names map[string]struct{}) {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	for name, client := range redisClients {
+		if _, ok := names[name]; ok {
+			continue
+		}
+
+		if err := client.Close(); err != nil {
+			log.Warn().Err(err).Str(logs.MiddlewareName, name).Msg("Unable to close the Redis client")
+		}
+		delete(redisClients, name)
+	}
+}
+
+// reserve reserves a token in the bucket of the given source.
+// When the delay exceeds the maximum delay, no token is reserved, and cancelling the reservation is a no-op.
+func (r *redisLimiter) reserve(ctx context.Context, source string) (*reservation, error) {
+	if time.Now().UnixNano() < r.retryAt.Load() {
+		return nil, errRedisUnavailable
+	}
+
+	key := redisKeyPrefix + r.name + ":" + source
+
+	reserveCtx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	result, err := reserveScript.Run(reserveCtx, r.client, []string{key}, r.interval, r.burst, r.maxDelay).Int64Slice()
+	if err != nil {
+		r.retryAt.Store(time.Now().Add(redisRetryInterval).UnixNano())
+		return nil, err
+	}
+
+	if len(result) != 3 {
+		return nil, fmt.Errorf("unexpected reserve script result: %v", result)
+	}
+
+	res := &reservation{
+		delay: time.Duration(result[1]) * time.Microsecond,
+		// The tokens left are the emission intervals between the TAT and the end of the burst window.
+		quota: quota{
+			limit:     r.burst,
+			remaining: max((r.burst*r.interval-result[2])/r.interval, 0),
+			reset:     time.Duration(result[2]) * time.Microsecond,
+		},
+		cancel: func() {},
+	}
+
+	if result[0] == 1 {
+		res.cancel = func() {
+			r.cancel(ctx, key)
+		}
+	}
+
+	return res, nil
+}
+
+// cancel gives back the token reserved in the bucket with the given key.
+func (r *redisLimiter) cancel(ctx context.Context, key string) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	if err := cancelScript.Run(ctx, r.client, []string{key}, r.interval).Err(); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Could not cancel token reservation in Redis")
+	}
+}
@@ -57,8 +57,9 @@ func (r *ResponseModifier) WriteHeader(code int) {
 	}
 
 	resp := http.Response{
-		Header:  r.rw.Header(),
-		Request: r.req,
+		StatusCode: code,
+		Header:     r.rw.Header(),
+		Request:    r.req,
 	}
 
 	if err := r.modifier(&resp); err != nil {
@@ -0,0 +1,108 @@
+// Package bandwidthlimiter implements a TCP middleware throttling the connections with token buckets.
+package bandwidthlimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bandwidthlimiter/shaper"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const typeName = "BandwidthLimiterTCP"
+
+// bandwidthLimiter throttles the connections in both directions,
+// with token buckets for each connection, and token buckets shared by the connections from the same IP.
+type bandwidthLimiter struct {
+	name string
+	next tcp.Handler
+
+	rate  int64
+	burst int64
+
+	sources *shaper.Sources
+}
+
+// New creates a TCP bandwidth limiter middleware.
+// The connections are identified and grouped by remote IP.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPBandwidthLimit, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if err := shaper.ValidateLimit(config.Rate, config.Burst); err != nil {
+		return nil, err
+	}
+
+	if err := shaper.ValidateLimit(config.SourceRate, config.SourceBurst); err != nil {
+		return nil, fmt.Errorf("invalid source limit: %w", err)
+	}
+
+	if config.Rate == 0 && config.SourceRate == 0 {
+		return nil, errors.New("at least one of rate and sourceRate must be defined")
+	}
+
+	return &bandwidthLimiter{
+		name:    name,
+		next:    next,
+		rate:    config.Rate,
+		burst:   config.Burst,
+		sources: shaper.NewSources(config.SourceRate, config.SourceBurst),
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (b *bandwidthLimiter) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), b.name, typeName)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot parse IP from remote addr")
+		conn.Close()
+		return
+	}
+
+	sourceRead, sourceWrite := b.sources.Acquire(ip)
+	defer b.sources.Release(ip)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.next.ServeTCP(&throttledConn{
+		WriteCloser: conn,
+		cancel:      cancel,
+		reader:      shaper.NewReader(ctx, conn, shaper.NewBucket(b.rate, b.burst), sourceRead),
+		writer:      shaper.NewWriter(ctx, conn, shaper.NewBucket(b.rate, b.burst), sourceWrite),
+	})
+}
+
+// throttledConn throttles the reads and the writes of a connection.
+type throttledConn struct {
+	tcp.WriteCloser
+
+	// cancel interrupts the reads and writes waiting for tokens.
+	cancel context.CancelFunc
+	reader *shaper.Reader
+	writer *shaper.Writer
+}
+
+func (c *throttledConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c *throttledConn) Write(p []byte) (int, error) {
+	return c.writer.Write(p)
+}
+
+func (c *throttledConn) Close() error {
+	c.cancel()
+	return c.WriteCloser.Close()
+}
+
+// NetConn returns the throttled connection.
+func (c *throttledConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
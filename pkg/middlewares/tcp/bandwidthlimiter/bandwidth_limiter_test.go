@@ -0,0 +1,176 @@
+package bandwidthlimiter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPBandwidthLimit
+		expectedError bool
+	}{
+		{
+			desc:   "rate",
+			config: dynamic.TCPBandwidthLimit{Rate: 1000, Burst: 100},
+		},
+		{
+			desc:   "source rate",
+			config: dynamic.TCPBandwidthLimit{SourceRate: 1000, SourceBurst: 100},
+		},
+		{
+			desc:          "no rate",
+			config:        dynamic.TCPBandwidthLimit{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative rate",
+			config:        dynamic.TCPBandwidthLimit{Rate: -1},
+			expectedError: true,
+		},
+		{
+			desc:          "negative source rate",
+			config:        dynamic.TCPBandwidthLimit{Rate: 1000, SourceRate: -1},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, test.config, "foo")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBandwidthLimiter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.TCPBandwidthLimit
+	}{
+		{
+			desc:   "connection rate",
+			config: dynamic.TCPBandwidthLimit{Rate: 1000, Burst: 100},
+		},
+		{
+			desc:   "source rate",
+			config: dynamic.TCPBandwidthLimit{SourceRate: 1000, SourceBurst: 100},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			data := bytes.Repeat([]byte("a"), 300)
+
+			// The handler echoes what it reads, so that both directions are throttled.
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				buf := make([]byte, len(data))
+				if _, err := io.ReadFull(conn, buf); err != nil {
+					return
+				}
+				_, _ = conn.Write(buf)
+			})
+
+			middleware, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			server, client := net.Pipe()
+			t.Cleanup(func() {
+				_ = client.Close()
+			})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				middleware.ServeTCP(fakeConn{Conn: server})
+			}()
+
+			start := time.Now()
+
+			go func() {
+				_, _ = client.Write(data)
+			}()
+
+			// Each direction needs 200ms worth of tokens after the first 100 bytes.
+			received := make([]byte, len(data))
+			_, err = io.ReadFull(client, received)
+			require.NoError(t, err)
+
+			assert.Equal(t, data, received)
+			assert.GreaterOrEqual(t, time.Since(start), 350*time.Millisecond)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Timeout waiting for the connection to be served")
+			}
+		})
+	}
+}
+
+func TestThrottledConn_Close(t *testing.T) {
+	blocked := make(chan error)
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		go func() {
+			// The write waits for tokens, until the connection is closed.
+			_, err := conn.Write(bytes.Repeat([]byte("a"), 20))
+			blocked <- err
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		_ = conn.Close()
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPBandwidthLimit{Rate: 1, Burst: 10}, "foo")
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	go func() {
+		_, _ = io.Copy(io.Discard, client)
+	}()
+
+	middleware.ServeTCP(fakeConn{Conn: server})
+
+	select {
+	case err := <-blocked:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the write to be interrupted")
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+}
+
+func (c fakeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}
+}
+
+func (c fakeConn) CloseWrite() error {
+	return c.Close()
+}
@@ -0,0 +1,74 @@
+package ipdenylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	httpipdenylist "github.com/traefik/traefik/v3/pkg/middlewares/ipdenylist"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const (
+	typeName = "IPDenyListerTCP"
+)
+
+// registry holds the denied ranges of the middlewares across the configuration reloads.
+var registry = httpipdenylist.NewSourceRangesRegistry()
+
+// ipDenyLister is a middleware that provides Checks of the Requesting IP against a set of Denylists.
+type ipDenyLister struct {
+	next       tcp.Handler
+	denyLister *httpipdenylist.SourceRanges
+	name       string
+}
+
+// New builds a new TCP IPDenyLister given a list of CIDR-Strings, files, and URLs to deny.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPDenyList, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	denyLister, err := registry.Get(logger.WithContext(ctx), name, config.SourceRange, config.SourceRangeFiles, config.SourceRangeURLs, time.Duration(config.RefreshInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().Msgf("Setting up IPDenyLister with sourceRange: %s, sourceRangeFiles: %s, sourceRangeURLs: %s",
+		config.SourceRange, config.SourceRangeFiles, config.SourceRangeURLs)
+
+	return &ipDenyLister{
+		denyLister: denyLister,
+		next:       next,
+		name:       name,
+	}, nil
+}
+
+// Retain drops the denied ranges of the TCP IPDenyList middlewares which are not in the given list,
+// i.e. which have been removed from the configuration.
+func Retain(names map[string]struct{}) {
+	registry.Retain(names)
+}
+
+func (dl *ipDenyLister) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), dl.name, typeName)
+
+	addr := conn.RemoteAddr().String()
+
+	denied, err := dl.denyLister.Contains(logger.WithContext(context.Background()), addr)
+	if err != nil {
+		logger.Error().Err(err).Msgf("Connection from %s rejected", addr)
+		conn.Close()
+		return
+	}
+
+	if denied {
+		logger.Debug().Msgf("Connection from %s rejected: denied", addr)
+		conn.Close()
+		return
+	}
+
+	logger.Debug().Msgf("Connection from %s accepted", addr)
+
+	dl.next.ServeTCP(conn)
+}
@@ -0,0 +1,167 @@
+// Package ratelimiter implements a TCP connection rate limiting middleware with a set of token buckets.
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter limits the rate of new connections with a set of token buckets;
+// one for each source IP. The same parameters are applied to all the buckets.
+type rateLimiter struct {
+	name string
+	next tcp.Handler
+
+	rate  rate.Limit // conns/s
+	burst int
+	// maxDelay is the maximum duration a connection is delayed for its bucket reservation to become effective.
+	maxDelay time.Duration
+	// ttl is the number of seconds after which the bucket of an inactive source is removed.
+	ttl      int
+	strategy ip.Strategy
+
+	buckets *ttlmap.TtlMap // actual buckets, keyed by source IP.
+
+	// rejectionsCounter counts the rejected connections, when the metrics are enabled.
+	rejectionsCounter metrics.Counter
+}
+
+// New creates a TCP rate limiter middleware.
+// The connections are identified and grouped by remote IP.
+// The rejectionsCounter, if not nil, counts the connections rejected by the middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, rejectionsCounter metrics.Counter, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.Average <= 0 {
+		return nil, errors.New("average must be greater than zero")
+	}
+
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	maxDelay := time.Duration(config.MaxDelay)
+	if maxDelay < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxDelay: %v", maxDelay)
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	rtl := float64(config.Average*int64(time.Second)) / float64(period)
+
+	// Make the ttl inversely proportional to how often a bucket is supposed to see any activity (when maxed out),
+	// for low rates, and keep the delayed connections accounted for.
+	ttl := 1 + int(maxDelay.Seconds())
+	if rtl >= 1 {
+		ttl++
+	} else {
+		ttl += int(1 / rtl)
+	}
+
+	rl := &rateLimiter{
+		name:     name,
+		next:     next,
+		rate:     rate.Limit(rtl),
+		burst:    int(burst),
+		maxDelay: maxDelay,
+		ttl:      ttl,
+		strategy: &ip.RemoteAddrStrategy{},
+		buckets:  buckets,
+	}
+
+	if rejectionsCounter != nil {
+		rl.rejectionsCounter = rejectionsCounter.With("middleware", name)
+	}
+
+	return rl, nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (rl *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), rl.name, typeName)
+
+	source := rl.strategy.GetIP(&http.Request{RemoteAddr: conn.RemoteAddr().String()})
+
+	delay, err := rl.reserve(source)
+	if err != nil {
+		logger.Debug().Err(err).Msgf("Connection from %s rejected", source)
+		rl.countRejection()
+		conn.Close()
+		return
+	}
+
+	if delay > 0 {
+		logger.Debug().Msgf("Connection from %s delayed by %s", source, delay)
+		time.Sleep(delay)
+	}
+
+	rl.next.ServeTCP(conn)
+}
+
+// reserve reserves a token in the bucket of the given source, and returns the delay after which it is available.
+// When the delay exceeds the maximum delay, no token is reserved.
+func (rl *rateLimiter) reserve(source string) (time.Duration, error) {
+	var bucket *rate.Limiter
+	if rlSource, exists := rl.buckets.Get(source); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(rl.rate, rl.burst)
+	}
+
+	// We Set even in the case where the source already exists,
+	// because we want to update the expiryTime everytime we get the source,
+	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
+	if err := rl.buckets.Set(source, bucket, rl.ttl); err != nil {
+		return 0, fmt.Errorf("could not insert/update bucket: %w", err)
+	}
+
+	now := time.Now()
+
+	res := bucket.ReserveN(now, 1)
+	if !res.OK() {
+		return 0, errors.New("no bursty traffic allowed")
+	}
+
+	delay := res.DelayFrom(now)
+	if delay > rl.maxDelay {
+		res.CancelAt(now)
+		return 0, fmt.Errorf("rate limit exceeded, retry in %s", delay)
+	}
+
+	return delay, nil
+}
+
+func (rl *rateLimiter) countRejection() {
+	if rl.rejectionsCounter != nil {
+		rl.rejectionsCounter.Add(1)
+	}
+}
@@ -0,0 +1,201 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPRateLimit
+		expectedRate  float64
+		expectedBurst int
+		expectedError bool
+	}{
+		{
+			desc:          "default period and burst",
+			config:        dynamic.TCPRateLimit{Average: 10},
+			expectedRate:  10,
+			expectedBurst: 1,
+		},
+		{
+			desc:          "custom period",
+			config:        dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(2 * time.Second), Burst: 5},
+			expectedRate:  0.5,
+			expectedBurst: 5,
+		},
+		{
+			desc:          "zero average",
+			config:        dynamic.TCPRateLimit{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative period",
+			config:        dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(-time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:          "negative max delay",
+			config:        dynamic.TCPRateLimit{Average: 1, MaxDelay: ptypes.Duration(-time.Second)},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(context.Background(), nil, test.config, nil, "foo")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			rl, ok := handler.(*rateLimiter)
+			require.True(t, ok)
+
+			assert.InDelta(t, test.expectedRate, float64(rl.rate), 1e-9)
+			assert.Equal(t, test.expectedBurst, rl.burst)
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	var served int
+	var mu sync.Mutex
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		mu.Lock()
+		served++
+		mu.Unlock()
+	})
+
+	counter := &fakeCounter{}
+
+	middleware, err := New(context.Background(), next, dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(time.Hour), Burst: 2}, counter, "foo")
+	require.NoError(t, err)
+
+	// The first two connections are within the burst.
+	for range 2 {
+		conn := newFakeConn("127.0.0.1:9000")
+		middleware.ServeTCP(conn)
+		assert.False(t, conn.isClosed())
+	}
+
+	// The third connection from the same IP exceeds the rate limit.
+	conn := newFakeConn("127.0.0.1:9001")
+	middleware.ServeTCP(conn)
+	assert.True(t, conn.isClosed())
+
+	// A connection from another IP has its own bucket.
+	conn = newFakeConn("127.0.0.2:9000")
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.isClosed())
+
+	assert.Equal(t, 3, served)
+	assert.InDelta(t, 1, counter.value, 0)
+	assert.Equal(t, []string{"middleware", "foo"}, counter.labels)
+}
+
+func TestRateLimiter_ServeTCP_maxDelay(t *testing.T) {
+	served := make(chan struct{}, 2)
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served <- struct{}{}
+	})
+
+	config := dynamic.TCPRateLimit{
+		Average:  10,
+		Burst:    1,
+		MaxDelay: ptypes.Duration(time.Second),
+	}
+
+	middleware, err := New(context.Background(), next, config, nil, "foo")
+	require.NoError(t, err)
+
+	conn := newFakeConn("127.0.0.1:9000")
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.isClosed())
+
+	// The second connection is delayed until a token is available, instead of being closed.
+	start := time.Now()
+	conn = newFakeConn("127.0.0.1:9000")
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.isClosed())
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	assert.Len(t, served, 2)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	addr string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newFakeConn(addr string) *fakeConn {
+	return &fakeConn{addr: addr}
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return fakeAddr{addr: c.addr}
+}
+
+func (c *fakeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	panic("implement me")
+}
+
+func (c *fakeConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+type fakeAddr struct {
+	addr string
+}
+
+func (a fakeAddr) Network() string {
+	return "tcp"
+}
+
+func (a fakeAddr) String() string {
+	return a.addr
+}
+
+type fakeCounter struct {
+	labels []string
+	value  float64
+}
+
+func (c *fakeCounter) With(labelValues ...string) metrics.Counter {
+	c.labels = append(c.labels, labelValues...)
+	return c
+}
+
+func (c *fakeCounter) Add(delta float64) {
+	c.value += delta
+}
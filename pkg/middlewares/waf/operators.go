@@ -0,0 +1,230 @@
+package waf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/ip"
+)
+
+// operator matches a value of the transaction.
+type operator func(t *transaction, value string) bool
+
+// parseOperator parses the operator of a rule, e.g. "@rx ^foo" or "!@pm foo bar".
+// Without operator name, the value is a regular expression.
+// The files read by the operators, e.g. @pmFromFile, are read with readFile.
+func parseOperator(value string, readFile func(name string) ([]byte, error)) (operator, error) {
+	negated := false
+	if strings.HasPrefix(value, "!") {
+		negated = true
+		value = value[1:]
+	}
+
+	name, param := "rx", value
+	if strings.HasPrefix(value, "@") {
+		name, param, _ = strings.Cut(value[1:], " ")
+		param = strings.TrimSpace(param)
+	}
+
+	op, err := newOperator(name, param, readFile)
+	if errors.As(err, &unsupportedError{}) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("operator @%s: %w", name, err)
+	}
+
+	if negated {
+		return func(t *transaction, value string) bool {
+			return !op(t, value)
+		}, nil
+	}
+
+	return op, nil
+}
+
+func newOperator(name, param string, readFile func(name string) ([]byte, error)) (operator, error) {
+	switch strings.ToLower(name) {
+	case "rx":
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(t *transaction, value string) bool {
+			if !t.capturing {
+				return re.MatchString(value)
+			}
+
+			matches := re.FindStringSubmatch(value)
+			if matches == nil {
+				return false
+			}
+
+			t.setCaptures(matches)
+
+			return true
+		}, nil
+
+	case "pm":
+		return phraseMatch(strings.Fields(param)), nil
+
+	case "pmfromfile", "pmf":
+		var phrases []string
+		for _, file := range strings.Fields(param) {
+			content, err := readFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			filePhrases, err := parsePhrases(content)
+			if err != nil {
+				return nil, err
+			}
+
+			phrases = append(phrases, filePhrases...)
+		}
+
+		return phraseMatch(phrases), nil
+
+	case "contains":
+		return stringOperator(param, strings.Contains), nil
+
+	case "containsword":
+		return stringOperator(param, containsWord), nil
+
+	case "beginswith":
+		return stringOperator(param, strings.HasPrefix), nil
+
+	case "endswith":
+		return stringOperator(param, strings.HasSuffix), nil
+
+	case "streq":
+		return stringOperator(param, func(value, param string) bool { return value == param }), nil
+
+	case "within":
+		return stringOperator(param, func(value, param string) bool { return strings.Contains(param, value) }), nil
+
+	case "eq":
+		return numericOperator(param, func(value, param int) bool { return value == param }), nil
+
+	case "ge":
+		return numericOperator(param, func(value, param int) bool { return value >= param }), nil
+
+	case "gt":
+		return numericOperator(param, func(value, param int) bool { return value > param }), nil
+
+	case "le":
+		return numericOperator(param, func(value, param int) bool { return value <= param }), nil
+
+	case "lt":
+		return numericOperator(param, func(value, param int) bool { return value < param }), nil
+
+	case "ipmatch":
+		checker, err := ip.NewChecker(strings.Split(strings.ReplaceAll(param, " ", ""), ","))
+		if err != nil {
+			return nil, err
+		}
+
+		return func(_ *transaction, value string) bool {
+			contains, err := checker.Contains(value)
+			return err == nil && contains
+		}, nil
+
+	case "unconditionalmatch":
+		return unconditionalMatch(), nil
+
+	case "nomatch":
+		return func(*transaction, string) bool { return false }, nil
+
+	default:
+		return nil, unsupportedError{name: fmt.Sprintf("operator %q", "@"+name)}
+	}
+}
+
+func unconditionalMatch() operator {
+	return func(*transaction, string) bool { return true }
+}
+
+// phraseMatch returns an operator matching the values containing one of the given phrases, case-insensitively.
+func phraseMatch(phrases []string) operator {
+	lower := make([]string, 0, len(phrases))
+	for _, phrase := range phrases {
+		lower = append(lower, strings.ToLower(phrase))
+	}
+
+	return func(_ *transaction, value string) bool {
+		value = strings.ToLower(value)
+		for _, phrase := range lower {
+			if strings.Contains(value, phrase) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// stringOperator returns an operator comparing the values with the parameter, in which the macros are expanded.
+func stringOperator(param string, compare func(value, param string) bool) operator {
+	return func(t *transaction, value string) bool {
+		return compare(value, t.expand(param))
+	}
+}
+
+// numericOperator returns an operator comparing the values with the parameter, as integers.
+// The values which are not integers are considered as being 0.
+func numericOperator(param string, compare func(value, param int) bool) operator {
+	return func(t *transaction, value string) bool {
+		v, _ := strconv.Atoi(strings.TrimSpace(value))
+		p, _ := strconv.Atoi(strings.TrimSpace(t.expand(param)))
+
+		return compare(v, p)
+	}
+}
+
+func containsWord(value, word string) bool {
+	if word == "" {
+		return true
+	}
+
+	for i := 0; ; {
+		j := strings.Index(value[i:], word)
+		if j < 0 {
+			return false
+		}
+
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isWordChar(value[start-1])) && (end == len(value) || !isWordChar(value[end])) {
+			return true
+		}
+
+		i = start + 1
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// parsePhrases returns the phrases of the given file content, one per line, without comments.
+func parsePhrases(content []byte) ([]string, error) {
+	var phrases []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		phrases = append(phrases, line)
+	}
+
+	return phrases, scanner.Err()
+}
@@ -0,0 +1,270 @@
+package waf
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rule is a rule defined by a SecRule or SecAction directive, or a marker defined by a SecMarker directive.
+type rule struct {
+	id     int
+	phase  int
+	marker string
+
+	variables       []variable
+	operator        operator
+	transformations []transformation
+
+	disruptive disruptiveAction
+	status     int
+	msg        string
+	logData    string
+	noLog      bool
+	capture    bool
+	setVars    []setVar
+	skipAfter  string
+
+	// chain is whether the rule is chained to the next rule, which is then stored in chained.
+	chain   bool
+	chained *rule
+
+	// unsupported is the operator or action of the rule which is not supported, if any.
+	// The rule is then a placeholder, which must be removed by a SecRuleRemoveById directive.
+	unsupported error
+}
+
+// unsupportedError reports an operator or an action which is not supported.
+type unsupportedError struct {
+	name string
+}
+
+func (e unsupportedError) Error() string {
+	return "unsupported " + e.name
+}
+
+// variable selects values of the transaction, e.g. ARGS:id or !REQUEST_HEADERS:Referer.
+type variable struct {
+	name     string
+	key      string
+	keyRegex *regexp.Regexp
+	exclude  bool
+	count    bool
+}
+
+// matches returns whether the given key is selected by the variable.
+func (v variable) matches(key string) bool {
+	switch {
+	case v.keyRegex != nil:
+		return v.keyRegex.MatchString(key)
+	case v.key != "":
+		return strings.EqualFold(v.key, key)
+	default:
+		return true
+	}
+}
+
+// setVar is a setvar action, which sets, increments, decrements, or deletes a transaction variable.
+type setVar struct {
+	key    string
+	op     byte
+	value  string
+	delete bool
+}
+
+func parseVariables(value string) ([]variable, error) {
+	var variables []variable
+
+	for _, part := range strings.Split(value, "|") {
+		part = strings.TrimSpace(part)
+
+		var v variable
+		switch {
+		case strings.HasPrefix(part, "!"):
+			v.exclude = true
+			part = part[1:]
+		case strings.HasPrefix(part, "&"):
+			v.count = true
+			part = part[1:]
+		}
+
+		name, key, _ := strings.Cut(part, ":")
+		v.name = strings.ToUpper(name)
+
+		if _, ok := scalars[v.name]; ok {
+			if key != "" {
+				return nil, fmt.Errorf("variable %s is not a collection", v.name)
+			}
+		} else if _, ok := collections[v.name]; !ok {
+			return nil, fmt.Errorf("unsupported variable %q", name)
+		}
+
+		key = strings.Trim(key, "'")
+		if len(key) > 1 && strings.HasPrefix(key, "/") && strings.HasSuffix(key, "/") {
+			var err error
+			v.keyRegex, err = regexp.Compile("(?i)" + key[1:len(key)-1])
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: compiling key regex: %w", v.name, err)
+			}
+		} else {
+			v.key = key
+		}
+
+		if v.exclude && v.key == "" && v.keyRegex == nil {
+			return nil, fmt.Errorf("variable %s: an exclusion must define a key", v.name)
+		}
+
+		variables = append(variables, v)
+	}
+
+	return variables, nil
+}
+
+// parseActions parses the comma separated list of actions of a rule.
+func (r *rule) parseActions(value, dir string) error {
+	for _, action := range splitActions(value) {
+		name, arg, _ := strings.Cut(action, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		arg = strings.Trim(strings.TrimSpace(arg), "'")
+
+		var err error
+		switch name {
+		case "id":
+			r.id, err = strconv.Atoi(arg)
+		case "phase":
+			r.phase, err = parsePhase(arg)
+		case "deny", "drop":
+			r.disruptive = actionDeny
+		case "block":
+			r.disruptive = actionDefault
+		case "pass":
+			r.disruptive = actionPass
+		case "allow":
+			r.disruptive = actionAllow
+		case "status":
+			r.status, err = strconv.Atoi(arg)
+			if err == nil && (r.status < 100 || r.status > 999) {
+				err = errors.New("invalid status code")
+			}
+		case "msg":
+			r.msg = arg
+		case "logdata":
+			r.logData = arg
+		case "log":
+			r.noLog = false
+		case "nolog":
+			r.noLog = true
+		case "capture":
+			r.capture = true
+		case "chain":
+			r.chain = true
+		case "skipafter":
+			r.skipAfter = arg
+		case "t":
+			if strings.EqualFold(arg, "none") {
+				r.transformations = nil
+				continue
+			}
+
+			var t transformation
+			t, err = getTransformation(arg)
+			r.transformations = append(r.transformations, t)
+		case "setvar":
+			var sv setVar
+			sv, err = parseSetVar(arg)
+			r.setVars = append(r.setVars, sv)
+		case "auditlog", "noauditlog", "tag", "severity", "rev", "ver", "maturity", "accuracy":
+			// These actions only describe the rule, or configure the ModSecurity audit log.
+		default:
+			if r.unsupported == nil {
+				r.unsupported = unsupportedError{name: fmt.Sprintf("action %q", name)}
+			}
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("action %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func parsePhase(value string) (int, error) {
+	switch strings.ToLower(value) {
+	case "request":
+		return 2, nil
+	case "response":
+		return 4, nil
+	case "logging":
+		return 5, nil
+	default:
+		return strconv.Atoi(value)
+	}
+}
+
+// parseSetVar parses a setvar action, e.g. tx.score=+5, or !tx.score.
+func parseSetVar(value string) (setVar, error) {
+	var sv setVar
+
+	if strings.HasPrefix(value, "!") {
+		sv.delete = true
+		value = value[1:]
+	}
+
+	key, val, hasValue := strings.Cut(value, "=")
+
+	collection, name, ok := strings.Cut(key, ".")
+	if !ok || !strings.EqualFold(collection, "tx") || name == "" {
+		return setVar{}, fmt.Errorf("unsupported variable %q, only the TX collection can be set", key)
+	}
+	sv.key = strings.ToLower(name)
+
+	if strings.HasPrefix(val, "+") || strings.HasPrefix(val, "-") {
+		sv.op = val[0]
+		val = val[1:]
+	}
+
+	if !hasValue && !sv.delete {
+		val = "1"
+	}
+	sv.value = val
+
+	return sv, nil
+}
+
+// splitActions splits a comma separated list of actions, ignoring the commas enclosed in single quotes.
+func splitActions(value string) []string {
+	var actions []string
+
+	var current strings.Builder
+	quoted := false
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == '\\' && quoted && i+1 < len(value) && value[i+1] == '\'':
+			current.WriteByte('\'')
+			i++
+		case c == '\'':
+			quoted = !quoted
+			current.WriteByte(c)
+		case c == ',' && !quoted:
+			if s := strings.TrimSpace(current.String()); s != "" {
+				actions = append(actions, s)
+			}
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if s := strings.TrimSpace(current.String()); s != "" {
+		actions = append(actions, s)
+	}
+
+	return actions
+}
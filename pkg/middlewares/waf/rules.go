@@ -0,0 +1,464 @@
+package waf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// engineMode is the mode set by the SecRuleEngine directive.
+type engineMode int
+
+const (
+	engineOn engineMode = iota
+	engineDetectionOnly
+	engineOff
+)
+
+// disruptiveAction is the action applied to a request, when a rule matches.
+type disruptiveAction int
+
+const (
+	// actionDefault applies the disruptive action of the SecDefaultAction directive of the rule phase.
+	actionDefault disruptiveAction = iota
+	actionPass
+	actionDeny
+	actionAllow
+)
+
+// defaultAction holds the actions inherited by the rules of a phase, as defined by the SecDefaultAction directive.
+type defaultAction struct {
+	disruptive disruptiveAction
+	status     int
+}
+
+// ruleSet is a set of rules parsed from SecLang directives.
+type ruleSet struct {
+	engine         engineMode
+	bodyAccess     bool
+	defaultActions map[int]defaultAction
+	rules          []*rule
+}
+
+func newRuleSet() *ruleSet {
+	return &ruleSet{
+		bodyAccess: true,
+		defaultActions: map[int]defaultAction{
+			1: {disruptive: actionPass, status: 403},
+			2: {disruptive: actionPass, status: 403},
+		},
+	}
+}
+
+// hasPhase returns whether at least one rule is evaluated during the given phase.
+func (s *ruleSet) hasPhase(phase int) bool {
+	for _, r := range s.rules {
+		if r.marker == "" && r.phase == phase {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRules parses the directives of the given files, and then the given inline directives.
+// When noLocalFiles is true, the files read by the operators are looked up in dataFiles, by name,
+// instead of the local filesystem.
+func parseRules(files []string, rules string, noLocalFiles bool, dataFiles map[string]string) (*ruleSet, error) {
+	if noLocalFiles && len(files) > 0 {
+		return nil, errors.New("rules files cannot be read from the local filesystem")
+	}
+
+	p := &parser{set: newRuleSet(), ids: make(map[int]struct{}), noLocalFiles: noLocalFiles, dataFiles: dataFiles}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading rules file: %w", err)
+		}
+
+		if err := p.parse(string(content), filepath.Dir(file)); err != nil {
+			return nil, fmt.Errorf("parsing rules file %s: %w", file, err)
+		}
+	}
+
+	if err := p.parse(rules, "."); err != nil {
+		return nil, fmt.Errorf("parsing rules: %w", err)
+	}
+
+	if p.chain != nil {
+		return nil, fmt.Errorf("rule %d: chained rule is missing", p.chainStart.id)
+	}
+
+	p.removeRules()
+
+	// The rules using an unsupported operator or action are only rejected if they have not been removed.
+	for _, r := range p.set.rules {
+		for c := r; c != nil; c = c.chained {
+			if c.unsupported != nil {
+				return nil, fmt.Errorf("rule %d: %w", r.id, c.unsupported)
+			}
+		}
+	}
+
+	return p.set, nil
+}
+
+type parser struct {
+	set *ruleSet
+	ids map[int]struct{}
+
+	// chainStart is the first rule of the chain being parsed, and chain is its last rule.
+	chainStart *rule
+	chain      *rule
+
+	removedIDs []idRange
+
+	noLocalFiles bool
+	dataFiles    map[string]string
+}
+
+type idRange struct {
+	from, to int
+}
+
+// parse parses the given directives, the relative paths being resolved from the given directory.
+func (p *parser) parse(content, dir string) error {
+	for i, line := range directiveLines(content) {
+		if err := p.parseDirective(line, dir); err != nil {
+			return fmt.Errorf("directive %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// readFile returns the content of the file read by an operator,
+// whose relative path is resolved from the given directory.
+func (p *parser) readFile(name, dir string) ([]byte, error) {
+	if !p.noLocalFiles {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+
+		return os.ReadFile(name)
+	}
+
+	content, ok := p.dataFiles[name]
+	if !ok {
+		return nil, fmt.Errorf("data file %q not found, as the files of the local filesystem cannot be read", name)
+	}
+
+	return []byte(content), nil
+}
+
+// directiveLines returns the directives of the given content, without comments,
+// and with the lines ending with a backslash joined to the next one.
+func directiveLines(content string) []string {
+	var lines []string
+
+	var current strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if current.Len() == 0 && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+
+		if continued, ok := strings.CutSuffix(line, `\`); ok {
+			current.WriteString(continued)
+			current.WriteString(" ")
+			continue
+		}
+
+		current.WriteString(line)
+		lines = append(lines, current.String())
+		current.Reset()
+	}
+
+	if current.Len() > 0 {
+		lines = append(lines, current.String())
+	}
+
+	return lines
+}
+
+func (p *parser) parseDirective(line, dir string) error {
+	args, err := splitArguments(line)
+	if err != nil {
+		return err
+	}
+
+	name, args := args[0], args[1:]
+
+	if p.chain != nil && !strings.EqualFold(name, "SecRule") {
+		return fmt.Errorf("rule %d: chained rule is missing", p.chainStart.id)
+	}
+
+	switch strings.ToLower(name) {
+	case "secruleengine":
+		if len(args) != 1 {
+			return fmt.Errorf("%s: expected 1 argument, got %d", name, len(args))
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "on":
+			p.set.engine = engineOn
+		case "detectiononly":
+			p.set.engine = engineDetectionOnly
+		case "off":
+			p.set.engine = engineOff
+		default:
+			return fmt.Errorf("%s: invalid value %q", name, args[0])
+		}
+
+	case "secrequestbodyaccess":
+		if len(args) != 1 {
+			return fmt.Errorf("%s: expected 1 argument, got %d", name, len(args))
+		}
+
+		switch strings.ToLower(args[0]) {
+		case "on":
+			p.set.bodyAccess = true
+		case "off":
+			p.set.bodyAccess = false
+		default:
+			return fmt.Errorf("%s: invalid value %q", name, args[0])
+		}
+
+	case "secdefaultaction":
+		if len(args) != 1 {
+			return fmt.Errorf("%s: expected 1 argument, got %d", name, len(args))
+		}
+
+		r := &rule{phase: 2}
+		if err := r.parseActions(args[0], dir); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if r.unsupported != nil {
+			return fmt.Errorf("%s: %w", name, r.unsupported)
+		}
+
+		action := defaultAction{disruptive: r.disruptive, status: r.status}
+		if action.disruptive == actionDefault {
+			action.disruptive = actionPass
+		}
+		if action.status == 0 {
+			action.status = 403
+		}
+
+		p.set.defaultActions[r.phase] = action
+
+	case "secrule":
+		if len(args) != 2 && len(args) != 3 {
+			return fmt.Errorf("%s: expected 2 or 3 arguments, got %d", name, len(args))
+		}
+
+		r := &rule{phase: 2}
+
+		r.variables, err = parseVariables(args[0])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		var unsupported unsupportedError
+		r.operator, err = parseOperator(args[1], func(name string) ([]byte, error) {
+			return p.readFile(name, dir)
+		})
+		if errors.As(err, &unsupported) {
+			// The rule is kept as a placeholder, as it may be removed by a SecRuleRemoveById directive.
+			r.unsupported = unsupported
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if len(args) == 3 {
+			if err := r.parseActions(args[2], dir); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		return p.addRule(r)
+
+	case "secaction":
+		if len(args) != 1 {
+			return fmt.Errorf("%s: expected 1 argument, got %d", name, len(args))
+		}
+
+		r := &rule{phase: 2, operator: unconditionalMatch()}
+		if err := r.parseActions(args[0], dir); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		return p.addRule(r)
+
+	case "secmarker":
+		if len(args) != 1 {
+			return fmt.Errorf("%s: expected 1 argument, got %d", name, len(args))
+		}
+
+		p.set.rules = append(p.set.rules, &rule{marker: args[0]})
+
+	case "secruleremovebyid":
+		if len(args) == 0 {
+			return fmt.Errorf("%s: expected at least 1 argument", name)
+		}
+
+		for _, arg := range args {
+			ids, err := parseIDRange(arg)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			p.removedIDs = append(p.removedIDs, ids)
+		}
+
+	case "seccomponentsignature":
+		// The signature only identifies the rule set in the ModSecurity audit log.
+
+	default:
+		return fmt.Errorf("unsupported directive %q", name)
+	}
+
+	return nil
+}
+
+func (p *parser) addRule(r *rule) error {
+	if p.chain != nil {
+		if r.id != 0 || r.disruptive != actionDefault {
+			return fmt.Errorf("rule %d: chained rules cannot define an id or a disruptive action", p.chainStart.id)
+		}
+
+		p.chain.chained = r
+		p.chain = nil
+
+		if r.chain {
+			p.chain = r
+		} else {
+			p.chainStart = nil
+		}
+
+		return nil
+	}
+
+	if r.id <= 0 {
+		return errors.New("rule id must be defined and positive")
+	}
+
+	if _, ok := p.ids[r.id]; ok {
+		return fmt.Errorf("duplicate rule id %d", r.id)
+	}
+	p.ids[r.id] = struct{}{}
+
+	if r.phase != 1 && r.phase != 2 {
+		return fmt.Errorf("rule %d: unsupported phase %d, only the request phases (1 and 2) are supported", r.id, r.phase)
+	}
+
+	p.set.rules = append(p.set.rules, r)
+
+	if r.chain {
+		p.chainStart = r
+		p.chain = r
+	}
+
+	return nil
+}
+
+// removeRules removes the rules targeted by the SecRuleRemoveById directives.
+func (p *parser) removeRules() {
+	if len(p.removedIDs) == 0 {
+		return
+	}
+
+	var rules []*rule
+	for _, r := range p.set.rules {
+		removed := false
+		for _, ids := range p.removedIDs {
+			if r.marker == "" && r.id >= ids.from && r.id <= ids.to {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			rules = append(rules, r)
+		}
+	}
+
+	p.set.rules = rules
+}
+
+func parseIDRange(value string) (idRange, error) {
+	from, to, isRange := strings.Cut(value, "-")
+
+	fromID, err := strconv.Atoi(from)
+	if err != nil {
+		return idRange{}, fmt.Errorf("invalid rule id %q", value)
+	}
+
+	if !isRange {
+		return idRange{from: fromID, to: fromID}, nil
+	}
+
+	toID, err := strconv.Atoi(to)
+	if err != nil || toID < fromID {
+		return idRange{}, fmt.Errorf("invalid rule id range %q", value)
+	}
+
+	return idRange{from: fromID, to: toID}, nil
+}
+
+// splitArguments splits a directive into its name and arguments,
+// which are separated by spaces, unless they are enclosed in double quotes.
+func splitArguments(line string) ([]string, error) {
+	var args []string
+
+	var current strings.Builder
+	inArg, quoted := false, false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case quoted && c == '\\' && i+1 < len(line) && line[i+1] == '"':
+			current.WriteByte('"')
+			i++
+
+		case quoted && c == '"':
+			args = append(args, current.String())
+			current.Reset()
+			inArg, quoted = false, false
+
+		case quoted:
+			current.WriteByte(c)
+
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+
+		case c == '"' && !inArg:
+			inArg, quoted = true, true
+
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quoted argument")
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
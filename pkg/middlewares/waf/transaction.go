@@ -0,0 +1,477 @@
+package waf
+
+import (
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var macroRegexp = regexp.MustCompile(`%\{([^}]+)}`)
+
+// keyValue is a value of a collection.
+type keyValue struct {
+	key, value string
+}
+
+// matchedValue is a value selected by a variable, and named after it, e.g. ARGS:id.
+type matchedValue struct {
+	name, value string
+}
+
+// matchedRule is a rule matched by a request.
+type matchedRule struct {
+	id      int
+	msg     string
+	logData string
+}
+
+// interruption is the disruptive action applied to a request.
+type interruption struct {
+	ruleID int
+	status int
+}
+
+// collections are the variables holding several values, which can be selected by key.
+var collections = map[string]func(t *transaction) []keyValue{
+	"ARGS":                  (*transaction).args,
+	"ARGS_NAMES":            func(t *transaction) []keyValue { return names(t.args()) },
+	"ARGS_GET":              func(t *transaction) []keyValue { return t.argsGet },
+	"ARGS_GET_NAMES":        func(t *transaction) []keyValue { return names(t.argsGet) },
+	"ARGS_POST":             func(t *transaction) []keyValue { return t.argsPost },
+	"ARGS_POST_NAMES":       func(t *transaction) []keyValue { return names(t.argsPost) },
+	"REQUEST_HEADERS":       func(t *transaction) []keyValue { return t.headers },
+	"REQUEST_HEADERS_NAMES": func(t *transaction) []keyValue { return names(t.headers) },
+	"REQUEST_COOKIES":       func(t *transaction) []keyValue { return t.cookies },
+	"REQUEST_COOKIES_NAMES": func(t *transaction) []keyValue { return names(t.cookies) },
+	"TX":                    (*transaction).txValues,
+	"MATCHED_VARS":          (*transaction).matchedValues,
+	"MATCHED_VARS_NAMES":    func(t *transaction) []keyValue { return names(t.matchedValues()) },
+}
+
+// scalars are the variables holding a single value.
+var scalars = map[string]func(t *transaction) string{
+	"REQUEST_URI":       (*transaction).requestURI,
+	"REQUEST_URI_RAW":   (*transaction).requestURI,
+	"REQUEST_FILENAME":  func(t *transaction) string { return t.req.URL.Path },
+	"REQUEST_BASENAME":  func(t *transaction) string { return path.Base(t.req.URL.Path) },
+	"REQUEST_LINE":      func(t *transaction) string { return t.req.Method + " " + t.requestURI() + " " + t.req.Proto },
+	"REQUEST_METHOD":    func(t *transaction) string { return t.req.Method },
+	"REQUEST_PROTOCOL":  func(t *transaction) string { return t.req.Proto },
+	"QUERY_STRING":      func(t *transaction) string { return t.req.URL.RawQuery },
+	"REQUEST_BODY":      func(t *transaction) string { return t.body },
+	"REMOTE_ADDR":       (*transaction).remoteAddr,
+	"MATCHED_VAR":       func(t *transaction) string { return t.lastMatched().value },
+	"MATCHED_VAR_NAME":  func(t *transaction) string { return t.lastMatched().name },
+	"REQUEST_BODY_SIZE": func(t *transaction) string { return strconv.Itoa(len(t.body)) },
+}
+
+// transaction holds the state of the evaluation of a rule set for a request.
+type transaction struct {
+	set           *ruleSet
+	req           *http.Request
+	detectionOnly bool
+
+	argsGet  []keyValue
+	argsPost []keyValue
+	headers  []keyValue
+	cookies  []keyValue
+	body     string
+
+	tx        map[string]string
+	matched   []matchedValue
+	capturing bool
+
+	matchedRules []matchedRule
+	interruption *interruption
+	allowed      bool
+}
+
+func newTransaction(set *ruleSet, req *http.Request, detectionOnly bool) *transaction {
+	t := &transaction{
+		set:           set,
+		req:           req,
+		detectionOnly: detectionOnly,
+		argsGet:       parseQuery(req.URL.RawQuery),
+		tx:            make(map[string]string),
+	}
+
+	if req.Host != "" {
+		t.headers = append(t.headers, keyValue{key: "Host", value: req.Host})
+	}
+
+	headerNames := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	for _, name := range headerNames {
+		for _, value := range req.Header[name] {
+			t.headers = append(t.headers, keyValue{key: name, value: value})
+		}
+	}
+
+	for _, cookie := range req.Cookies() {
+		t.cookies = append(t.cookies, keyValue{key: cookie.Name, value: cookie.Value})
+	}
+
+	return t
+}
+
+// setBody sets the request body, and the arguments it contains according to its content type.
+func (t *transaction) setBody(body []byte) {
+	t.body = string(body)
+
+	mediaType, _, err := mime.ParseMediaType(t.req.Header.Get("Content-Type"))
+	if err != nil {
+		return
+	}
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		t.argsPost = parseQuery(t.body)
+
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return
+		}
+
+		t.argsPost = flattenJSON("json", data, nil)
+	}
+}
+
+// evaluate evaluates the rules of the given phase, until a disruptive action stops the evaluation.
+func (t *transaction) evaluate(phase int) {
+	if t.allowed || (t.interruption != nil && !t.detectionOnly) {
+		return
+	}
+
+	skipTo := ""
+
+	for _, r := range t.set.rules {
+		if skipTo != "" {
+			if r.marker == skipTo {
+				skipTo = ""
+			}
+			continue
+		}
+
+		if r.marker != "" || r.phase != phase || !t.match(r) {
+			continue
+		}
+
+		for chained := r; chained != nil; chained = chained.chained {
+			t.setVars(chained)
+		}
+
+		if !r.noLog {
+			t.matchedRules = append(t.matchedRules, matchedRule{
+				id:      r.id,
+				msg:     t.expand(r.msg),
+				logData: t.expand(r.logData),
+			})
+		}
+
+		defaults := t.set.defaultActions[phase]
+
+		disruptive := r.disruptive
+		if disruptive == actionDefault {
+			disruptive = defaults.disruptive
+		}
+
+		status := r.status
+		if status == 0 {
+			status = defaults.status
+		}
+
+		switch disruptive {
+		case actionDeny:
+			if t.interruption == nil {
+				t.interruption = &interruption{ruleID: r.id, status: status}
+			}
+
+			if !t.detectionOnly {
+				return
+			}
+
+		case actionAllow:
+			t.allowed = true
+			return
+		}
+
+		if r.skipAfter != "" {
+			skipTo = r.skipAfter
+		}
+	}
+}
+
+// match returns whether the rule, and the rules chained to it, match the request.
+func (t *transaction) match(r *rule) bool {
+	t.capturing = r.capture
+
+	var matched []matchedValue
+	if len(r.variables) == 0 {
+		if r.operator(t, "") {
+			matched = append(matched, matchedValue{})
+		}
+	} else {
+		for _, v := range t.values(r.variables) {
+			value := v.value
+			for _, transform := range r.transformations {
+				value = transform(value)
+			}
+
+			if r.operator(t, value) {
+				matched = append(matched, matchedValue{name: v.name, value: value})
+			}
+		}
+	}
+
+	t.capturing = false
+
+	if len(matched) == 0 {
+		return false
+	}
+
+	t.matched = matched
+
+	return r.chained == nil || t.match(r.chained)
+}
+
+// values returns the values selected by the given variables.
+func (t *transaction) values(variables []variable) []matchedValue {
+	var values []matchedValue
+
+	for _, v := range variables {
+		if v.exclude {
+			continue
+		}
+
+		var selected []matchedValue
+		if get, ok := scalars[v.name]; ok {
+			if value := get(t); value != "" || !v.count {
+				selected = append(selected, matchedValue{name: v.name, value: value})
+			}
+		} else {
+			for _, kv := range collections[v.name](t) {
+				if v.matches(kv.key) && !excluded(variables, v.name, kv.key) {
+					selected = append(selected, matchedValue{name: v.name + ":" + kv.key, value: kv.value})
+				}
+			}
+		}
+
+		if v.count {
+			values = append(values, matchedValue{name: "&" + v.name, value: strconv.Itoa(len(selected))})
+			continue
+		}
+
+		values = append(values, selected...)
+	}
+
+	return values
+}
+
+func excluded(variables []variable, name, key string) bool {
+	for _, v := range variables {
+		if v.exclude && v.name == name && v.matches(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *transaction) setVars(r *rule) {
+	for _, sv := range r.setVars {
+		if sv.delete {
+			delete(t.tx, sv.key)
+			continue
+		}
+
+		value := t.expand(sv.value)
+
+		switch sv.op {
+		case '+', '-':
+			current, _ := strconv.Atoi(t.tx[sv.key])
+			delta, _ := strconv.Atoi(value)
+			if sv.op == '-' {
+				delta = -delta
+			}
+
+			t.tx[sv.key] = strconv.Itoa(current + delta)
+
+		default:
+			t.tx[sv.key] = value
+		}
+	}
+}
+
+func (t *transaction) setCaptures(matches []string) {
+	for i := range 10 {
+		key := strconv.Itoa(i)
+		if i < len(matches) {
+			t.tx[key] = matches[i]
+		} else {
+			delete(t.tx, key)
+		}
+	}
+}
+
+// expand replaces the macros of the given value, e.g. %{tx.score} or %{MATCHED_VAR}, by the values they refer to.
+func (t *transaction) expand(value string) string {
+	if !strings.Contains(value, "%{") {
+		return value
+	}
+
+	return macroRegexp.ReplaceAllStringFunc(value, func(macro string) string {
+		name, key, _ := strings.Cut(macro[2:len(macro)-1], ".")
+		name = strings.ToUpper(name)
+
+		if get, ok := scalars[name]; ok {
+			return get(t)
+		}
+
+		if get, ok := collections[name]; ok {
+			for _, kv := range get(t) {
+				if strings.EqualFold(kv.key, key) {
+					return kv.value
+				}
+			}
+		}
+
+		return ""
+	})
+}
+
+func (t *transaction) requestURI() string {
+	if t.req.RequestURI != "" {
+		return t.req.RequestURI
+	}
+
+	return t.req.URL.RequestURI()
+}
+
+func (t *transaction) remoteAddr() string {
+	host, _, err := net.SplitHostPort(t.req.RemoteAddr)
+	if err != nil {
+		return t.req.RemoteAddr
+	}
+
+	return host
+}
+
+// args returns the arguments of the query, followed by the ones of the body.
+func (t *transaction) args() []keyValue {
+	args := make([]keyValue, 0, len(t.argsGet)+len(t.argsPost))
+	args = append(args, t.argsGet...)
+
+	return append(args, t.argsPost...)
+}
+
+func (t *transaction) txValues() []keyValue {
+	keys := make([]string, 0, len(t.tx))
+	for key := range t.tx {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]keyValue, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, keyValue{key: key, value: t.tx[key]})
+	}
+
+	return values
+}
+
+func (t *transaction) matchedValues() []keyValue {
+	values := make([]keyValue, 0, len(t.matched))
+	for _, m := range t.matched {
+		values = append(values, keyValue{key: m.name, value: m.value})
+	}
+
+	return values
+}
+
+func (t *transaction) lastMatched() matchedValue {
+	if len(t.matched) == 0 {
+		return matchedValue{}
+	}
+
+	return t.matched[len(t.matched)-1]
+}
+
+// names returns the keys of the given values, as the values of a collection.
+func names(values []keyValue) []keyValue {
+	result := make([]keyValue, 0, len(values))
+	for _, kv := range values {
+		result = append(result, keyValue{key: kv.key, value: kv.key})
+	}
+
+	return result
+}
+
+// parseQuery parses URL-encoded arguments, keeping their order, and their raw value when it cannot be decoded.
+func parseQuery(query string) []keyValue {
+	var args []keyValue
+
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+		args = append(args, keyValue{key: unescape(key), value: unescape(value)})
+	}
+
+	return args
+}
+
+func unescape(value string) string {
+	unescaped, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+
+	return unescaped
+}
+
+// flattenJSON returns the values of the given JSON data, named after their path, e.g. json.user.name.
+func flattenJSON(prefix string, data interface{}, args []keyValue) []keyValue {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(d))
+		for key := range d {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			args = flattenJSON(prefix+"."+key, d[key], args)
+		}
+
+	case []interface{}:
+		for i, value := range d {
+			args = flattenJSON(prefix+"."+strconv.Itoa(i), value, args)
+		}
+
+	case string:
+		args = append(args, keyValue{key: prefix, value: d})
+
+	case nil:
+		args = append(args, keyValue{key: prefix, value: ""})
+
+	default:
+		encoded, _ := json.Marshal(d)
+		args = append(args, keyValue{key: prefix, value: string(encoded)})
+	}
+
+	return args
+}
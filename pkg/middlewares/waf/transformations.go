@@ -0,0 +1,132 @@
+package waf
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// transformation transforms a value before it is matched by the operator of a rule.
+type transformation func(value string) string
+
+var transformations = map[string]transformation{
+	"lowercase":          strings.ToLower,
+	"uppercase":          strings.ToUpper,
+	"urldecode":          urlDecode,
+	"urldecodeuni":       urlDecode,
+	"htmlentitydecode":   html.UnescapeString,
+	"compresswhitespace": compressWhitespace,
+	"removewhitespace":   removeWhitespace,
+	"removenulls":        func(value string) string { return strings.ReplaceAll(value, "\x00", "") },
+	"trim":               strings.TrimSpace,
+	"trimleft":           func(value string) string { return strings.TrimLeftFunc(value, unicode.IsSpace) },
+	"trimright":          func(value string) string { return strings.TrimRightFunc(value, unicode.IsSpace) },
+	"length":             func(value string) string { return strconv.Itoa(len(value)) },
+	"normalizepath":      normalizePath,
+	"normalisepath":      normalizePath,
+	"base64decode":       base64Decode,
+	"hexdecode":          hexDecode,
+}
+
+func getTransformation(name string) (transformation, error) {
+	t, ok := transformations[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported transformation %q", name)
+	}
+
+	return t, nil
+}
+
+// urlDecode decodes the percent-encoded characters, including the %uXXXX ones, and the plus signs.
+// The invalid encodings are kept as is.
+func urlDecode(value string) string {
+	if !strings.ContainsAny(value, "%+") {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '+':
+			b.WriteByte(' ')
+
+		case c == '%' && i+5 < len(value) && (value[i+1] == 'u' || value[i+1] == 'U'):
+			r, err := strconv.ParseUint(value[i+2:i+6], 16, 16)
+			if err != nil {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteRune(rune(r))
+			i += 5
+
+		case c == '%' && i+2 < len(value):
+			v, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+			if err != nil {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteByte(byte(v))
+			i += 2
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func compressWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func removeWhitespace(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+
+		return r
+	}, value)
+}
+
+func normalizePath(value string) string {
+	if value == "" {
+		return value
+	}
+
+	normalized := path.Clean(value)
+	if strings.HasSuffix(value, "/") && normalized != "/" {
+		normalized += "/"
+	}
+
+	return normalized
+}
+
+func base64Decode(value string) string {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return value
+		}
+	}
+
+	return string(decoded)
+}
+
+func hexDecode(value string) string {
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return value
+	}
+
+	return string(decoded)
+}
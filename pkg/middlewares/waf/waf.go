@@ -0,0 +1,177 @@
+package waf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const typeName = "WAF"
+
+// defaultMaxRequestBodyBytes is the default maximum size of a request body inspected by the rules.
+const defaultMaxRequestBodyBytes = 1024 * 1024
+
+// waf is a middleware evaluating SecLang rules on the requests.
+type waf struct {
+	next          http.Handler
+	name          string
+	rules         *ruleSet
+	detectionOnly bool
+	maxBodySize   int64
+}
+
+// New creates a new web application firewall middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.WAF, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	if config.Rules == "" && len(config.RulesFiles) == 0 {
+		return nil, errors.New("at least one rule or rules file must be defined")
+	}
+
+	if config.MaxRequestBodyBytes < 0 {
+		return nil, errors.New("maxRequestBodyBytes must be positive")
+	}
+
+	rules, err := parseRules(config.RulesFiles, config.Rules, config.NoLocalFiles, config.DataFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBodySize := config.MaxRequestBodyBytes
+	if maxBodySize == 0 {
+		maxBodySize = defaultMaxRequestBodyBytes
+	}
+
+	return &waf{
+		next:          next,
+		name:          name,
+		rules:         rules,
+		detectionOnly: config.DetectionOnly || rules.engine == engineDetectionOnly,
+		maxBodySize:   maxBodySize,
+	}, nil
+}
+
+func (w *waf) GetTracingInformation() (string, string, trace.SpanKind) {
+	return w.name, typeName, trace.SpanKindInternal
+}
+
+func (w *waf) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if w.rules.engine == engineOff {
+		w.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), w.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	t := newTransaction(w.rules, req, w.detectionOnly)
+	t.evaluate(1)
+
+	if (t.interruption == nil || w.detectionOnly) && !t.allowed && w.rules.hasPhase(2) {
+		if w.rules.bodyAccess && req.Body != nil && req.Body != http.NoBody {
+			body, err := io.ReadAll(io.LimitReader(req.Body, w.maxBodySize+1))
+			if err != nil {
+				logger.Debug().Err(err).Msg("Error while reading the request body")
+
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					reject(ctx, http.StatusRequestEntityTooLarge, rw)
+					return
+				}
+
+				reject(ctx, http.StatusBadRequest, rw)
+				return
+			}
+
+			if int64(len(body)) > w.maxBodySize {
+				if !w.detectionOnly {
+					logger.Debug().Msg("Rejecting request: the body is too large to be inspected")
+					tracing.SetStatusErrorf(req.Context(), "Request body too large to be inspected")
+
+					reject(ctx, http.StatusRequestEntityTooLarge, rw)
+					return
+				}
+
+				logger.Debug().Msg("The request body is too large to be inspected")
+
+				req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+			} else {
+				req.Body = readCloser{Reader: bytes.NewReader(body), Closer: req.Body}
+				t.setBody(body)
+			}
+		}
+
+		t.evaluate(2)
+	}
+
+	w.log(req, t)
+
+	if t.interruption != nil {
+		if w.detectionOnly {
+			logger.Debug().Msgf("Detected request which would have been blocked by rule %d", t.interruption.ruleID)
+		} else {
+			msg := fmt.Sprintf("Request blocked by rule %d", t.interruption.ruleID)
+			logger.Debug().Msg(msg)
+			tracing.SetStatusErrorf(req.Context(), msg)
+
+			reject(ctx, t.interruption.status, rw)
+			return
+		}
+	}
+
+	w.next.ServeHTTP(rw, req)
+}
+
+// log logs the matched rules, and adds their IDs to the access log fields.
+func (w *waf) log(req *http.Request, t *transaction) {
+	if len(t.matchedRules) == 0 {
+		return
+	}
+
+	logger := middlewares.GetLogger(req.Context(), w.name, typeName)
+
+	ids := make([]int, 0, len(t.matchedRules))
+	for _, r := range t.matchedRules {
+		event := logger.Debug()
+		if r.logData != "" {
+			event = event.Str("logData", r.logData)
+		}
+		event.Msgf("Rule %d matched: %s", r.id, r.msg)
+
+		ids = append(ids, r.id)
+	}
+
+	logData := accesslog.GetLogData(req)
+	if logData == nil {
+		return
+	}
+
+	if previous, ok := logData.Core[accesslog.WAFMatchedRules].([]int); ok {
+		ids = append(previous, ids...)
+	}
+
+	logData.Core[accesslog.WAFMatchedRules] = ids
+}
+
+func reject(ctx context.Context, statusCode int, rw http.ResponseWriter) {
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Send()
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,531 @@
+package waf
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+)
+
+const testRules = `
+SecRule REQUEST_HEADERS:User-Agent "@pm sqlmap nikto" \
+    "id:1001,phase:1,deny,status:403,msg:'Scanner detected'"
+
+SecRule ARGS "@rx (?i)union\s+select" "id:1002,phase:2,deny,t:urlDecode,msg:'SQL injection in %{MATCHED_VAR_NAME}'"
+
+SecRule REQUEST_FILENAME "@beginsWith /admin" "id:1003,phase:1,log,pass,msg:'Admin access'"
+
+SecRule REQUEST_METHOD "@streq DELETE" "id:1004,phase:1,deny,status:405"
+`
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.WAF
+		expErr string
+	}{
+		{
+			desc:   "no rules",
+			config: dynamic.WAF{},
+			expErr: "at least one rule or rules file must be defined",
+		},
+		{
+			desc:   "negative maxRequestBodyBytes",
+			config: dynamic.WAF{Rules: testRules, MaxRequestBodyBytes: -1},
+			expErr: "maxRequestBodyBytes must be positive",
+		},
+		{
+			desc:   "unsupported directive",
+			config: dynamic.WAF{Rules: `SecAuditLog /var/log/audit.log`},
+			expErr: `parsing rules: directive 1: unsupported directive "SecAuditLog"`,
+		},
+		{
+			desc:   "unsupported operator",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@detectSQLi" "id:1,deny"`},
+			expErr: `rule 1: unsupported operator "@detectSQLi"`,
+		},
+		{
+			desc: "unsupported operator in a chained rule",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "id:1,deny,chain"
+SecRule ARGS "@detectXSS" ""`},
+			expErr: `rule 1: unsupported operator "@detectXSS"`,
+		},
+
+		{
+			desc:   "unsupported variable",
+			config: dynamic.WAF{Rules: `SecRule RESPONSE_BODY "@contains foo" "id:1,deny"`},
+			expErr: `parsing rules: directive 1: SecRule: unsupported variable "RESPONSE_BODY"`,
+		},
+		{
+			desc:   "unsupported action",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "id:1,deny,ctl:ruleEngine=Off"`},
+			expErr: `rule 1: unsupported action "ctl"`,
+		},
+		{
+			desc:   "unsupported default action",
+			config: dynamic.WAF{Rules: `SecDefaultAction "phase:2,deny,ctl:ruleEngine=Off"`},
+			expErr: `parsing rules: directive 1: SecDefaultAction: unsupported action "ctl"`,
+		},
+		{
+			desc:   "unsupported phase",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "id:1,phase:4,deny"`},
+			expErr: "parsing rules: directive 1: rule 1: unsupported phase 4, only the request phases (1 and 2) are supported",
+		},
+		{
+			desc:   "missing id",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "phase:1,deny"`},
+			expErr: "parsing rules: directive 1: rule id must be defined and positive",
+		},
+		{
+			desc: "duplicate id",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "id:1,deny"
+SecRule ARGS "@contains bar" "id:1,deny"`},
+			expErr: "parsing rules: directive 2: duplicate rule id 1",
+		},
+		{
+			desc:   "missing chained rule",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@contains foo" "id:1,deny,chain"`},
+			expErr: "rule 1: chained rule is missing",
+		},
+		{
+			desc:   "invalid regex",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@rx (" "id:1,deny"`},
+			expErr: "parsing rules: directive 1: SecRule: operator @rx: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			desc:   "unterminated quote",
+			config: dynamic.WAF{Rules: `SecRule ARGS "@rx foo`},
+			expErr: "parsing rules: directive 1: unterminated quoted argument",
+		},
+		{
+			desc:   "missing rules file",
+			config: dynamic.WAF{RulesFiles: []string{"/does/not/exist.conf"}},
+			expErr: "reading rules file: open /does/not/exist.conf: no such file or directory",
+		},
+		{
+			desc:   "valid rules",
+			config: dynamic.WAF{Rules: testRules},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), test.config, "waf")
+			if test.expErr != "" {
+				require.EqualError(t, err, test.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWAF(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.WAF
+		method        string
+		target        string
+		headers       map[string]string
+		body          string
+		expStatusCode int
+		expRules      []int
+		expBody       string
+	}{
+		{
+			desc:          "no match",
+			config:        dynamic.WAF{Rules: testRules},
+			target:        "/foo?id=1",
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc:          "header match",
+			config:        dynamic.WAF{Rules: testRules},
+			target:        "/foo",
+			headers:       map[string]string{"User-Agent": "Mozilla/5.0 Nikto/2.1"},
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1001},
+		},
+		{
+			desc:          "query match",
+			config:        dynamic.WAF{Rules: testRules},
+			target:        "/foo?id=1%20UNION%20SELECT%20password",
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1002},
+		},
+		{
+			desc:          "form body match",
+			config:        dynamic.WAF{Rules: testRules},
+			method:        http.MethodPost,
+			target:        "/foo",
+			headers:       map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:          "name=bar&id=1+union+select+1",
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1002},
+		},
+		{
+			desc:          "JSON body match",
+			config:        dynamic.WAF{Rules: testRules},
+			method:        http.MethodPost,
+			target:        "/foo",
+			headers:       map[string]string{"Content-Type": "application/json"},
+			body:          `{"user":{"ids":[1,"2 union select 3"]}}`,
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1002},
+		},
+		{
+			desc:          "body forwarded",
+			config:        dynamic.WAF{Rules: testRules},
+			method:        http.MethodPost,
+			target:        "/foo",
+			headers:       map[string]string{"Content-Type": "application/json"},
+			body:          `{"user":"bar"}`,
+			expStatusCode: http.StatusOK,
+			expBody:       `{"user":"bar"}`,
+		},
+		{
+			desc:          "pass action",
+			config:        dynamic.WAF{Rules: testRules},
+			target:        "/admin/users",
+			expStatusCode: http.StatusOK,
+			expRules:      []int{1003},
+		},
+		{
+			desc:          "status",
+			config:        dynamic.WAF{Rules: testRules},
+			method:        http.MethodDelete,
+			target:        "/foo",
+			expStatusCode: http.StatusMethodNotAllowed,
+			expRules:      []int{1004},
+		},
+		{
+			desc:          "phase 1 interruption skips phase 2",
+			config:        dynamic.WAF{Rules: testRules},
+			target:        "/foo?id=union%20select",
+			headers:       map[string]string{"User-Agent": "sqlmap/1.0"},
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1001},
+		},
+		{
+			desc:          "detection only",
+			config:        dynamic.WAF{Rules: testRules, DetectionOnly: true},
+			target:        "/admin?id=union%20select",
+			headers:       map[string]string{"User-Agent": "sqlmap/1.0"},
+			expStatusCode: http.StatusOK,
+			expRules:      []int{1001, 1003, 1002},
+		},
+		{
+			desc:          "detection only engine",
+			config:        dynamic.WAF{Rules: "SecRuleEngine DetectionOnly\n" + testRules},
+			target:        "/foo",
+			headers:       map[string]string{"User-Agent": "sqlmap/1.0"},
+			expStatusCode: http.StatusOK,
+			expRules:      []int{1001},
+		},
+		{
+			desc:          "engine off",
+			config:        dynamic.WAF{Rules: "SecRuleEngine Off\n" + testRules},
+			target:        "/foo",
+			headers:       map[string]string{"User-Agent": "sqlmap/1.0"},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc:          "body too large",
+			config:        dynamic.WAF{Rules: testRules, MaxRequestBodyBytes: 10},
+			method:        http.MethodPost,
+			target:        "/foo",
+			body:          strings.Repeat("a", 11),
+			expStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "body too large in detection only mode",
+			config:        dynamic.WAF{Rules: testRules, MaxRequestBodyBytes: 10, DetectionOnly: true},
+			method:        http.MethodPost,
+			target:        "/foo",
+			body:          strings.Repeat("a", 11),
+			expStatusCode: http.StatusOK,
+			expBody:       strings.Repeat("a", 11),
+		},
+		{
+			desc:          "body access disabled",
+			config:        dynamic.WAF{Rules: "SecRequestBodyAccess Off\n" + testRules, MaxRequestBodyBytes: 10},
+			method:        http.MethodPost,
+			target:        "/foo",
+			headers:       map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:          "id=1+union+select+1",
+			expStatusCode: http.StatusOK,
+			expBody:       "id=1+union+select+1",
+		},
+		{
+			desc: "anomaly scoring",
+			config: dynamic.WAF{Rules: `
+SecDefaultAction "phase:2,log,pass"
+SecAction "id:900,phase:1,nolog,pass,setvar:tx.threshold=5"
+SecRule ARGS "@contains <script" "id:910,phase:2,block,setvar:tx.score=+3"
+SecRule REQUEST_HEADERS:Referer "@contains javascript:" "id:920,phase:2,block,setvar:tx.score=+3"
+SecRule TX:score "@ge %{tx.threshold}" "id:949,phase:2,deny,msg:'Anomaly score %{tx.score}'"
+`},
+			target:        "/foo?q=%3Cscript%3E",
+			headers:       map[string]string{"Referer": "javascript:alert(1)"},
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{910, 920, 949},
+		},
+		{
+			desc: "anomaly scoring under threshold",
+			config: dynamic.WAF{Rules: `
+SecDefaultAction "phase:2,log,pass"
+SecAction "id:900,phase:1,nolog,pass,setvar:tx.threshold=5"
+SecRule ARGS "@contains <script" "id:910,phase:2,block,setvar:tx.score=+3"
+SecRule TX:score "@ge %{tx.threshold}" "id:949,phase:2,deny"
+`},
+			target:        "/foo?q=%3Cscript%3E",
+			expStatusCode: http.StatusOK,
+			expRules:      []int{910},
+		},
+		{
+			desc: "block with default deny action",
+			config: dynamic.WAF{Rules: `
+SecDefaultAction "phase:1,deny,status:418"
+SecRule ARGS:id "!@rx ^\d+$" "id:1,phase:1,block"
+`},
+			target:        "/foo?id=abc",
+			expStatusCode: http.StatusTeapot,
+			expRules:      []int{1},
+		},
+		{
+			desc: "chain",
+			config: dynamic.WAF{Rules: `
+SecRule REQUEST_METHOD "@streq POST" "id:1,phase:1,deny,chain"
+    SecRule &REQUEST_HEADERS:Content-Type "@eq 0"
+`},
+			method:        http.MethodPost,
+			target:        "/foo",
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1},
+		},
+		{
+			desc: "chain not matching",
+			config: dynamic.WAF{Rules: `
+SecRule REQUEST_METHOD "@streq POST" "id:1,phase:1,deny,chain"
+    SecRule &REQUEST_HEADERS:Content-Type "@eq 0"
+`},
+			method:        http.MethodPost,
+			target:        "/foo",
+			headers:       map[string]string{"Content-Type": "text/plain"},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc: "capture",
+			config: dynamic.WAF{Rules: `
+SecRule REQUEST_FILENAME "@rx ^/api/(v\d+)/" "id:1,phase:1,deny,capture,chain"
+    SecRule TX:1 "!@within v1 v2"
+`},
+			target:        "/api/v3/users",
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1},
+		},
+		{
+			desc: "exclusion",
+			config: dynamic.WAF{Rules: `
+SecRule ARGS|!ARGS:password "@contains '" "id:1,phase:1,deny"
+`},
+			target:        "/login?password=it's",
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc: "skipAfter",
+			config: dynamic.WAF{Rules: `
+SecRule REQUEST_HEADERS:X-Internal "@streq true" "id:1,phase:1,pass,nolog,skipAfter:END"
+SecRule ARGS "@contains admin" "id:2,phase:1,deny"
+SecMarker END
+`},
+			target:        "/foo?user=admin",
+			headers:       map[string]string{"X-Internal": "true"},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc: "allow",
+			config: dynamic.WAF{Rules: `
+SecRule REMOTE_ADDR "@ipMatch 192.0.2.0/24" "id:1,phase:1,allow,nolog"
+SecRule ARGS "@contains admin" "id:2,phase:2,deny"
+`},
+			target:        "/foo?user=admin",
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc: "removed rule",
+			config: dynamic.WAF{Rules: testRules + `
+SecRuleRemoveById 1000-1001
+`},
+			target:        "/foo",
+			headers:       map[string]string{"User-Agent": "sqlmap/1.0"},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			desc: "removed rules with unsupported operator and action",
+			config: dynamic.WAF{Rules: `
+SecRule ARGS "@detectSQLi" "id:1,phase:2,deny"
+SecRule REQUEST_HEADERS:X-Debug "@streq true" "id:2,phase:1,pass,nolog,ctl:ruleEngine=Off"
+SecRule ARGS "@contains admin" "id:3,phase:2,deny"
+SecRuleRemoveById 1 2
+`},
+			target:        "/foo?user=admin",
+			headers:       map[string]string{"X-Debug": "true"},
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{3},
+		},
+		{
+			desc: "transformations",
+			config: dynamic.WAF{Rules: `
+SecRule REQUEST_HEADERS:X-Payload "@streq <script>" "id:1,phase:1,deny,t:none,t:htmlEntityDecode,t:lowercase,t:removeWhitespace"
+`},
+			target:        "/foo",
+			headers:       map[string]string{"X-Payload": "&lt;SCR IPT&gt;"},
+			expStatusCode: http.StatusForbidden,
+			expRules:      []int{1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				_, _ = rw.Write(body)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "waf")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://localhost"+test.target, strings.NewReader(test.body))
+			req.RemoteAddr = "192.0.2.1:1234"
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expStatusCode, rw.Code)
+
+			if test.expRules != nil {
+				assert.Equal(t, test.expRules, logData.Core[accesslog.WAFMatchedRules])
+			} else {
+				assert.NotContains(t, logData.Core, accesslog.WAFMatchedRules)
+			}
+
+			if test.expBody != "" {
+				assert.Equal(t, test.expBody, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestWAF_rulesFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "scanners.data"), []byte("# Scanners\nsqlmap\nnikto\n"), 0o600)
+	require.NoError(t, err)
+
+	rulesFile := filepath.Join(dir, "rules.conf")
+	err = os.WriteFile(rulesFile, []byte(`SecRule REQUEST_HEADERS:User-Agent "@pmFromFile scanners.data" "id:1,phase:1,deny"`), 0o600)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.WAF{RulesFiles: []string{rulesFile}}, "waf")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("User-Agent", "SQLMap/1.0")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestWAF_noLocalFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	dataFile := filepath.Join(dir, "scanners.data")
+	err := os.WriteFile(dataFile, []byte("sqlmap\n"), 0o600)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	// The files of the local filesystem cannot be read, even by absolute path.
+	_, err = New(context.Background(), next, dynamic.WAF{
+		Rules:        `SecRule REQUEST_HEADERS:User-Agent "@pmFromFile ` + dataFile + `" "id:1,phase:1,deny"`,
+		NoLocalFiles: true,
+	}, "waf")
+	require.ErrorContains(t, err, "cannot be read")
+
+	_, err = New(context.Background(), next, dynamic.WAF{
+		RulesFiles:   []string{dataFile},
+		NoLocalFiles: true,
+	}, "waf")
+	require.Error(t, err)
+
+	handler, err := New(context.Background(), next, dynamic.WAF{
+		Rules:        `SecRule REQUEST_HEADERS:User-Agent "@pmf scanners.data" "id:1,phase:1,deny"`,
+		NoLocalFiles: true,
+		DataFiles:    map[string]string{"scanners.data": "# Scanners\nnikto\n"},
+	}, "waf")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("User-Agent", "Nikto/2.5")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("User-Agent", "sqlmap/1.0")
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -8,6 +9,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
 	"golang.org/x/exp/slices"
@@ -27,6 +29,12 @@ var httpFuncs = map[string]func(*matchersTree, ...string) error{
 	"QueryRegexp":  expectNParameters(queryRegexp, 1, 2),
 }
 
+// geoIPFuncs are the matchers relying on the GeoIP database of the muxer.
+var geoIPFuncs = map[string]func(*geoip.Database, *matchersTree, ...string) error{
+	"ClientASN":     clientASN,
+	"ClientCountry": clientCountry,
+}
+
 func expectNParameters(fn func(*matchersTree, ...string) error, n ...int) func(*matchersTree, ...string) error {
 	return func(tree *matchersTree, s ...string) error {
 		if !slices.Contains(n, len(s)) {
@@ -58,6 +66,56 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+func clientCountry(db *geoip.Database, tree *matchersTree, countries ...string) error {
+	if db == nil {
+		return errors.New("no GeoIP database configured for ClientCountry matcher")
+	}
+
+	country, err := geoip.ParseCountry(countries[0])
+	if err != nil {
+		return fmt.Errorf("initializing ClientCountry matcher: %w", err)
+	}
+
+	strategy := ip.RemoteAddrStrategy{}
+
+	tree.matcher = func(req *http.Request) bool {
+		record, err := db.LookupString(strategy.GetIP(req))
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("ClientCountry matcher: could not look up remote address")
+			return false
+		}
+
+		return record.Country == country
+	}
+
+	return nil
+}
+
+func clientASN(db *geoip.Database, tree *matchersTree, asns ...string) error {
+	if db == nil {
+		return errors.New("no GeoIP database configured for ClientASN matcher")
+	}
+
+	asn, err := geoip.ParseASN(asns[0])
+	if err != nil {
+		return fmt.Errorf("initializing ClientASN matcher: %w", err)
+	}
+
+	strategy := ip.RemoteAddrStrategy{}
+
+	tree.matcher = func(req *http.Request) bool {
+		record, err := db.LookupString(strategy.GetIP(req))
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("ClientASN matcher: could not look up remote address")
+			return false
+		}
+
+		return record.ASN == asn
+	}
+
+	return nil
+}
+
 func method(tree *matchersTree, methods ...string) error {
 	method := strings.ToUpper(methods[0])
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
 )
 
@@ -96,6 +97,111 @@ func TestClientIPMatcher(t *testing.T) {
 	}
 }
 
+func TestClientGeoIPMatchers(t *testing.T) {
+	db, err := geoip.NewDatabase([]string{"../../geoip/fixtures/test.mmdb"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		rule          string
+		noDatabase    bool
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid ClientCountry matcher",
+			rule:          "ClientCountry(`FRA`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientCountry matcher (too many parameters)",
+			rule:          "ClientCountry(`FR`, `DE`)",
+			expectedError: true,
+		},
+		{
+			desc:          "ClientCountry matcher without database",
+			rule:          "ClientCountry(`FR`)",
+			noDatabase:    true,
+			expectedError: true,
+		},
+		{
+			desc: "valid ClientCountry matcher",
+			rule: "ClientCountry(`fr`)",
+			expected: map[string]int{
+				"1.2.3.4:1234":     http.StatusOK,
+				"81.2.69.1:1234":   http.StatusNotFound,
+				"8.8.8.8:1234":     http.StatusNotFound,
+				"[2001:db8::1]:80": http.StatusNotFound,
+				"1":                http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid ClientCountry matchers",
+			rule: "ClientCountry(`GB`) || ClientCountry(`DE`)",
+			expected: map[string]int{
+				"1.2.3.4:1234":     http.StatusNotFound,
+				"81.2.69.1:1234":   http.StatusOK,
+				"[2001:db8::1]:80": http.StatusOK,
+			},
+		},
+		{
+			desc:          "invalid ClientASN matcher",
+			rule:          "ClientASN(`foo`)",
+			expectedError: true,
+		},
+		{
+			desc:          "ClientASN matcher without database",
+			rule:          "ClientASN(`64500`)",
+			noDatabase:    true,
+			expectedError: true,
+		},
+		{
+			desc: "valid ClientASN matcher",
+			rule: "ClientASN(`AS64500`)",
+			expected: map[string]int{
+				"1.2.3.4:1234":   http.StatusOK,
+				"81.2.69.1:1234": http.StatusNotFound,
+				"8.8.8.8:1234":   http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			if !test.noDatabase {
+				muxer.SetGeoIPDatabase(db)
+			}
+
+			err = muxer.AddRoute(test.rule, "", 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for remoteAddr := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				req.RemoteAddr = remoteAddr
+
+				muxer.ServeHTTP(w, req)
+				results[remoteAddr] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
 func TestMethodMatcher(t *testing.T) {
 	testCases := []struct {
 		desc          string
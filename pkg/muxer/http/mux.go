@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/vulcand/predicate"
 )
@@ -16,6 +17,7 @@ type Muxer struct {
 	parser         predicate.Parser
 	parserV2       predicate.Parser
 	defaultHandler http.Handler
+	geoIP          *geoip.Database
 }
 
 // NewMuxer returns a new muxer instance.
@@ -24,6 +26,9 @@ func NewMuxer() (*Muxer, error) {
 	for matcher := range httpFuncs {
 		matchers = append(matchers, matcher)
 	}
+	for matcher := range geoIPFuncs {
+		matchers = append(matchers, matcher)
+	}
 
 	parser, err := rules.NewParser(matchers)
 	if err != nil {
@@ -65,6 +70,11 @@ func (m *Muxer) SetDefaultHandler(handler http.Handler) {
 	m.defaultHandler = handler
 }
 
+// SetGeoIPDatabase sets the GeoIP database used by the ClientCountry and ClientASN matchers.
+func (m *Muxer) SetGeoIPDatabase(db *geoip.Database) {
+	m.geoIP = db
+}
+
 // GetRulePriority computes the priority for a given rule.
 // The priority is calculated using the length of rule.
 func GetRulePriority(rule string) int {
@@ -91,7 +101,7 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler http.
 			return fmt.Errorf("error while parsing rule %s: %w", rule, err)
 		}
 
-		matcherFuncs = httpFuncs
+		matcherFuncs = m.httpFuncs()
 	}
 
 	buildTree, ok := parse.(rules.TreeBuilder)
@@ -116,6 +126,23 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler http.
 	return nil
 }
 
+// httpFuncs returns the matchers of the v3 syntax, including the GeoIP ones bound to the muxer database.
+func (m *Muxer) httpFuncs() map[string]func(*matchersTree, ...string) error {
+	funcs := make(map[string]func(*matchersTree, ...string) error, len(httpFuncs)+len(geoIPFuncs))
+	for name, fn := range httpFuncs {
+		funcs[name] = fn
+	}
+
+	for name, fn := range geoIPFuncs {
+		fn := fn
+		funcs[name] = expectNParameters(func(tree *matchersTree, s ...string) error {
+			return fn(m.geoIP, tree, s...)
+		}, 1)
+	}
+
+	return funcs
+}
+
 // ParseDomains extract domains from rule.
 func ParseDomains(rule string) ([]string, error) {
 	var matchers []string
@@ -125,6 +152,9 @@ func ParseDomains(rule string) ([]string, error) {
 	for matcher := range httpFuncsV2 {
 		matchers = append(matchers, matcher)
 	}
+	for matcher := range geoIPFuncs {
+		matchers = append(matchers, matcher)
+	}
 
 	parser, err := rules.NewParser(matchers)
 	if err != nil {
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -8,14 +9,24 @@ import (
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 var tcpFuncs = map[string]func(*matchersTree, ...string) error{
-	"ALPN":          expect1Parameter(alpn),
-	"ClientIP":      expect1Parameter(clientIP),
-	"HostSNI":       expect1Parameter(hostSNI),
-	"HostSNIRegexp": expect1Parameter(hostSNIRegexp),
+	"ALPN":                   expect1Parameter(alpn),
+	"ClientIP":               expect1Parameter(clientIP),
+	"HostSNI":                expect1Parameter(hostSNI),
+	"HostSNIRegexp":          expect1Parameter(hostSNIRegexp),
+	"ProxyProtocolALPN":      expect1Parameter(proxyProtocolALPN),
+	"ProxyProtocolAuthority": expect1Parameter(proxyProtocolAuthority),
+}
+
+// geoIPFuncs are the matchers relying on the GeoIP database of the muxer.
+var geoIPFuncs = map[string]func(*geoip.Database, *matchersTree, ...string) error{
+	"ClientASN":     clientASN,
+	"ClientCountry": clientCountry,
 }
 
 func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
@@ -67,6 +78,52 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+func clientCountry(db *geoip.Database, tree *matchersTree, countries ...string) error {
+	if db == nil {
+		return errors.New("no GeoIP database configured for ClientCountry matcher")
+	}
+
+	country, err := geoip.ParseCountry(countries[0])
+	if err != nil {
+		return fmt.Errorf("initializing ClientCountry matcher: %w", err)
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		record, err := db.LookupString(meta.remoteIP)
+		if err != nil {
+			log.Warn().Err(err).Msg("ClientCountry matcher: could not look up remote address")
+			return false
+		}
+
+		return record.Country == country
+	}
+
+	return nil
+}
+
+func clientASN(db *geoip.Database, tree *matchersTree, asns ...string) error {
+	if db == nil {
+		return errors.New("no GeoIP database configured for ClientASN matcher")
+	}
+
+	asn, err := geoip.ParseASN(asns[0])
+	if err != nil {
+		return fmt.Errorf("initializing ClientASN matcher: %w", err)
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		record, err := db.LookupString(meta.remoteIP)
+		if err != nil {
+			log.Warn().Err(err).Msg("ClientASN matcher: could not look up remote address")
+			return false
+		}
+
+		return record.ASN == asn
+	}
+
+	return nil
+}
+
 var hostOrIP = regexp.MustCompile(`^[[:alnum:]\.\-\:]+$`)
 
 // hostSNI checks if the SNI Host of the connection match the matcher host.
@@ -122,6 +179,35 @@ func hostSNIRegexp(tree *matchersTree, templates ...string) error {
 	return nil
 }
 
+// proxyProtocolAuthority checks if the authority sent in the PROXY protocol header of the connection matches the matcher host.
+func proxyProtocolAuthority(tree *matchersTree, hosts ...string) error {
+	host := hosts[0]
+
+	if !hostOrIP.MatchString(host) {
+		return fmt.Errorf("invalid value for ProxyProtocolAuthority matcher, %q is not a valid hostname", host)
+	}
+
+	// trim trailing period in case of FQDN
+	host = types.CanonicalDomain(strings.TrimSuffix(host, "."))
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.proxyProtocolAuthority != "" && meta.proxyProtocolAuthority == host
+	}
+
+	return nil
+}
+
+// proxyProtocolALPN checks if the application protocol sent in the PROXY protocol header of the connection matches the matcher protocol.
+func proxyProtocolALPN(tree *matchersTree, protos ...string) error {
+	proto := protos[0]
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.proxyProtocolALPN != "" && meta.proxyProtocolALPN == proto
+	}
+
+	return nil
+}
+
 // isASCII checks if the given string contains only ASCII characters.
 func isASCII(s string) bool {
 	for i := 0; i < len(s); i++ {
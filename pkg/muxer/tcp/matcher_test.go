@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
@@ -318,6 +319,98 @@ func Test_ClientIP(t *testing.T) {
 	}
 }
 
+func Test_ClientGeoIP(t *testing.T) {
+	db, err := geoip.NewDatabase([]string{"../../geoip/fixtures/test.mmdb"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc       string
+		rule       string
+		noDatabase bool
+		expected   map[string]bool
+		buildErr   bool
+	}{
+		{
+			desc:     "Invalid ClientCountry matcher",
+			rule:     "ClientCountry(`FRA`)",
+			buildErr: true,
+		},
+		{
+			desc:       "ClientCountry matcher without database",
+			rule:       "ClientCountry(`FR`)",
+			noDatabase: true,
+			buildErr:   true,
+		},
+		{
+			desc: "valid ClientCountry matcher",
+			rule: "ClientCountry(`FR`)",
+			expected: map[string]bool{
+				"1.2.3.4":     true,
+				"81.2.69.1":   false,
+				"8.8.8.8":     false,
+				"2001:db8::1": false,
+			},
+		},
+		{
+			desc:     "Invalid ClientASN matcher",
+			rule:     "ClientASN(`AS`)",
+			buildErr: true,
+		},
+		{
+			desc:       "ClientASN matcher without database",
+			rule:       "ClientASN(`64502`)",
+			noDatabase: true,
+			buildErr:   true,
+		},
+		{
+			desc: "valid ClientASN matcher",
+			rule: "ClientASN(`64502`)",
+			expected: map[string]bool{
+				"2001:db8::1": true,
+				"1.2.3.4":     false,
+			},
+		},
+		{
+			desc: "valid ClientCountry and ClientASN matchers",
+			rule: "ClientCountry(`GB`) && !ClientASN(`64500`)",
+			expected: map[string]bool{
+				"81.2.69.1": true,
+				"1.2.3.4":   false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			if !test.noDatabase {
+				muxer.SetGeoIPDatabase(db)
+			}
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for remoteIP, match := range test.expected {
+				meta := ConnData{
+					remoteIP: remoteIP,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, remoteIP)
+			}
+		})
+	}
+}
+
 func Test_ALPN(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -381,3 +474,108 @@ func Test_ALPN(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProxyProtocolAuthority(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[string]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ProxyProtocolAuthority matcher (invalid host)",
+			rule:     "ProxyProtocolAuthority(`foo/bar`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ProxyProtocolAuthority matcher (too many parameters)",
+			rule:     "ProxyProtocolAuthority(`foo.example.com`, `bar.example.com`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid ProxyProtocolAuthority matcher",
+			rule: "ProxyProtocolAuthority(`Foo.Example.com.`)",
+			expected: map[string]bool{
+				"foo.example.com": true,
+				"bar.example.com": false,
+				"":                false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for authority, match := range test.expected {
+				meta := ConnData{
+					proxyProtocolAuthority: authority,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, authority)
+			}
+		})
+	}
+}
+
+func Test_ProxyProtocolALPN(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[string]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ProxyProtocolALPN matcher (too many parameters)",
+			rule:     "ProxyProtocolALPN(`h2`, `mqtt`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid ProxyProtocolALPN matcher",
+			rule: "ProxyProtocolALPN(`h2`)",
+			expected: map[string]bool{
+				"h2":   true,
+				"mqtt": false,
+				"":     false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for proto, match := range test.expected {
+				meta := ConnData{
+					proxyProtocolALPN: proto,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, proto)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -18,6 +19,10 @@ type ConnData struct {
 	serverName string
 	remoteIP   string
 	alpnProtos []string
+
+	// proxyProtocolAuthority and proxyProtocolALPN are sent in the PROXY protocol header received by the entry point.
+	proxyProtocolAuthority string
+	proxyProtocolALPN      string
 }
 
 // NewConnData builds a connData struct from the given parameters.
@@ -32,11 +37,18 @@ func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (
 	// so there is no need to trim a potential trailing dot
 	serverName = types.CanonicalDomain(serverName)
 
-	return ConnData{
+	connData := ConnData{
 		serverName: types.CanonicalDomain(serverName),
 		remoteIP:   remoteIP,
 		alpnProtos: alpnProtos,
-	}, nil
+	}
+
+	if info, ok := tcp.GetProxyProtocolInfo(conn); ok {
+		connData.proxyProtocolAuthority = types.CanonicalDomain(info.Authority)
+		connData.proxyProtocolALPN = info.ALPN
+	}
+
+	return connData, nil
 }
 
 // Muxer defines a muxer that handles TCP routing with rules.
@@ -44,6 +56,7 @@ type Muxer struct {
 	routes   routes
 	parser   predicate.Parser
 	parserV2 predicate.Parser
+	geoIP    *geoip.Database
 }
 
 // NewMuxer returns a TCP muxer.
@@ -52,6 +65,9 @@ func NewMuxer() (*Muxer, error) {
 	for matcherName := range tcpFuncs {
 		matcherNames = append(matcherNames, matcherName)
 	}
+	for matcherName := range geoIPFuncs {
+		matcherNames = append(matcherNames, matcherName)
+	}
 
 	parser, err := rules.NewParser(matcherNames)
 	if err != nil {
@@ -86,6 +102,11 @@ func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 	return nil, false
 }
 
+// SetGeoIPDatabase sets the GeoIP database used by the ClientCountry and ClientASN matchers.
+func (m *Muxer) SetGeoIPDatabase(db *geoip.Database) {
+	m.geoIP = db
+}
+
 // GetRulePriority computes the priority for a given rule.
 // The priority is calculated using the length of rule.
 // There is a special case where the HostSNI(`*`) has a priority of -1.
@@ -139,7 +160,7 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler tcp.H
 			return fmt.Errorf("error while parsing rule %s: %w", rule, err)
 		}
 
-		matcherFuncs = tcpFuncs
+		matcherFuncs = m.tcpFuncs()
 	}
 
 	buildTree, ok := parse.(rules.TreeBuilder)
@@ -178,6 +199,23 @@ func (m *Muxer) HasRoutes() bool {
 	return len(m.routes) > 0
 }
 
+// tcpFuncs returns the matchers of the v3 syntax, including the GeoIP ones bound to the muxer database.
+func (m *Muxer) tcpFuncs() map[string]func(*matchersTree, ...string) error {
+	funcs := make(map[string]func(*matchersTree, ...string) error, len(tcpFuncs)+len(geoIPFuncs))
+	for name, fn := range tcpFuncs {
+		funcs[name] = fn
+	}
+
+	for name, fn := range geoIPFuncs {
+		fn := fn
+		funcs[name] = expect1Parameter(func(tree *matchersTree, s ...string) error {
+			return fn(m.geoIP, tree, s...)
+		})
+	}
+
+	return funcs
+}
+
 // ParseHostSNI extracts the HostSNIs declared in a rule.
 // This is a first naive implementation used in TCP routing.
 func ParseHostSNI(rule string) ([]string, error) {
@@ -188,6 +226,9 @@ func ParseHostSNI(rule string) ([]string, error) {
 	for matcher := range tcpFuncsV2 {
 		matchers = append(matchers, matcher)
 	}
+	for matcher := range geoIPFuncs {
+		matchers = append(matchers, matcher)
+	}
 
 	parser, err := rules.NewParser(matchers)
 	if err != nil {
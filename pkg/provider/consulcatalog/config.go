@@ -303,6 +303,11 @@ func (p *Provider) addServer(item itemData, loadBalancer *dynamic.ServersLoadBal
 
 	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(item.Address, port))
 
+	if loadBalancer.Servers[0].Zone == "" && loadBalancer.Servers[0].Region == "" {
+		loadBalancer.Servers[0].Zone = item.Zone
+		loadBalancer.Servers[0].Region = item.Region
+	}
+
 	return nil
 }
 
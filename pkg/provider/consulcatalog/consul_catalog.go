@@ -35,6 +35,8 @@ type itemData struct {
 	ID         string
 	Node       string
 	Datacenter string
+	Zone       string
+	Region     string
 	Name       string
 	Namespace  string
 	Address    string
@@ -335,6 +337,14 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 				Status:     status,
 			}
 
+			// The locality of the service overrides the locality of its node.
+			for _, locality := range []*api.Locality{consulService.Node.Locality, consulService.Service.Locality} {
+				if locality != nil {
+					item.Zone = locality.Zone
+					item.Region = locality.Region
+				}
+			}
+
 			extraConf, err := p.getExtraConf(item.Labels)
 			if err != nil {
 				log.Ctx(ctx).Error().Err(err).Msgf("Skip item %s", item.Name)
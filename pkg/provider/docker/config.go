@@ -282,6 +282,11 @@ func (p *DynConfBuilder) addServer(ctx context.Context, container dockerData, lo
 	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(ip, port))
 	loadBalancer.Servers[0].Scheme = ""
 
+	if loadBalancer.Servers[0].Zone == "" && loadBalancer.Servers[0].Region == "" {
+		loadBalancer.Servers[0].Zone = container.Zone
+		loadBalancer.Servers[0].Region = container.Region
+	}
+
 	return nil
 }
 
@@ -15,6 +15,9 @@ type dockerData struct {
 	NetworkSettings networkSettings
 	Health          string
 	Node            *dockertypes.ContainerNode
+	NodeID          string // ID of the Swarm node running the task
+	Zone            string
+	Region          string
 	ExtraConf       configuration
 }
 
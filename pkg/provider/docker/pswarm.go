@@ -27,6 +27,12 @@ const SwarmAPIVersion = "1.24"
 
 const swarmName = "swarm"
 
+// Labels of the swarm nodes defining the locality of the tasks they run.
+const (
+	labelNodeZone   = "zone"
+	labelNodeRegion = "region"
+)
+
 var _ provider.Provider = (*SwarmProvider)(nil)
 
 // SwarmProvider holds configurations of the provider.
@@ -191,6 +197,17 @@ func (p *SwarmProvider) listServices(ctx context.Context, dockerClient client.AP
 		networkMap[network.ID] = &networkToAdd
 	}
 
+	nodes, err := dockerClient.NodeList(ctx, dockertypes.NodeListOptions{})
+	if err != nil {
+		// The locality of the tasks is optional.
+		logger.Debug().Err(err).Msg("Failed to list the swarm nodes")
+	}
+
+	nodeMap := make(map[string]swarmtypes.Node)
+	for _, node := range nodes {
+		nodeMap[node.ID] = node
+	}
+
 	var dockerDataList []dockerData
 	var dockerDataListTasks []dockerData
 
@@ -211,6 +228,13 @@ func (p *SwarmProvider) listServices(ctx context.Context, dockerClient client.AP
 			if err != nil {
 				logger.Warn().Err(err).Send()
 			} else {
+				for i, taskData := range dockerDataListTasks {
+					if node, ok := nodeMap[taskData.NodeID]; ok {
+						dockerDataListTasks[i].Zone = node.Spec.Labels[labelNodeZone]
+						dockerDataListTasks[i].Region = node.Spec.Labels[labelNodeRegion]
+					}
+				}
+
 				dockerDataList = append(dockerDataList, dockerDataListTasks...)
 			}
 		}
@@ -296,6 +320,7 @@ func parseTasks(ctx context.Context, task swarmtypes.Task, serviceDockerData doc
 ) dockerData {
 	dData := dockerData{
 		ID:              task.ID,
+		NodeID:          task.NodeID,
 		ServiceName:     serviceDockerData.Name,
 		Name:            serviceDockerData.Name + "." + strconv.Itoa(task.Slot),
 		Labels:          serviceDockerData.Labels,
@@ -27,6 +27,7 @@ type fakeServicesClient struct {
 	dockerclient.APIClient
 	dockerVersion string
 	networks      []dockertypes.NetworkResource
+	nodes         []swarm.Node
 	services      []swarm.Service
 	tasks         []swarm.Task
 	err           error
@@ -47,3 +48,7 @@ func (c *fakeServicesClient) NetworkList(ctx context.Context, options dockertype
 func (c *fakeServicesClient) TaskList(ctx context.Context, options dockertypes.TaskListOptions) ([]swarm.Task, error) {
 	return c.tasks, c.err
 }
+
+func (c *fakeServicesClient) NodeList(ctx context.Context, options dockertypes.NodeListOptions) ([]swarm.Node, error) {
+	return c.nodes, c.err
+}
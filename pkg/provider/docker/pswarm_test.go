@@ -252,6 +252,61 @@ func TestSwarmProvider_listServices(t *testing.T) {
 	}
 }
 
+func TestSwarmProvider_listServices_locality(t *testing.T) {
+	dockerClient := &fakeServicesClient{
+		services: []swarm.Service{
+			swarmService(
+				serviceName("service1"),
+				serviceLabels(map[string]string{
+					"traefik.docker.network": "barnet",
+				}),
+				withEndpointSpec(modeDNSSR)),
+		},
+		tasks: []swarm.Task{
+			swarmTask("id1",
+				taskNodeID("node1"),
+				taskNetworkAttachment("yk6l57rfwizjzxxzftn4amaot", "network_name", "overlay", []string{"127.0.0.1"}),
+				taskStatus(taskState(swarm.TaskStateRunning)),
+			),
+			swarmTask("id2",
+				taskNodeID("node2"),
+				taskNetworkAttachment("yk6l57rfwizjzxxzftn4amaot", "network_name", "overlay", []string{"127.0.0.2"}),
+				taskStatus(taskState(swarm.TaskStateRunning)),
+			),
+		},
+		nodes: []swarm.Node{
+			{
+				ID: "node1",
+				Spec: swarm.NodeSpec{
+					Annotations: swarm.Annotations{
+						Labels: map[string]string{"zone": "eu-west-1a", "region": "eu-west-1"},
+					},
+				},
+			},
+		},
+		dockerVersion: "1.30",
+		networks: []dockertypes.NetworkResource{
+			{
+				Name:   "network_name",
+				ID:     "yk6l57rfwizjzxxzftn4amaot",
+				Scope:  "swarm",
+				Driver: "overlay",
+			},
+		},
+	}
+
+	p := SwarmProvider{}
+
+	serviceDockerData, err := p.listServices(context.Background(), dockerClient)
+	require.NoError(t, err)
+	require.Len(t, serviceDockerData, 2)
+
+	assert.Equal(t, "eu-west-1a", serviceDockerData[0].Zone)
+	assert.Equal(t, "eu-west-1", serviceDockerData[0].Region)
+	assert.Empty(t, serviceDockerData[1].Zone)
+	assert.Empty(t, serviceDockerData[1].Region)
+}
+
 func TestSwarmProvider_parseService_task(t *testing.T) {
 	testCases := []struct {
 		service     swarm.Service
@@ -206,9 +206,11 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		tlsConfigs = make(map[string]*tls.CertAndStores)
 	}
 
+	nodeLocalities := k8s.LoadNodeLocalities(ctx, client)
+
 	conf := &dynamic.Configuration{
 		// TODO: choose between mutating and returning tlsConfigs
-		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs),
+		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs, nodeLocalities),
 		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs),
 		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client),
 		TLS: &dynamic.TLSConfiguration{
@@ -243,7 +245,19 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
-		errorPage, errorPageService, err := p.createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
+		jwtAuth, err := createJWTMiddleware(client, middleware.Namespace, middleware.Spec.JWT)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading JWT middleware")
+			continue
+		}
+
+		oidcAuth, err := createOIDCMiddleware(client, middleware.Namespace, middleware.Spec.OIDC)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading OIDC middleware")
+			continue
+		}
+
+		errorPage, errorPageService, err := p.createErrorPageMiddleware(client, nodeLocalities, middleware.Namespace, middleware.Spec.Errors)
 		if err != nil {
 			logger.Error().Err(err).Msg("Error while reading error page middleware")
 			continue
@@ -261,7 +275,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
-		rateLimit, err := createRateLimitMiddleware(middleware.Spec.RateLimit)
+		rateLimit, err := createRateLimitMiddleware(client, middleware.Namespace, middleware.Spec.RateLimit)
 		if err != nil {
 			logger.Error().Err(err).Msg("Error while reading rateLimit middleware")
 			continue
@@ -279,6 +293,12 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
+		waf, err := createWAFMiddleware(client, middleware.Namespace, middleware.Spec.WAF)
+		if err != nil {
+			logger.Error().Err(err).Msg("Error while reading WAF middleware")
+			continue
+		}
+
 		conf.HTTP.Middlewares[id] = &dynamic.Middleware{
 			AddPrefix:         middleware.Spec.AddPrefix,
 			StripPrefix:       middleware.Spec.StripPrefix,
@@ -288,6 +308,8 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Chain:             createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
 			IPWhiteList:       middleware.Spec.IPWhiteList,
 			IPAllowList:       middleware.Spec.IPAllowList,
+			IPDenyList:        createIPDenyListMiddleware(middleware.Spec.IPDenyList),
+			GeoIP:             middleware.Spec.GeoIP,
 			Headers:           middleware.Spec.Headers,
 			Errors:            errorPage,
 			RateLimit:         rateLimit,
@@ -296,10 +318,17 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			BasicAuth:         basicAuth,
 			DigestAuth:        digestAuth,
 			ForwardAuth:       forwardAuth,
+			JWT:               jwtAuth,
+			OIDC:              oidcAuth,
 			InFlightReq:       middleware.Spec.InFlightReq,
+			BandwidthLimit:    middleware.Spec.BandwidthLimit,
 			Buffering:         middleware.Spec.Buffering,
+			Cache:             createCacheMiddleware(middleware.Spec.Cache),
 			CircuitBreaker:    circuitBreaker,
 			Compress:          middleware.Spec.Compress,
+			Decompress:        middleware.Spec.Decompress,
+			BodyRewrite:       middleware.Spec.BodyRewrite,
+			WAF:               waf,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             retry,
 			ContentType:       middleware.Spec.ContentType,
@@ -312,9 +341,12 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
 
 		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
-			InFlightConn: middlewareTCP.Spec.InFlightConn,
-			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
-			IPAllowList:  middlewareTCP.Spec.IPAllowList,
+			InFlightConn:   middlewareTCP.Spec.InFlightConn,
+			IPWhiteList:    middlewareTCP.Spec.IPWhiteList,
+			IPAllowList:    middlewareTCP.Spec.IPAllowList,
+			IPDenyList:     createTCPIPDenyListMiddleware(middlewareTCP.Spec.IPDenyList),
+			RateLimit:      middlewareTCP.Spec.RateLimit,
+			BandwidthLimit: middlewareTCP.Spec.BandwidthLimit,
 		}
 	}
 
@@ -323,6 +355,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		allowCrossNamespace:       p.AllowCrossNamespace,
 		allowExternalNameServices: p.AllowExternalNameServices,
 		allowEmptyServices:        p.AllowEmptyServices,
+		nodeLocalities:            nodeLocalities,
 	}
 
 	for _, service := range client.GetTraefikServices() {
@@ -646,12 +679,12 @@ func createCircuitBreakerMiddleware(circuitBreaker *traefikv1alpha1.CircuitBreak
 	return cb, nil
 }
 
-func createRateLimitMiddleware(rateLimit *traefikv1alpha1.RateLimit) (*dynamic.RateLimit, error) {
+func createRateLimitMiddleware(k8sClient Client, namespace string, rateLimit *traefikv1alpha1.RateLimit) (*dynamic.RateLimit, error) {
 	if rateLimit == nil {
 		return nil, nil
 	}
 
-	rl := &dynamic.RateLimit{Average: rateLimit.Average}
+	rl := &dynamic.RateLimit{Average: rateLimit.Average, SendHeaders: rateLimit.SendHeaders}
 	rl.SetDefaults()
 
 	if rateLimit.Burst != nil {
@@ -669,9 +702,91 @@ func createRateLimitMiddleware(rateLimit *traefikv1alpha1.RateLimit) (*dynamic.R
 		rl.SourceCriterion = rateLimit.SourceCriterion
 	}
 
+	if rateLimit.Redis != nil {
+		redis, err := createRedisConfig(k8sClient, namespace, rateLimit.Redis)
+		if err != nil {
+			return nil, err
+		}
+		rl.Redis = redis
+	}
+
+	for _, tier := range rateLimit.Tiers {
+		rlTier := dynamic.RateLimitTier{
+			Name:            tier.Name,
+			Average:         tier.Average,
+			Burst:           1,
+			SourceCriterion: tier.SourceCriterion,
+		}
+
+		if tier.Burst != nil {
+			rlTier.Burst = *tier.Burst
+		}
+
+		if tier.Period != nil {
+			err := rlTier.Period.Set(tier.Period.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rl.Tiers = append(rl.Tiers, rlTier)
+	}
+
 	return rl, nil
 }
 
+func createRedisConfig(k8sClient Client, namespace string, redis *traefikv1alpha1.Redis) (*dynamic.Redis, error) {
+	options := &dynamic.Redis{DB: redis.DB}
+	options.SetDefaults()
+
+	if len(redis.Endpoints) > 0 {
+		options.Endpoints = redis.Endpoints
+	}
+
+	if redis.TLS != nil {
+		options.TLS = &types.ClientTLS{
+			InsecureSkipVerify: redis.TLS.InsecureSkipVerify,
+		}
+
+		if len(redis.TLS.CASecret) > 0 {
+			caSecret, err := loadCASecret(namespace, redis.TLS.CASecret, k8sClient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load redis ca secret: %w", err)
+			}
+			options.TLS.CA = caSecret
+		}
+
+		if len(redis.TLS.CertSecret) > 0 {
+			cert, key, err := loadAuthTLSSecret(namespace, redis.TLS.CertSecret, k8sClient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load redis secret: %w", err)
+			}
+			options.TLS.Cert = cert
+			options.TLS.Key = key
+		}
+	}
+
+	if redis.Secret == "" {
+		return options, nil
+	}
+
+	secret, ok, err := k8sClient.GetSecret(namespace, redis.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, redis.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, redis.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, redis.Secret)
+	}
+
+	options.Username = string(secret.Data["username"])
+	options.Password = string(secret.Data["password"])
+
+	return options, nil
+}
+
 func createRetryMiddleware(retry *traefikv1alpha1.Retry) (*dynamic.Retry, error) {
 	if retry == nil {
 		return nil, nil
@@ -687,7 +802,7 @@ func createRetryMiddleware(retry *traefikv1alpha1.Retry) (*dynamic.Retry, error)
 	return r, nil
 }
 
-func (p *Provider) createErrorPageMiddleware(client Client, namespace string, errorPage *traefikv1alpha1.ErrorPage) (*dynamic.ErrorPage, *dynamic.Service, error) {
+func (p *Provider) createErrorPageMiddleware(client Client, nodeLocalities map[string]k8s.Locality, namespace string, errorPage *traefikv1alpha1.ErrorPage) (*dynamic.ErrorPage, *dynamic.Service, error) {
 	if errorPage == nil {
 		return nil, nil, nil
 	}
@@ -702,6 +817,7 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 		allowCrossNamespace:       p.AllowCrossNamespace,
 		allowExternalNameServices: p.AllowExternalNameServices,
 		allowEmptyServices:        p.AllowEmptyServices,
+		nodeLocalities:            nodeLocalities,
 	}
 
 	balancerServerHTTP, err := cb.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
@@ -759,6 +875,233 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *traef
 	return forwardAuth, nil
 }
 
+func createJWTMiddleware(k8sClient Client, namespace string, auth *traefikv1alpha1.JWT) (*dynamic.JWT, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	jwtAuth := &dynamic.JWT{
+		PublicKeys:     auth.PublicKeys,
+		JWKSURL:        auth.JWKSURL,
+		Issuer:         auth.Issuer,
+		Audiences:      auth.Audiences,
+		ClaimRules:     auth.ClaimRules,
+		ForwardHeaders: auth.ForwardHeaders,
+		RemoveHeader:   auth.RemoveHeader,
+	}
+	jwtAuth.SetDefaults()
+
+	if auth.JWKSRefreshInterval != nil {
+		if err := jwtAuth.JWKSRefreshInterval.Set(auth.JWKSRefreshInterval.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if auth.ClockSkew != nil {
+		if err := jwtAuth.ClockSkew.Set(auth.ClockSkew.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if auth.Secret == "" {
+		return jwtAuth, nil
+	}
+
+	secret, ok, err := k8sClient.GetSecret(namespace, auth.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, auth.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, auth.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, auth.Secret)
+	}
+
+	value, ok := secret.Data["secret"]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' must contain the secret key", namespace, auth.Secret)
+	}
+	jwtAuth.Secret = string(value)
+
+	return jwtAuth, nil
+}
+
+// createCacheMiddleware creates the cache middleware configuration,
+// which only stores the responses in memory, as the disk directory of the dynamic configuration would be written by Traefik.
+func createCacheMiddleware(cache *traefikv1alpha1.Cache) *dynamic.Cache {
+	if cache == nil {
+		return nil
+	}
+
+	return &dynamic.Cache{
+		MaxSize:              cache.MaxSize,
+		MaxResponseBodyBytes: cache.MaxResponseBodyBytes,
+	}
+}
+
+// createIPDenyListMiddleware creates the IPDenyList middleware configuration,
+// which only holds inline ranges, as the files and URLs of the dynamic configuration would be read and fetched by Traefik.
+func createIPDenyListMiddleware(ipDenyList *traefikv1alpha1.IPDenyList) *dynamic.IPDenyList {
+	if ipDenyList == nil {
+		return nil
+	}
+
+	return &dynamic.IPDenyList{
+		SourceRange:      ipDenyList.SourceRange,
+		IPStrategy:       ipDenyList.IPStrategy,
+		RejectStatusCode: ipDenyList.RejectStatusCode,
+	}
+}
+
+// createTCPIPDenyListMiddleware creates the TCP IPDenyList middleware configuration,
+// which only holds inline ranges, as the files and URLs of the dynamic configuration would be read and fetched by Traefik.
+func createTCPIPDenyListMiddleware(ipDenyList *traefikv1alpha1.TCPIPDenyList) *dynamic.TCPIPDenyList {
+	if ipDenyList == nil {
+		return nil
+	}
+
+	return &dynamic.TCPIPDenyList{SourceRange: ipDenyList.SourceRange}
+}
+
+// createWAFMiddleware creates the WAF middleware configuration, whose rules files and data files are read from Secrets of the same namespace,
+// as the rules of the Kubernetes CRD cannot read the files of the local filesystem.
+func createWAFMiddleware(k8sClient Client, namespace string, waf *traefikv1alpha1.WAF) (*dynamic.WAF, error) {
+	if waf == nil {
+		return nil, nil
+	}
+
+	wafConfig := &dynamic.WAF{
+		Rules:               waf.Rules,
+		DetectionOnly:       waf.DetectionOnly,
+		MaxRequestBodyBytes: waf.MaxRequestBodyBytes,
+		NoLocalFiles:        true,
+	}
+
+	if waf.RulesSecret != "" {
+		data, err := getWAFSecretData(k8sClient, namespace, waf.RulesSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var rules strings.Builder
+		for _, key := range keys {
+			rules.Write(data[key])
+			rules.WriteString("\n")
+		}
+		rules.WriteString(waf.Rules)
+
+		wafConfig.Rules = rules.String()
+	}
+
+	if waf.DataSecret != "" {
+		data, err := getWAFSecretData(k8sClient, namespace, waf.DataSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		wafConfig.DataFiles = make(map[string]string, len(data))
+		for key, value := range data {
+			wafConfig.DataFiles[key] = string(value)
+		}
+	}
+
+	return wafConfig, nil
+}
+
+func getWAFSecretData(k8sClient Client, namespace, secretName string) (map[string][]byte, error) {
+	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+
+	return secret.Data, nil
+}
+
+func createOIDCMiddleware(k8sClient Client, namespace string, auth *traefikv1alpha1.OIDC) (*dynamic.OIDC, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	if auth.Secret == "" {
+		return nil, errors.New("OIDC secret must be set")
+	}
+
+	secret, ok, err := k8sClient.GetSecret(namespace, auth.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, auth.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, auth.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, auth.Secret)
+	}
+
+	sessionSecret, ok := secret.Data["sessionSecret"]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' must contain the sessionSecret key", namespace, auth.Secret)
+	}
+
+	oidcAuth := &dynamic.OIDC{}
+	oidcAuth.SetDefaults()
+
+	oidcAuth.Issuer = auth.Issuer
+	oidcAuth.ClientID = auth.ClientID
+	oidcAuth.ClientSecret = string(secret.Data["clientSecret"])
+	oidcAuth.PostLogoutRedirectURL = auth.PostLogoutRedirectURL
+	oidcAuth.ForwardHeaders = auth.ForwardHeaders
+	oidcAuth.ForwardAccessToken = auth.ForwardAccessToken
+
+	if len(auth.Scopes) > 0 {
+		oidcAuth.Scopes = auth.Scopes
+	}
+
+	if auth.RedirectPath != "" {
+		oidcAuth.RedirectPath = auth.RedirectPath
+	}
+
+	if auth.LogoutPath != "" {
+		oidcAuth.LogoutPath = auth.LogoutPath
+	}
+
+	oidcAuth.Session = &dynamic.OIDCSession{}
+	oidcAuth.Session.SetDefaults()
+	oidcAuth.Session.Secret = string(sessionSecret)
+
+	if auth.Session != nil {
+		oidcAuth.Session.Domain = auth.Session.Domain
+		oidcAuth.Session.Secure = auth.Session.Secure
+		oidcAuth.Session.MaxAge = auth.Session.MaxAge
+
+		if auth.Session.Name != "" {
+			oidcAuth.Session.Name = auth.Session.Name
+		}
+
+		if auth.Session.Path != "" {
+			oidcAuth.Session.Path = auth.Session.Path
+		}
+
+		if auth.Session.SameSite != "" {
+			oidcAuth.Session.SameSite = auth.Session.SameSite
+		}
+	}
+
+	return oidcAuth, nil
+}
+
 func loadCASecret(namespace, secretName string, k8sClient Client) (string, error) {
 	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
 	if err != nil {
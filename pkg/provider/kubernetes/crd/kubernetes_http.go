@@ -13,6 +13,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	traefikv1alpha1 "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v3/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -24,7 +25,7 @@ const (
 	httpProtocol       = "http"
 )
 
-func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.HTTPConfiguration {
+func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, nodeLocalities map[string]k8s.Locality) *dynamic.HTTPConfiguration {
 	conf := &dynamic.HTTPConfiguration{
 		Routers:           map[string]*dynamic.Router{},
 		Middlewares:       map[string]*dynamic.Middleware{},
@@ -55,6 +56,7 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 			allowCrossNamespace:       p.AllowCrossNamespace,
 			allowExternalNameServices: p.AllowExternalNameServices,
 			allowEmptyServices:        p.AllowEmptyServices,
+			nodeLocalities:            nodeLocalities,
 		}
 
 		for _, route := range ingressRoute.Spec.Routes {
@@ -202,6 +204,7 @@ type configBuilder struct {
 	allowCrossNamespace       bool
 	allowExternalNameServices bool
 	allowEmptyServices        bool
+	nodeLocalities            map[string]k8s.Locality
 }
 
 // buildTraefikService creates the configuration for the traefik service defined in tService,
@@ -244,15 +247,69 @@ func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tS
 		})
 	}
 
+	sticky, err := c.createSticky(namespace, tService.Weighted.Sticky)
+	if err != nil {
+		return err
+	}
+
 	conf[id] = &dynamic.Service{
 		Weighted: &dynamic.WeightedRoundRobin{
 			Services: wrrServices,
-			Sticky:   tService.Weighted.Sticky,
+			Sticky:   sticky,
 		},
 	}
 	return nil
 }
 
+// createSticky creates the sticky sessions configuration,
+// whose cookie secret is read from the secret key of a Secret of the same namespace.
+func (c configBuilder) createSticky(namespace string, sticky *traefikv1alpha1.Sticky) (*dynamic.Sticky, error) {
+	if sticky == nil {
+		return nil, nil
+	}
+
+	stickyConf := &dynamic.Sticky{
+		Header:   sticky.Header,
+		ClientIP: sticky.ClientIP,
+	}
+
+	if sticky.Cookie == nil {
+		return stickyConf, nil
+	}
+
+	stickyConf.Cookie = &dynamic.Cookie{
+		Name:     sticky.Cookie.Name,
+		Secure:   sticky.Cookie.Secure,
+		HTTPOnly: sticky.Cookie.HTTPOnly,
+		SameSite: sticky.Cookie.SameSite,
+		MaxAge:   sticky.Cookie.MaxAge,
+		Encrypt:  sticky.Cookie.Encrypt,
+	}
+
+	if sticky.Cookie.Secret == "" {
+		return stickyConf, nil
+	}
+
+	secret, ok, err := c.client.GetSecret(namespace, sticky.Cookie.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, sticky.Cookie.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, sticky.Cookie.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, sticky.Cookie.Secret)
+	}
+
+	value, ok := secret.Data["secret"]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' must contain the secret key", namespace, sticky.Cookie.Secret)
+	}
+	stickyConf.Cookie.Secret = string(value)
+
+	return stickyConf, nil
+}
+
 // buildMirroring creates the configuration for the mirroring service named id, and defined by tService.
 // It adds it to the given conf map.
 func (c configBuilder) buildMirroring(ctx context.Context, tService *traefikv1alpha1.TraefikService, id string, conf map[string]*dynamic.Service) error {
@@ -318,7 +375,10 @@ func (c configBuilder) buildServersLB(namespace string, svc traefikv1alpha1.Load
 		}
 	}
 
-	lb.Sticky = svc.Sticky
+	lb.Sticky, err = c.createSticky(namespace, svc.Sticky)
+	if err != nil {
+		return nil, err
+	}
 
 	lb.ServersTransport, err = c.makeServersTransportKey(namespace, svc.ServersTransport)
 	if err != nil {
@@ -475,9 +535,17 @@ func (c configBuilder) loadServers(parentNamespace string, svc traefikv1alpha1.L
 		for _, addr := range subset.Addresses {
 			hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(int(port)))
 
-			servers = append(servers, dynamic.Server{
+			server := dynamic.Server{
 				URL: fmt.Sprintf("%s://%s", protocol, hostPort),
-			})
+			}
+
+			if addr.NodeName != nil {
+				locality := c.nodeLocalities[*addr.NodeName]
+				server.Zone = locality.Zone
+				server.Region = locality.Region
+			}
+
+			servers = append(servers, server)
 		}
 	}
 
@@ -202,6 +202,8 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 		if service.ProxyProtocol.Version != 0 {
 			tcpService.LoadBalancer.ProxyProtocol.Version = service.ProxyProtocol.Version
 		}
+
+		tcpService.LoadBalancer.ProxyProtocol.TLVs = service.ProxyProtocol.TLVs
 	}
 
 	if service.ServersTransport == "" && service.TerminationDelay != nil {
@@ -1686,6 +1686,60 @@ func TestLoadIngressRoutes(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "Simple Ingress Route, with a sticky cookie secret read from a secret",
+			paths: []string{"services.yml", "with_sticky_cookie_secret.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:           map[string]*dynamic.TCPRouter{},
+					Middlewares:       map[string]*dynamic.TCPMiddleware{},
+					Services:          map[string]*dynamic.TCPService{},
+					ServersTransports: map[string]*dynamic.TCPServersTransport{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"default-test-route-6b204d94623b3df4370c": {
+							EntryPoints: []string{"foo"},
+							Service:     "default-test-route-6b204d94623b3df4370c",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:    12,
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-test-route-6b204d94623b3df4370c": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Sticky: &dynamic.Sticky{
+									Cookie: &dynamic.Cookie{
+										Name:    "foo",
+										Secret:  "supersecret",
+										Encrypt: true,
+									},
+								},
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+								ResponseForwarding: &dynamic.ResponseForwarding{
+									FlushInterval: ptypes.Duration(100 * time.Millisecond),
+								},
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:                "Simple Ingress Route with middleware",
 			allowCrossNamespace: true,
@@ -1724,6 +1778,30 @@ func TestLoadIngressRoutes(t *testing.T) {
 								},
 							},
 						},
+						"default-ratelimit-redis": {
+							RateLimit: &dynamic.RateLimit{
+								Average: 100,
+								Burst:   1,
+								Period:  ptypes.Duration(time.Second),
+								Redis: &dynamic.Redis{
+									Endpoints: []string{"redis-1:6379", "redis-2:6379"},
+									Username:  "user",
+									Password:  "password",
+									DB:        2,
+								},
+								Tiers: []dynamic.RateLimitTier{
+									{
+										Name:    "apikey",
+										Average: 10,
+										Period:  ptypes.Duration(time.Minute),
+										Burst:   1,
+										SourceCriterion: &dynamic.SourceCriterion{
+											RequestHeaderName: "X-Api-Key",
+										},
+									},
+								},
+							},
+						},
 						"default-stripprefix": {
 							StripPrefix: &dynamic.StripPrefix{
 								Prefixes: []string{"/tobestripped"},
@@ -3660,6 +3738,132 @@ func TestLoadIngressRoutes(t *testing.T) {
 								},
 							},
 						},
+						"default-jwt": {
+							JWT: &dynamic.JWT{
+								Secret:              "mysecret",
+								JWKSRefreshInterval: ptypes.Duration(15 * time.Minute),
+								Issuer:              "https://example.com",
+								ClockSkew:           ptypes.Duration(30 * time.Second),
+							},
+						},
+						"default-oidc": {
+							OIDC: &dynamic.OIDC{
+								Issuer:       "https://example.com",
+								ClientID:     "client",
+								ClientSecret: "clientsecret",
+								Scopes:       []string{"openid", "profile", "email"},
+								RedirectPath: "/oauth2/callback",
+								LogoutPath:   "/oauth2/logout",
+								Session: &dynamic.OIDCSession{
+									Secret:   "sessionsecret",
+									Name:     "traefik_oidc",
+									Path:     "/",
+									Secure:   true,
+									SameSite: "lax",
+								},
+							},
+						},
+					},
+					Services:          map[string]*dynamic.Service{},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc:  "Simple Ingress Route, with WAF middlewares reading their rules and data files from secrets, and not from the local filesystem",
+			paths: []string{"services.yml", "with_waf.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:           map[string]*dynamic.TCPRouter{},
+					Middlewares:       map[string]*dynamic.TCPMiddleware{},
+					Services:          map[string]*dynamic.TCPService{},
+					ServersTransports: map[string]*dynamic.TCPServersTransport{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-waf": {
+							WAF: &dynamic.WAF{
+								Rules:         "SecRuleEngine On\nSecRule ARGS \"@contains foo\" \"id:1,deny\"\nSecRule ARGS \"@contains bar\" \"id:2,deny\"",
+								DetectionOnly: true,
+								NoLocalFiles:  true,
+								DataFiles:     map[string]string{"scanners.data": "sqlmap\n"},
+							},
+						},
+						"default-waf-local-file": {
+							WAF: &dynamic.WAF{
+								Rules:        "SecRule ARGS \"@pmFromFile /var/run/secrets/kubernetes.io/serviceaccount/token\" \"id:1,deny\"",
+								NoLocalFiles: true,
+							},
+						},
+					},
+					Services:          map[string]*dynamic.Service{},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc:  "Cache middleware, without the disk storage of the dynamic configuration",
+			paths: []string{"services.yml", "with_cache.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:           map[string]*dynamic.TCPRouter{},
+					Middlewares:       map[string]*dynamic.TCPMiddleware{},
+					Services:          map[string]*dynamic.TCPService{},
+					ServersTransports: map[string]*dynamic.TCPServersTransport{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-cache": {
+							Cache: &dynamic.Cache{MaxSize: 1048576},
+						},
+					},
+					Services:          map[string]*dynamic.Service{},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc:  "IPDenyList middlewares, without the files and URLs of the dynamic configuration",
+			paths: []string{"services.yml", "with_ipdenylist.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{
+						"default-ipdenylist": {
+							IPDenyList: &dynamic.TCPIPDenyList{
+								SourceRange: []string{"192.168.1.7"},
+							},
+						},
+					},
+					Services:          map[string]*dynamic.TCPService{},
+					ServersTransports: map[string]*dynamic.TCPServersTransport{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-ipdenylist": {
+							IPDenyList: &dynamic.IPDenyList{
+								SourceRange:      []string{"192.168.1.7"},
+								RejectStatusCode: 404,
+							},
+						},
 					},
 					Services:          map[string]*dynamic.Service{},
 					ServersTransports: map[string]*dynamic.ServersTransport{},
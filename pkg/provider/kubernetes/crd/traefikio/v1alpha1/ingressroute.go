@@ -99,7 +99,7 @@ type LoadBalancerSpec struct {
 	Namespace string `json:"namespace,omitempty"`
 	// Sticky defines the sticky sessions configuration.
 	// More info: https://doc.traefik.io/traefik/v3.0/routing/services/#sticky-sessions
-	Sticky *dynamic.Sticky `json:"sticky,omitempty"`
+	Sticky *Sticky `json:"sticky,omitempty"`
 	// Port defines the port of a Kubernetes Service.
 	// This can be a reference to a named port.
 	Port intstr.IntOrString `json:"port,omitempty"`
@@ -142,6 +142,38 @@ type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty"`
 }
 
+// Sticky holds the sticky sessions configuration.
+type Sticky struct {
+	// Cookie defines the sticky cookie configuration.
+	Cookie *Cookie `json:"cookie,omitempty"`
+	// Header defines the sticky configuration based on the value of a request header.
+	Header *dynamic.StickyHeader `json:"header,omitempty"`
+	// ClientIP defines the sticky configuration based on the client IP.
+	ClientIP *dynamic.StickyClientIP `json:"clientIP,omitempty"`
+}
+
+// Cookie holds the sticky configuration based on cookie.
+type Cookie struct {
+	// Name defines the Cookie name.
+	Name string `json:"name,omitempty"`
+	// Secure defines whether the cookie can only be transmitted over an encrypted connection (i.e. HTTPS).
+	Secure bool `json:"secure,omitempty"`
+	// HTTPOnly defines whether the cookie can be accessed by client-side APIs, such as JavaScript.
+	HTTPOnly bool `json:"httpOnly,omitempty"`
+	// SameSite defines the same site policy.
+	// More info: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
+	SameSite string `json:"sameSite,omitempty"`
+	// MaxAge indicates the number of seconds until the cookie expires.
+	// When set to a negative number, the cookie expires immediately.
+	// When set to zero, the cookie never expires.
+	MaxAge int `json:"maxAge,omitempty"`
+	// Secret is the name of the referenced Kubernetes Secret containing the secret used to sign the cookie value, in its secret key,
+	// so that clients cannot forge a cookie to target a specific server.
+	Secret string `json:"secret,omitempty"`
+	// Encrypt defines whether the cookie value is encrypted with the secret, instead of being signed.
+	Encrypt bool `json:"encrypt,omitempty"`
+}
+
 // Service defines an upstream HTTP service to proxy traffic to.
 type Service struct {
 	LoadBalancerSpec `json:",inline"`
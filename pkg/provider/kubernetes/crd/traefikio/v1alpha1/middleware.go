@@ -35,6 +35,8 @@ type MiddlewareSpec struct {
 	// Deprecated: please use IPAllowList instead.
 	IPWhiteList       *dynamic.IPWhiteList       `json:"ipWhiteList,omitempty"`
 	IPAllowList       *dynamic.IPAllowList       `json:"ipAllowList,omitempty"`
+	IPDenyList        *IPDenyList                `json:"ipDenyList,omitempty"`
+	GeoIP             *dynamic.GeoIP             `json:"geoIP,omitempty"`
 	Headers           *dynamic.Headers           `json:"headers,omitempty"`
 	Errors            *ErrorPage                 `json:"errors,omitempty"`
 	RateLimit         *RateLimit                 `json:"rateLimit,omitempty"`
@@ -43,10 +45,17 @@ type MiddlewareSpec struct {
 	BasicAuth         *BasicAuth                 `json:"basicAuth,omitempty"`
 	DigestAuth        *DigestAuth                `json:"digestAuth,omitempty"`
 	ForwardAuth       *ForwardAuth               `json:"forwardAuth,omitempty"`
+	JWT               *JWT                       `json:"jwt,omitempty"`
+	OIDC              *OIDC                      `json:"oidc,omitempty"`
 	InFlightReq       *dynamic.InFlightReq       `json:"inFlightReq,omitempty"`
+	BandwidthLimit    *dynamic.BandwidthLimit    `json:"bandwidthLimit,omitempty"`
 	Buffering         *dynamic.Buffering         `json:"buffering,omitempty"`
+	Cache             *Cache                     `json:"cache,omitempty"`
 	CircuitBreaker    *CircuitBreaker            `json:"circuitBreaker,omitempty"`
 	Compress          *dynamic.Compress          `json:"compress,omitempty"`
+	Decompress        *dynamic.Decompress        `json:"decompress,omitempty"`
+	BodyRewrite       *dynamic.BodyRewrite       `json:"bodyRewrite,omitempty"`
+	WAF               *WAF                       `json:"waf,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert `json:"passTLSClientCert,omitempty"`
 	Retry             *Retry                     `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType       `json:"contentType,omitempty"`
@@ -78,6 +87,23 @@ type ErrorPage struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the cache middleware configuration.
+// This middleware stores the responses of the service in memory, and serves them again to the subsequent requests,
+// following the HTTP caching rules (RFC 9111).
+// The responses cannot be stored on the local disk with the Kubernetes CRD.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/cache/
+type Cache struct {
+	// MaxSize defines the maximum size (in bytes) of the stored responses.
+	// When the cache is full, the least recently used responses are evicted.
+	// Default: 67108864 (64Mi).
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// MaxResponseBodyBytes defines the maximum body size (in bytes) of a response to be stored.
+	// Default: 1048576 (1Mi).
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // CircuitBreaker holds the circuit breaker configuration.
 type CircuitBreaker struct {
 	// Expression is the condition that triggers the tripped state.
@@ -161,6 +187,94 @@ type ForwardAuth struct {
 	AddAuthCookiesToResponse []string `json:"addAuthCookiesToResponse,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+
+// JWT holds the JWT middleware configuration.
+// This middleware validates the JSON Web Token (JWT) carried by the Authorization header of the requests.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/jwt/
+type JWT struct {
+	// PublicKeys defines the PEM-encoded public keys used to verify the token signatures.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	// Secret is the name of the referenced Kubernetes Secret containing the secret used to verify the HMAC token signatures.
+	// The secret is extracted from the key `secret`.
+	Secret string `json:"secret,omitempty"`
+	// JWKSURL defines the URL of the JSON Web Key Set used to verify the token signatures.
+	JWKSURL string `json:"jwksUrl,omitempty"`
+	// JWKSRefreshInterval defines the interval after which the cached JSON Web Key Set is refreshed in the background.
+	// Default: 15m.
+	JWKSRefreshInterval *intstr.IntOrString `json:"jwksRefreshInterval,omitempty"`
+	// Issuer defines the expected issuer (iss claim) of the tokens.
+	Issuer string `json:"issuer,omitempty"`
+	// Audiences defines the accepted audiences (aud claim) of the tokens.
+	// The tokens must be intended for at least one of them.
+	Audiences []string `json:"audiences,omitempty"`
+	// ClockSkew defines the tolerance applied when checking the exp and nbf claims of the tokens.
+	ClockSkew *intstr.IntOrString `json:"clockSkew,omitempty"`
+	// ClaimRules defines the rules that the claims of the tokens must satisfy.
+	ClaimRules []dynamic.JWTClaimRule `json:"claimRules,omitempty"`
+	// ForwardHeaders defines the headers to set on the forwarded request, keyed by header name, from the claims of the token.
+	// It replaces any existing conflicting headers.
+	ForwardHeaders map[string]string `json:"forwardHeaders,omitempty"`
+	// RemoveHeader defines whether to remove the authorization header before forwarding the request to the backend.
+	RemoveHeader bool `json:"removeHeader,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect middleware configuration.
+// This middleware authenticates the users with an OpenID Connect provider, using the authorization code flow with PKCE.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/oidc/
+type OIDC struct {
+	// Issuer defines the URL of the OpenID Connect provider, used to discover its endpoints.
+	Issuer string `json:"issuer,omitempty"`
+	// ClientID defines the client identifier registered with the provider.
+	ClientID string `json:"clientId,omitempty"`
+	// Secret is the name of the referenced Kubernetes Secret containing the client secret and the session secret.
+	// The client secret is extracted from the key `clientSecret`, and the secret used to encrypt the session cookie from the key `sessionSecret`.
+	Secret string `json:"secret,omitempty"`
+	// Scopes defines the scopes requested to the provider.
+	// Default: openid, profile, email.
+	Scopes []string `json:"scopes,omitempty"`
+	// RedirectPath defines the path where the provider redirects the users after their authentication.
+	// Default: /oauth2/callback.
+	RedirectPath string `json:"redirectPath,omitempty"`
+	// LogoutPath defines the path which logs the users out.
+	// Default: /oauth2/logout.
+	LogoutPath string `json:"logoutPath,omitempty"`
+	// PostLogoutRedirectURL defines the URL where the users are redirected after their logout.
+	PostLogoutRedirectURL string `json:"postLogoutRedirectUrl,omitempty"`
+	// Session defines the configuration of the session cookie.
+	Session *OIDCSession `json:"session,omitempty"`
+	// ForwardHeaders defines the headers to set on the forwarded request, keyed by header name, from the claims of the ID token.
+	// It replaces any existing conflicting headers.
+	ForwardHeaders map[string]string `json:"forwardHeaders,omitempty"`
+	// ForwardAccessToken defines whether to forward the access token in the Authorization header of the request.
+	ForwardAccessToken bool `json:"forwardAccessToken,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDCSession holds the OIDC session cookie configuration.
+type OIDCSession struct {
+	// Name defines the session cookie name.
+	// Default: traefik_oidc.
+	Name string `json:"name,omitempty"`
+	// Path defines the session cookie path.
+	// Default: /.
+	Path string `json:"path,omitempty"`
+	// Domain defines the session cookie domain.
+	Domain string `json:"domain,omitempty"`
+	// Secure defines whether the session cookie can only be transmitted over an encrypted connection (i.e. HTTPS).
+	Secure bool `json:"secure,omitempty"`
+	// SameSite defines the same site policy of the session cookie.
+	// More info: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
+	// Default: lax.
+	SameSite string `json:"sameSite,omitempty"`
+	// MaxAge indicates the number of seconds until the session cookie expires.
+	// When set to zero, the cookie expires when the browser is closed.
+	MaxAge int `json:"maxAge,omitempty"`
+}
+
 // ClientTLS holds the client TLS configuration.
 type ClientTLS struct {
 	// CASecret is the name of the referenced Kubernetes Secret containing the CA to validate the server certificate.
@@ -197,6 +311,50 @@ type RateLimit struct {
 	// If several strategies are defined at the same time, an error will be raised.
 	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
 	SourceCriterion *dynamic.SourceCriterion `json:"sourceCriterion,omitempty"`
+	// Redis defines the Redis server storing the token buckets, to enforce the rate limit across several Traefik instances.
+	// If not set, the token buckets are stored in memory.
+	Redis *Redis `json:"redis,omitempty"`
+	// SendHeaders defines whether to add the RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers to the responses,
+	// to report the state of the token bucket of the source.
+	SendHeaders bool `json:"sendHeaders,omitempty"`
+	// Tiers defines additional rate limits, each with its own source criterion and rate, enforced along with the one above.
+	// A request is rejected as soon as one of the rate limits is exceeded.
+	Tiers []RateLimitTier `json:"tiers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RateLimitTier holds a rate limit enforced by a RateLimit middleware along with its main rate limit.
+type RateLimitTier struct {
+	// Name is the name of the tier, reported in the X-RateLimit-Tier header when the tier rejects a request.
+	Name string `json:"name"`
+	// Average is the maximum rate, by default in requests/s, allowed for the given source.
+	Average int64 `json:"average,omitempty"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period *intstr.IntOrString `json:"period,omitempty"`
+	// Burst is the maximum number of requests allowed to arrive in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst *int64 `json:"burst,omitempty"`
+	// SourceCriterion defines what criterion is used to group requests as originating from a common source.
+	// If none are set, the default is to use the request's remote address field (as an ipStrategy).
+	SourceCriterion *dynamic.SourceCriterion `json:"sourceCriterion,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Redis holds the Redis configuration.
+type Redis struct {
+	// Endpoints defines the endpoints of the Redis server(s).
+	// It defaults to 127.0.0.1:6379.
+	Endpoints []string `json:"endpoints,omitempty"`
+	// TLS defines the configuration used to secure the connection to Redis.
+	TLS *ClientTLS `json:"tls,omitempty"`
+	// Secret is the name of the referenced Kubernetes Secret containing the credentials used to authenticate with Redis.
+	// The credentials are extracted from the keys `username` and `password`.
+	Secret string `json:"secret,omitempty"`
+	// DB defines the database selected after connecting to Redis.
+	DB int `json:"db,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -216,6 +374,43 @@ type Retry struct {
 	InitialInterval intstr.IntOrString `json:"initialInterval,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+
+// IPDenyList holds the IP denylist middleware configuration.
+// This middleware refuses requests based on the client IP.
+// The denied ranges cannot be loaded from files and URLs with the Kubernetes CRD.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/ipdenylist/
+type IPDenyList struct {
+	// SourceRange defines the set of denied IPs (or ranges of denied IPs by using CIDR notation).
+	SourceRange []string            `json:"sourceRange,omitempty"`
+	IPStrategy  *dynamic.IPStrategy `json:"ipStrategy,omitempty"`
+	// RejectStatusCode defines the HTTP status code used for refused requests.
+	// If not set, the default is 403 (Forbidden).
+	RejectStatusCode int `json:"rejectStatusCode,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// WAF holds the web application firewall middleware configuration.
+// This middleware evaluates SecLang rules, e.g. the OWASP Core Rule Set, on the request headers, query, and body.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/waf/
+type WAF struct {
+	// Rules defines the SecLang directives, e.g. SecRule or SecAction directives, one per line.
+	Rules string `json:"rules,omitempty"`
+	// RulesSecret is the name of the referenced Kubernetes Secret containing SecLang directives,
+	// which are loaded before the Rules option, in the alphabetical order of the Secret keys.
+	RulesSecret string `json:"rulesSecret,omitempty"`
+	// DataSecret is the name of the referenced Kubernetes Secret containing the data files read by the @pmFromFile operators,
+	// whose keys are the file names, as the rules cannot read the files of the local filesystem.
+	DataSecret string `json:"dataSecret,omitempty"`
+	// DetectionOnly defines whether the requests matching a disruptive rule are only logged, instead of being blocked.
+	DetectionOnly bool `json:"detectionOnly,omitempty"`
+	// MaxRequestBodyBytes defines the maximum size (in bytes) of a request body inspected by the rules.
+	// The requests with a larger body are rejected with a 413 (Request Entity Too Large) response, unless in detection only mode.
+	// Default: 1048576 (1Mi).
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // MiddlewareList is a collection of Middleware resources.
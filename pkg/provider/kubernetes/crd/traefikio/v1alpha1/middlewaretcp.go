@@ -34,6 +34,28 @@ type MiddlewareTCPSpec struct {
 	// This middleware accepts/refuses connections based on the client IP.
 	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ipallowlist/
 	IPAllowList *dynamic.TCPIPAllowList `json:"ipAllowList,omitempty"`
+	// IPDenyList defines the IPDenyList middleware configuration.
+	// This middleware refuses connections based on the client IP.
+	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ipdenylist/
+	IPDenyList *TCPIPDenyList `json:"ipDenyList,omitempty"`
+	// RateLimit defines the RateLimit middleware configuration.
+	// This middleware limits the rate of new connections for each client IP.
+	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ratelimit/
+	RateLimit *dynamic.TCPRateLimit `json:"rateLimit,omitempty"`
+	// BandwidthLimit defines the BandwidthLimit middleware configuration.
+	// This middleware throttles the connections, for each connection and for each client IP.
+	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/bandwidthlimit/
+	BandwidthLimit *dynamic.TCPBandwidthLimit `json:"bandwidthLimit,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPDenyList holds the TCP IPDenyList middleware configuration.
+// The denied ranges cannot be loaded from files and URLs with the Kubernetes CRD.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ipdenylist/
+type TCPIPDenyList struct {
+	// SourceRange defines the denied IPs (or ranges of denied IPs by using CIDR notation).
+	SourceRange []string `json:"sourceRange,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1,7 +1,6 @@
 package v1alpha1
 
 import (
-	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -81,5 +80,5 @@ type WeightedRoundRobin struct {
 	Services []Service `json:"services,omitempty"`
 	// Sticky defines whether sticky sessions are enabled.
 	// More info: https://doc.traefik.io/traefik/v3.0/routing/providers/kubernetes-crd/#stickiness-and-load-balancing
-	Sticky *dynamic.Sticky `json:"sticky,omitempty"`
+	Sticky *Sticky `json:"sticky,omitempty"`
 }
@@ -213,6 +213,8 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 		TCP: &dynamic.TCPConfiguration{},
 	}
 
+	localities := k8s.LoadNodeLocalities(ctx, client)
+
 	var ingressClasses []*netv1.IngressClass
 
 	if !p.DisableIngressClassLookup {
@@ -256,7 +258,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				continue
 			}
 
-			service, err := p.loadService(client, ingress.Namespace, *ingress.Spec.DefaultBackend)
+			service, err := p.loadService(client, localities, ingress.Namespace, *ingress.Spec.DefaultBackend)
 			if err != nil {
 				logger.Error().
 					Str("serviceName", ingress.Spec.DefaultBackend.Service.Name).
@@ -304,7 +306,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			}
 
 			for _, pa := range rule.HTTP.Paths {
-				service, err := p.loadService(client, ingress.Namespace, pa.Backend)
+				service, err := p.loadService(client, localities, ingress.Namespace, pa.Backend)
 				if err != nil {
 					logger.Error().
 						Str("serviceName", pa.Backend.Service.Name).
@@ -525,7 +527,7 @@ func getTLSConfig(tlsConfigs map[string]*tls.CertAndStores) []*tls.CertAndStores
 	return configs
 }
 
-func (p *Provider) loadService(client Client, namespace string, backend netv1.IngressBackend) (*dynamic.Service, error) {
+func (p *Provider) loadService(client Client, localities map[string]k8s.Locality, namespace string, backend netv1.IngressBackend) (*dynamic.Service, error) {
 	if backend.Resource != nil {
 		// https://kubernetes.io/docs/concepts/services-networking/ingress/#resource-backend
 		return nil, errors.New("resource backends are not supported")
@@ -674,9 +676,17 @@ func (p *Provider) loadService(client Client, namespace string, backend netv1.In
 		for _, addr := range subset.Addresses {
 			hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(int(port)))
 
-			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.Server{
+			server := dynamic.Server{
 				URL: fmt.Sprintf("%s://%s", protocol, hostPort),
-			})
+			}
+
+			if addr.NodeName != nil {
+				locality := localities[*addr.NodeName]
+				server.Zone = locality.Zone
+				server.Region = locality.Region
+			}
+
+			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, server)
 		}
 	}
 
@@ -87,6 +87,43 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "Ingress with servers locality",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{},
+				HTTP: &dynamic.HTTPConfiguration{
+					Middlewares: map[string]*dynamic.Middleware{},
+					Routers: map[string]*dynamic.Router{
+						"testing-bar": {
+							Rule:    "PathPrefix(`/bar`)",
+							Service: "testing-service1-80",
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"testing-service1-80": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								PassHostHeader: Bool(true),
+								ResponseForwarding: &dynamic.ResponseForwarding{
+									FlushInterval: ptypes.Duration(100 * time.Millisecond),
+								},
+								Servers: []dynamic.Server{
+									{
+										URL:    "http://10.10.0.1:8080",
+										Zone:   "eu-west-1a",
+										Region: "eu-west-1",
+									},
+									{
+										URL:    "http://10.21.0.1:8080",
+										Zone:   "eu-west-1b",
+										Region: "eu-west-1",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "Ingress with annotations",
 			expected: &dynamic.Configuration{
@@ -1746,6 +1783,43 @@ func TestLoadConfigurationFromIngressesWithNodePortLB(t *testing.T) {
 	}
 }
 
+func TestLoadConfigurationFromIngressesWithNodesError(t *testing.T) {
+	clientMock := newClientMock(generateTestFilename("Ingress with servers locality"))
+	clientMock.apiNodesError = errors.New("failed to list the nodes")
+
+	p := Provider{}
+	conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
+
+	expected := &dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{},
+		HTTP: &dynamic.HTTPConfiguration{
+			Middlewares: map[string]*dynamic.Middleware{},
+			Routers: map[string]*dynamic.Router{
+				"testing-bar": {
+					Rule:    "PathPrefix(`/bar`)",
+					Service: "testing-service1-80",
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"testing-service1-80": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						PassHostHeader: Bool(true),
+						ResponseForwarding: &dynamic.ResponseForwarding{
+							FlushInterval: ptypes.Duration(100 * time.Millisecond),
+						},
+						Servers: []dynamic.Server{
+							{URL: "http://10.10.0.1:8080"},
+							{URL: "http://10.21.0.1:8080"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, conf)
+}
+
 func generateTestFilename(desc string) string {
 	return filepath.Join("fixtures", strings.ReplaceAll(desc, " ", "-")+".yml")
 }
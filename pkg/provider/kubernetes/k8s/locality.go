@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Locality holds the zone and the region of a node.
+type Locality struct {
+	Zone   string
+	Region string
+}
+
+// NodeLister lists the nodes of the cluster.
+type NodeLister interface {
+	GetNodes() ([]*corev1.Node, bool, error)
+}
+
+// LoadNodeLocalities lists the nodes once and returns their locality, keyed by node name.
+// The locality of the servers is only used by the locality-aware load balancing,
+// so a failure to list the nodes is logged and leaves the servers without locality,
+// instead of failing the services.
+func LoadNodeLocalities(ctx context.Context, client NodeLister) map[string]Locality {
+	nodes, _, err := client.GetNodes()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Unable to list the nodes, the servers will have no zone and region")
+		return map[string]Locality{}
+	}
+
+	return NodeLocalities(nodes)
+}
+
+// NodeLocalities returns the locality of the given nodes, keyed by node name,
+// as defined by their well-known topology labels.
+func NodeLocalities(nodes []*corev1.Node) map[string]Locality {
+	localities := make(map[string]Locality)
+	for _, node := range nodes {
+		localities[node.Name] = Locality{
+			Zone:   node.Labels[corev1.LabelTopologyZone],
+			Region: node.Labels[corev1.LabelTopologyRegion],
+		}
+	}
+
+	return localities
+}
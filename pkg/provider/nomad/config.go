@@ -257,6 +257,15 @@ func (p *Provider) addServer(i item, lb *dynamic.ServersLoadBalancer) error {
 	lb.Servers[0].Scheme = ""
 	lb.Servers[0].URL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(i.Address, port))
 
+	// The Nomad datacenters are the zones of the Nomad regions.
+	if lb.Servers[0].Zone == "" {
+		lb.Servers[0].Zone = i.Datacenter
+	}
+
+	if lb.Servers[0].Region == "" {
+		lb.Servers[0].Region = i.Region
+	}
+
 	return nil
 }
 
@@ -2366,6 +2366,7 @@ func Test_buildConfig(t *testing.T) {
 					ID:         "1",
 					Node:       "Node1",
 					Datacenter: "dc1",
+					Region:     "global",
 					Name:       "Test",
 					Namespace:  "ns",
 					Tags:       []string{},
@@ -2377,6 +2378,7 @@ func Test_buildConfig(t *testing.T) {
 					ID:         "2",
 					Node:       "Node1",
 					Datacenter: "dc1",
+					Region:     "global",
 					Name:       "Test",
 					Namespace:  "ns",
 					Tags: []string{
@@ -2420,7 +2422,9 @@ func Test_buildConfig(t *testing.T) {
 							LoadBalancer: &dynamic.ServersLoadBalancer{
 								Servers: []dynamic.Server{
 									{
-										URL: "http://127.0.0.1:80",
+										URL:    "http://127.0.0.1:80",
+										Zone:   "dc1",
+										Region: "global",
 									},
 								},
 								PassHostHeader: Bool(true),
@@ -2433,7 +2437,9 @@ func Test_buildConfig(t *testing.T) {
 							LoadBalancer: &dynamic.ServersLoadBalancer{
 								Servers: []dynamic.Server{
 									{
-										URL: "http://127.0.0.2:80",
+										URL:    "http://127.0.0.2:80",
+										Zone:   "dc1",
+										Region: "global",
 									},
 								},
 								PassHostHeader: Bool(true),
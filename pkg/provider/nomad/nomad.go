@@ -40,7 +40,8 @@ type item struct {
 	Name       string   // service name
 	Namespace  string   // service namespace
 	Node       string   // node ID
-	Datacenter string   // region
+	Datacenter string   // datacenter
+	Region     string   // region
 	Address    string   // service address
 	Port       int      // service port
 	Tags       []string // service tags
@@ -244,6 +245,8 @@ func (p *Provider) getNomadServiceData(ctx context.Context) ([]item, error) {
 		return nil, err
 	}
 
+	region := p.getRegion(ctx)
+
 	var items []item
 
 	for _, stub := range stubs {
@@ -279,6 +282,7 @@ func (p *Provider) getNomadServiceData(ctx context.Context) ([]item, error) {
 					Namespace:  i.Namespace,
 					Node:       i.NodeID,
 					Datacenter: i.Datacenter,
+					Region:     region,
 					Address:    i.Address,
 					Port:       i.Port,
 					Tags:       i.Tags,
@@ -291,6 +295,22 @@ func (p *Provider) getNomadServiceData(ctx context.Context) ([]item, error) {
 	return items, nil
 }
 
+// getRegion returns the region of the services,
+// which is the configured Nomad region, or else the region of the local agent.
+func (p *Provider) getRegion(ctx context.Context) string {
+	if p.Endpoint.Region != "" {
+		return p.Endpoint.Region
+	}
+
+	region, err := p.client.Agent().Region()
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Unable to get the region of the Nomad agent, the servers will have no region")
+		return ""
+	}
+
+	return region
+}
+
 // getExtraConf returns a configuration with settings which are not part of the dynamic configuration (e.g. "<prefix>.enable").
 func (p *Provider) getExtraConf(tags []string) configuration {
 	labels := tagsToLabels(tags, p.Prefix)
@@ -135,6 +135,8 @@ func TestProvider_SetDefaults_Endpoint(t *testing.T) {
 func Test_getNomadServiceData(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case strings.HasSuffix(r.RequestURI, "/v1/agent/self"):
+			_, _ = w.Write([]byte(agentSelf))
 		case strings.HasSuffix(r.RequestURI, "/v1/services"):
 			_, _ = w.Write([]byte(services))
 		case strings.HasSuffix(r.RequestURI, "/v1/service/redis"):
@@ -159,8 +161,24 @@ func Test_getNomadServiceData(t *testing.T) {
 	items, err := p.getNomadServiceData(context.TODO())
 	require.NoError(t, err)
 	require.Len(t, items, 2)
+
+	for _, i := range items {
+		assert.Equal(t, "eu", i.Region)
+	}
 }
 
+const agentSelf = `
+{
+  "config": {
+    "Datacenter": "dc1",
+    "Region": "eu"
+  },
+  "member": {
+    "Name": "node1"
+  }
+}
+`
+
 const services = `
 [
   {
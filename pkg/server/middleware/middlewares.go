@@ -9,20 +9,29 @@ import (
 	"strings"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/geoip"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v3/pkg/middlewares/auth"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bandwidthlimiter"
+	"github.com/traefik/traefik/v3/pkg/middlewares/bodyrewrite"
 	"github.com/traefik/traefik/v3/pkg/middlewares/buffering"
+	"github.com/traefik/traefik/v3/pkg/middlewares/cache"
 	"github.com/traefik/traefik/v3/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v3/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v3/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v3/pkg/middlewares/contenttype"
 	"github.com/traefik/traefik/v3/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v3/pkg/middlewares/decompress"
+	geoipmiddleware "github.com/traefik/traefik/v3/pkg/middlewares/geoip"
 	"github.com/traefik/traefik/v3/pkg/middlewares/grpcweb"
 	"github.com/traefik/traefik/v3/pkg/middlewares/headers"
 	"github.com/traefik/traefik/v3/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ipallowlist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/ipdenylist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ipwhitelist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/passtlsclientcert"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ratelimiter"
@@ -33,6 +42,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v3/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v3/pkg/middlewares/waf"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 )
 
@@ -44,9 +54,11 @@ const (
 
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	pluginBuilder  PluginsBuilder
-	serviceBuilder serviceBuilder
+	configs         map[string]*runtime.MiddlewareInfo
+	pluginBuilder   PluginsBuilder
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
+	geoIPDatabase   *geoip.Database
 }
 
 type serviceBuilder interface {
@@ -54,8 +66,32 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, metricsRegistry metrics.Registry, geoIPDatabase *geoip.Database) *Builder {
+	// The stores of the cache middlewares, the denied ranges of the IPDenyList middlewares,
+	// and the Redis clients of the rate limit middlewares, which are not in the configuration anymore are dropped.
+	cacheNames := make(map[string]struct{})
+	ipDenyListNames := make(map[string]struct{})
+	redisRateLimitNames := make(map[string]struct{})
+	for name, config := range configs {
+		if config == nil || config.Middleware == nil {
+			continue
+		}
+
+		if config.Cache != nil {
+			cacheNames[name] = struct{}{}
+		}
+		if config.IPDenyList != nil {
+			ipDenyListNames[name] = struct{}{}
+		}
+		if config.RateLimit != nil && config.RateLimit.Redis != nil {
+			redisRateLimitNames[name] = struct{}{}
+		}
+	}
+	cache.Retain(cacheNames)
+	ipdenylist.Retain(ipDenyListNames)
+	ratelimiter.Retain(redisRateLimitNames)
+
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, metricsRegistry: metricsRegistry, geoIPDatabase: geoIPDatabase}
 }
 
 // BuildChain creates a middleware chain.
@@ -142,6 +178,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -178,6 +224,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Decompress
+	if config.Decompress != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return decompress.New(ctx, next, *config.Decompress, middlewareName)
+		}
+	}
+
+	// BodyRewrite
+	if config.BodyRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return bodyrewrite.New(ctx, next, *config.BodyRewrite, middlewareName)
+		}
+	}
+
+	// WAF
+	if config.WAF != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return waf.New(ctx, next, *config.WAF, middlewareName)
+		}
+	}
+
 	// ContentType
 	if config.ContentType != nil {
 		if middleware != nil {
@@ -218,6 +294,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
+	// OIDC
+	if config.OIDC != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDC, middlewareName)
+		}
+	}
+
 	// GrpcWeb
 	if config.GrpcWeb != nil {
 		if middleware != nil {
@@ -261,6 +357,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// IPDenyList
+	if config.IPDenyList != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return ipdenylist.New(ctx, next, *config.IPDenyList, middlewareName)
+		}
+	}
+
+	// GeoIP
+	if config.GeoIP != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return geoipmiddleware.New(ctx, next, *config.GeoIP, b.geoIPDatabase, middlewareName)
+		}
+	}
+
 	// InFlightReq
 	if config.InFlightReq != nil {
 		if middleware != nil {
@@ -271,6 +387,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// BandwidthLimit
+	if config.BandwidthLimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return bandwidthlimiter.New(ctx, next, *config.BandwidthLimit, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -287,7 +413,12 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+			var rejectionsCounter gokitmetrics.Counter
+			if b.metricsRegistry != nil {
+				rejectionsCounter = b.metricsRegistry.RateLimitRejectionsCounter()
+			}
+
+			return ratelimiter.New(ctx, next, *config.RateLimit, rejectionsCounter, middlewareName)
 		}
 	}
 
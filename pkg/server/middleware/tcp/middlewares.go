@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"strings"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/bandwidthlimiter"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/inflightconn"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipallowlist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipdenylist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipwhitelist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ratelimiter"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -22,12 +27,22 @@ const (
 
 // Builder the middleware builder.
 type Builder struct {
-	configs map[string]*runtime.TCPMiddlewareInfo
+	configs         map[string]*runtime.TCPMiddlewareInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo) *Builder {
-	return &Builder{configs: configs}
+func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo, metricsRegistry metrics.Registry) *Builder {
+	// The denied ranges of the IPDenyList middlewares which are not in the configuration anymore are dropped.
+	ipDenyListNames := make(map[string]struct{})
+	for name, config := range configs {
+		if config != nil && config.TCPMiddleware != nil && config.IPDenyList != nil {
+			ipDenyListNames[name] = struct{}{}
+		}
+	}
+	ipdenylist.Retain(ipDenyListNames)
+
+	return &Builder{configs: configs, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain.
@@ -112,6 +127,32 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// IPDenyList
+	if config.IPDenyList != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ipdenylist.New(ctx, next, *config.IPDenyList, middlewareName)
+		}
+	}
+
+	// RateLimit
+	if config.RateLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			var rejectionsCounter gokitmetrics.Counter
+			if b.metricsRegistry != nil {
+				rejectionsCounter = b.metricsRegistry.TCPRateLimitRejectionsCounter()
+			}
+
+			return ratelimiter.New(ctx, next, *config.RateLimit, rejectionsCounter, middlewareName)
+		}
+	}
+
+	// BandwidthLimit
+	if config.BandwidthLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return bandwidthlimiter.New(ctx, next, *config.BandwidthLimit, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
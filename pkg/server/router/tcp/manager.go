@@ -9,6 +9,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares/snicheck"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
@@ -30,6 +31,7 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	geoIPDatabase *geoip.Database,
 ) *Manager {
 	return &Manager{
 		serviceManager:     serviceManager,
@@ -37,6 +39,7 @@ func NewManager(conf *runtime.Configuration,
 		httpHandlers:       httpHandlers,
 		httpsHandlers:      httpsHandlers,
 		tlsManager:         tlsManager,
+		geoIPDatabase:      geoIPDatabase,
 		conf:               conf,
 	}
 }
@@ -48,6 +51,7 @@ type Manager struct {
 	httpHandlers       map[string]http.Handler
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
+	geoIPDatabase      *geoip.Database
 	conf               *runtime.Configuration
 }
 
@@ -98,7 +102,7 @@ type nameAndConfig struct {
 
 func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*Router, error) {
 	// Build a new Router.
-	router, err := NewRouter()
+	router, err := NewRouter(m.geoIPDatabase)
 	if err != nil {
 		return nil, err
 	}
@@ -164,3 +164,9 @@ func (c *postgresConn) Write(p []byte) (n int, err error) {
 
 	return 1, nil
 }
+
+// NetConn returns the underlying connection,
+// e.g. to get the PROXY protocol header received with it.
+func (c *postgresConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
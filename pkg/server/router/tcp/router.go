@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -45,17 +46,22 @@ type Router struct {
 }
 
 // NewRouter returns a new TCP router.
-func NewRouter() (*Router, error) {
+// The given GeoIP database, which can be nil, is used by the ClientCountry and ClientASN matchers of the TCP routes.
+func NewRouter(geoIPDatabase *geoip.Database) (*Router, error) {
 	muxTCP, err := tcpmuxer.NewMuxer()
 	if err != nil {
 		return nil, err
 	}
 
+	muxTCP.SetGeoIPDatabase(geoIPDatabase)
+
 	muxTCPTLS, err := tcpmuxer.NewMuxer()
 	if err != nil {
 		return nil, err
 	}
 
+	muxTCPTLS.SetGeoIPDatabase(geoIPDatabase)
+
 	muxHTTPS, err := tcpmuxer.NewMuxer()
 	if err != nil {
 		return nil, err
@@ -320,6 +326,11 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
+// NetConn returns the underlying connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 type clientHello struct {
 	serverName string   // SNI server name
 	protos     []string // ALPN protocols list
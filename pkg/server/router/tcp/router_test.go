@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
@@ -164,7 +165,7 @@ func Test_Routing(t *testing.T) {
 
 	dialerManager := tcp2.NewDialerManager(nil)
 	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
-	serviceManager := tcp.NewManager(conf, dialerManager)
+	serviceManager := tcp.NewManager(conf, dialerManager, metrics.NewVoidRegistry(), tcp2.NewStickyTables())
 
 	// Creates the tlsManager and defines the TLS 1.0 and 1.2 TLSOptions.
 	tlsManager := traefiktls.NewManager()
@@ -187,10 +188,10 @@ func Test_Routing(t *testing.T) {
 		},
 		[]*traefiktls.CertAndStores{})
 
-	middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+	middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares, nil)
 
 	manager := NewManager(conf, serviceManager, middlewaresBuilder,
-		nil, nil, tlsManager)
+		nil, nil, tlsManager, nil)
 
 	type checkCase struct {
 		checkRouter
@@ -943,7 +944,7 @@ func checkHTTPSTLS12(addr string, timeout time.Duration) error {
 }
 
 func TestPostgres(t *testing.T) {
-	router, err := NewRouter()
+	router, err := NewRouter(nil)
 	require.NoError(t, err)
 
 	// This test requires to have a TLS route, but does not actually check the
@@ -973,6 +974,16 @@ func TestPostgres(t *testing.T) {
 	require.Equal(t, []byte("OK"), b)
 }
 
+func TestPostgresConn_NetConn(t *testing.T) {
+	mockConn := NewMockConn()
+
+	// The wrapped connection is reachable, e.g. to get the PROXY protocol TLVs received with it.
+	var conn net.Conn = &postgresConn{WriteCloser: mockConn}
+	netConn, ok := conn.(interface{ NetConn() net.Conn })
+	require.True(t, ok)
+	assert.Equal(t, mockConn, netConn.NetConn())
+}
+
 func NewMockConn() *MockConn {
 	return &MockConn{
 		dataRead:  make(chan []byte),
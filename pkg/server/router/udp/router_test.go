@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/service/udp"
+	udp2 "github.com/traefik/traefik/v3/pkg/udp"
 )
 
 func TestRuntimeConfiguration(t *testing.T) {
@@ -117,7 +119,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPServices: test.serviceConfig,
 				UDPRouters:  test.routerConfig,
 			}
-			serviceManager := udp.NewManager(conf)
+			serviceManager := udp.NewManager(conf, metrics.NewVoidRegistry(), udp2.NewStickyTables())
 			routerManager := NewManager(conf, serviceManager)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
@@ -6,12 +6,14 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/geoip"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v3/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 	udprouter "github.com/traefik/traefik/v3/pkg/server/router/udp"
 	"github.com/traefik/traefik/v3/pkg/server/service"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
 	tcpsvc "github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	udpsvc "github.com/traefik/traefik/v3/pkg/server/service/udp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -33,12 +35,22 @@ type RouterFactory struct {
 
 	dialerManager *tcp.DialerManager
 
+	geoIPDatabase *geoip.Database
+
+	// httpSlowStartTables keeps the slow start of the servers of the HTTP services across the configuration reloads.
+	httpSlowStartTables *wrr.SlowStartTables
+	// tcpStickyTables keeps the client bindings of the sticky TCP services across the configuration reloads.
+	tcpStickyTables *tcp.StickyTables
+	// udpStickyTables keeps the client bindings of the sticky UDP services across the configuration reloads.
+	udpStickyTables *udp.StickyTables
+
 	cancelPrevState func()
 }
 
 // NewRouterFactory creates a new RouterFactory.
 func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager,
 	observabilityMgr *middleware.ObservabilityMgr, pluginBuilder middleware.PluginsBuilder, dialerManager *tcp.DialerManager,
+	geoIPDatabase *geoip.Database,
 ) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
 	for name, cfg := range staticConfiguration.EntryPoints {
@@ -56,13 +68,17 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	}
 
 	return &RouterFactory{
-		entryPointsTCP:   entryPointsTCP,
-		entryPointsUDP:   entryPointsUDP,
-		managerFactory:   managerFactory,
-		observabilityMgr: observabilityMgr,
-		tlsManager:       tlsManager,
-		pluginBuilder:    pluginBuilder,
-		dialerManager:    dialerManager,
+		entryPointsTCP:      entryPointsTCP,
+		entryPointsUDP:      entryPointsUDP,
+		managerFactory:      managerFactory,
+		observabilityMgr:    observabilityMgr,
+		tlsManager:          tlsManager,
+		pluginBuilder:       pluginBuilder,
+		dialerManager:       dialerManager,
+		geoIPDatabase:       geoIPDatabase,
+		httpSlowStartTables: wrr.NewSlowStartTables(),
+		tcpStickyTables:     tcp.NewStickyTables(),
+		udpStickyTables:     udp.NewStickyTables(),
 	}
 }
 
@@ -76,11 +92,11 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	ctx, f.cancelPrevState = context.WithCancel(context.Background())
 
 	// HTTP
-	serviceManager := f.managerFactory.Build(rtConf)
+	serviceManager := f.managerFactory.Build(rtConf, f.httpSlowStartTables)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.observabilityMgr.MetricsRegistry(), f.geoIPDatabase)
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.observabilityMgr, f.tlsManager)
+	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.observabilityMgr, f.tlsManager, f.geoIPDatabase)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
@@ -88,18 +104,22 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	serviceManager.LaunchHealthCheck(ctx)
 
 	// TCP
-	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager)
+	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager, f.observabilityMgr.MetricsRegistry(), f.tcpStickyTables)
 
-	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares)
+	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, f.observabilityMgr.MetricsRegistry())
 
-	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager, f.geoIPDatabase)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
+	svcTCPManager.LaunchHealthCheck(ctx)
+
 	// UDP
-	svcUDPManager := udpsvc.NewManager(rtConf)
+	svcUDPManager := udpsvc.NewManager(rtConf, f.observabilityMgr.MetricsRegistry(), f.udpStickyTables)
 	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	svcUDPManager.LaunchHealthCheck(ctx)
+
 	rtConf.PopulateUsedBy()
 
 	return routersTCP, routersUDP
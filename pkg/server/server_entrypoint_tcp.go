@@ -381,6 +381,11 @@ func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// NetConn returns the wrapped connection.
+func (c *writeCloserWrapper) NetConn() net.Conn {
+	return c.Conn
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
@@ -638,6 +643,10 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	serverHTTP.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
 		// This adds an empty struct in order to store a RoundTripper in the ConnContext in case of Kerberos or NTLM.
 		ctx = service.AddTransportOnContext(ctx)
+		// This adds the information carried by the TLVs of the PROXY protocol header, if any, for the access logs.
+		if info, ok := tcp.GetProxyProtocolInfo(c); ok {
+			ctx = tcp.AddProxyProtocolInfoOnContext(ctx, info)
+		}
 		if prevConnContext != nil {
 			return prevConnContext(ctx, c)
 		}
@@ -693,6 +702,11 @@ func (t *trackedConnection) Close() error {
 	return t.WriteCloser.Close()
 }
 
+// NetConn returns the wrapped connection.
+func (t *trackedConnection) NetConn() net.Conn {
+	return t.WriteCloser
+}
+
 // This function is inspired by http.AllowQuerySemicolons.
 func encodeQuerySemicolons(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -96,7 +96,7 @@ func TestHTTP3AdvertisedPort(t *testing.T) {
 	}, nil, nil)
 	require.NoError(t, err)
 
-	router, err := tcprouter.NewRouter()
+	router, err := tcprouter.NewRouter(nil)
 	require.NoError(t, err)
 
 	router.AddHTTPTLSConfig("*", &tls.Config{
@@ -0,0 +1,239 @@
+package chash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+// virtualNodesPerWeight is the number of points a handler owns on the ring, per unit of weight.
+// The more points, the more even the distribution of the keys between the handlers.
+const virtualNodesPerWeight = 100
+
+type namedHandler struct {
+	http.Handler
+	name   string
+	weight int
+}
+
+type ringEntry struct {
+	hash    uint64
+	handler *namedHandler
+}
+
+// Balancer is a consistent hashing load balancer.
+// (https://en.wikipedia.org/wiki/Consistent_hashing)
+// Each handler owns a number of points, proportional to its weight, on a ring of hashes.
+// A request is forwarded to the handler owning the first point following the hash of the request key on the ring.
+// When a handler is added, removed, or marked as down, only the keys mapped to its points are remapped,
+// to the handler owning the next healthy point on the ring.
+type Balancer struct {
+	wantsHealthCheck bool
+	key              func(req *http.Request) string
+
+	handlersMu sync.RWMutex
+	// ring is the list of the handler points, sorted by hash once all the handlers are added.
+	ring []ringEntry
+	// ringSorted is whether the ring has been sorted since the last handler was added.
+	ringSorted bool
+	// status is a record of which child services of the Balancer are healthy.
+	// A service is initially marked as healthy when it is created via Add,
+	// and its status is later updated through the SetStatus method.
+	status *loadbalancer.Status
+}
+
+// New creates a new consistent hashing load balancer.
+func New(config *dynamic.ConsistentHash, wantHealthCheck bool) (*Balancer, error) {
+	key, err := newKeyExtractor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Balancer{
+		status:           loadbalancer.NewStatus(),
+		wantsHealthCheck: wantHealthCheck,
+		key:              key,
+	}, nil
+}
+
+// newKeyExtractor returns the function extracting the hash key from a request.
+// It returns an error if more than one criterion is provided.
+func newKeyExtractor(config *dynamic.ConsistentHash) (func(req *http.Request) string, error) {
+	if config == nil {
+		config = &dynamic.ConsistentHash{}
+	}
+
+	var count int
+	for _, set := range []bool{config.IPStrategy != nil, config.RequestHeaderName != "", config.CookieName != "", config.QueryParameterName != ""} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return nil, errors.New("ipStrategy, requestHeaderName, cookieName and queryParameterName are mutually exclusive")
+	}
+
+	// The client IP is used as the key when no other criterion is set,
+	// and as a fallback when the request does not carry the configured key.
+	remoteAddr := &ip.RemoteAddrStrategy{}
+
+	var extract func(req *http.Request) string
+	switch {
+	case config.RequestHeaderName != "":
+		extract = func(req *http.Request) string {
+			return req.Header.Get(config.RequestHeaderName)
+		}
+
+	case config.CookieName != "":
+		extract = func(req *http.Request) string {
+			cookie, err := req.Cookie(config.CookieName)
+			if err != nil {
+				return ""
+			}
+			return cookie.Value
+		}
+
+	case config.QueryParameterName != "":
+		extract = func(req *http.Request) string {
+			return req.URL.Query().Get(config.QueryParameterName)
+		}
+
+	default:
+		strategy, err := config.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+		extract = strategy.GetIP
+	}
+
+	return func(req *http.Request) string {
+		if key := extract(req); key != "" {
+			return key
+		}
+		return remoteAddr.GetIP(req)
+	}, nil
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	b.status.Set(ctx, childName, up)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this consistent hashing service")
+	}
+	b.status.RegisterUpdater(fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer(key string) (*namedHandler, error) {
+	b.sortRing()
+
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	if len(b.ring) == 0 || !b.status.AnyUp() {
+		return nil, errNoAvailableServer
+	}
+
+	hash := xxhash.Sum64String(key)
+	start := sort.Search(len(b.ring), func(i int) bool {
+		return b.ring[i].hash >= hash
+	})
+
+	for i := range b.ring {
+		entry := b.ring[(start+i)%len(b.ring)]
+		if b.status.IsUp(entry.handler.name) {
+			log.Debug().Msgf("Service selected by consistent hashing: %s", entry.handler.name)
+			return entry.handler, nil
+		}
+	}
+
+	return nil, errNoAvailableServer
+}
+
+// sortRing sorts the ring once all the handlers are added,
+// rather than on each Add call, which would be quadratic when building large pools.
+func (b *Balancer) sortRing() {
+	b.handlersMu.RLock()
+	sorted := b.ringSorted
+	b.handlersMu.RUnlock()
+
+	if sorted {
+		return
+	}
+
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	if b.ringSorted {
+		return
+	}
+
+	sort.Slice(b.ring, func(i, j int) bool {
+		return b.ring[i].hash < b.ring[j].hash
+	})
+	b.ringSorted = true
+}
+
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server, err := b.nextServer(b.key(req))
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(w, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	server.ServeHTTP(w, req)
+}
+
+// Add adds a handler.
+// A handler with a non-positive weight is ignored.
+func (b *Balancer) Add(name string, handler http.Handler, weight *int) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: w}
+
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	for i := 0; i < w*virtualNodesPerWeight; i++ {
+		b.ring = append(b.ring, ringEntry{
+			hash:    xxhash.Sum64String(name + "#" + strconv.Itoa(i)),
+			handler: h,
+		})
+	}
+
+	b.ringSorted = false
+
+	b.status.Add(name)
+}
@@ -0,0 +1,299 @@
+package chash
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      *dynamic.ConsistentHash
+		expectError bool
+	}{
+		{
+			desc: "no configuration",
+		},
+		{
+			desc:   "request header",
+			config: &dynamic.ConsistentHash{RequestHeaderName: "X-User"},
+		},
+		{
+			desc:   "IP strategy",
+			config: &dynamic.ConsistentHash{IPStrategy: &dynamic.IPStrategy{Depth: 1}},
+		},
+		{
+			desc:        "invalid IP strategy",
+			config:      &dynamic.ConsistentHash{IPStrategy: &dynamic.IPStrategy{ExcludedIPs: []string{"foo"}}},
+			expectError: true,
+		},
+		{
+			desc: "several criteria",
+			config: &dynamic.ConsistentHash{
+				CookieName:         "user",
+				QueryParameterName: "user",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.config, false)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBalancerKey(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *dynamic.ConsistentHash
+		request  func() *http.Request
+		expected string
+	}{
+		{
+			desc: "remote address by default",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				return req
+			},
+			expected: "10.0.0.1",
+		},
+		{
+			desc:   "IP strategy",
+			config: &dynamic.ConsistentHash{IPStrategy: &dynamic.IPStrategy{Depth: 1}},
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Forwarded-For", "10.0.0.2, 10.0.0.3")
+				return req
+			},
+			expected: "10.0.0.3",
+		},
+		{
+			desc:   "request header",
+			config: &dynamic.ConsistentHash{RequestHeaderName: "X-User"},
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-User", "foo")
+				return req
+			},
+			expected: "foo",
+		},
+		{
+			desc:   "cookie",
+			config: &dynamic.ConsistentHash{CookieName: "user"},
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.AddCookie(&http.Cookie{Name: "user", Value: "bar"})
+				return req
+			},
+			expected: "bar",
+		},
+		{
+			desc:   "query parameter",
+			config: &dynamic.ConsistentHash{QueryParameterName: "user"},
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/?user=baz", nil)
+			},
+			expected: "baz",
+		},
+		{
+			desc:   "fallback on remote address",
+			config: &dynamic.ConsistentHash{RequestHeaderName: "X-User"},
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = "10.0.0.4:1234"
+				return req
+			},
+			expected: "10.0.0.4",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := New(test.config, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, balancer.key(test.request()))
+		})
+	}
+}
+
+func TestBalancerSameKeySameServer(t *testing.T) {
+	balancer, err := New(&dynamic.ConsistentHash{RequestHeaderName: "X-User"}, false)
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third"} {
+		balancer.Add(name, newHandler(name), Int(1))
+	}
+
+	for i := 0; i < 20; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+
+		first := serve(balancer, req)
+		for j := 0; j < 5; j++ {
+			assert.Equal(t, first, serve(balancer, req))
+		}
+	}
+}
+
+func TestBalancerDistribution(t *testing.T) {
+	balancer, err := New(&dynamic.ConsistentHash{RequestHeaderName: "X-User"}, false)
+	require.NoError(t, err)
+
+	balancer.Add("first", newHandler("first"), Int(3))
+	balancer.Add("second", newHandler("second"), Int(1))
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+		counts[serve(balancer, req)]++
+	}
+
+	assert.InDelta(t, 3000, counts["first"], 300)
+	assert.InDelta(t, 1000, counts["second"], 300)
+}
+
+func TestBalancerMinimalRemapping(t *testing.T) {
+	balancer, err := New(&dynamic.ConsistentHash{RequestHeaderName: "X-User"}, false)
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		balancer.Add(name, newHandler(name), Int(1))
+	}
+
+	const keys = 1000
+
+	before := make(map[int]string, keys)
+	for i := 0; i < keys; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+		before[i] = serve(balancer, req)
+	}
+
+	balancer.SetStatus(context.Background(), "second", false)
+
+	for i := 0; i < keys; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+		after := serve(balancer, req)
+
+		assert.NotEqual(t, "second", after)
+		if before[i] != "second" {
+			// Only the keys of the downed server are remapped.
+			assert.Equal(t, before[i], after)
+		}
+	}
+
+	balancer.SetStatus(context.Background(), "second", true)
+
+	for i := 0; i < keys; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+
+		// Keys go back to their original server when it recovers.
+		assert.Equal(t, before[i], serve(balancer, req))
+	}
+}
+
+func TestBalancerNoService(t *testing.T) {
+	balancer, err := New(nil, false)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+}
+
+func TestBalancerNoServiceUp(t *testing.T) {
+	balancer, err := New(nil, false)
+	require.NoError(t, err)
+
+	balancer.Add("first", newHandler("first"), Int(1))
+	balancer.SetStatus(context.Background(), "first", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+}
+
+func TestBalancerPropagate(t *testing.T) {
+	balancer, err := New(nil, true)
+	require.NoError(t, err)
+
+	balancer.Add("first", newHandler("first"), Int(1))
+	balancer.Add("second", newHandler("second"), Int(1))
+
+	var statuses []bool
+	err = balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "first", false)
+	balancer.SetStatus(context.Background(), "second", false)
+	balancer.SetStatus(context.Background(), "first", true)
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func Int(v int) *int { return &v }
+
+func newHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
+func serve(balancer *Balancer, req *http.Request) string {
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, req)
+	return recorder.Header().Get("server")
+}
+
+func TestBalancerAddOrder(t *testing.T) {
+	names := []string{"first", "second", "third", "fourth"}
+
+	balancer, err := New(&dynamic.ConsistentHash{RequestHeaderName: "X-User"}, false)
+	require.NoError(t, err)
+
+	reversed, err := New(&dynamic.ConsistentHash{RequestHeaderName: "X-User"}, false)
+	require.NoError(t, err)
+
+	for i := range names {
+		balancer.Add(names[i], newHandler(names[i]), Int(1))
+		reversed.Add(names[len(names)-1-i], newHandler(names[len(names)-1-i]), Int(1))
+	}
+
+	for i := 0; i < 100; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+
+		assert.Equal(t, serve(balancer, req), serve(reversed, req))
+	}
+}
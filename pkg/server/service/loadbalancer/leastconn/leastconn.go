@@ -0,0 +1,182 @@
+package leastconn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+type namedHandler struct {
+	http.Handler
+	name     string
+	weight   float64
+	inflight atomic.Int64
+}
+
+func (h *namedHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.inflight.Add(1)
+	defer h.inflight.Add(-1)
+
+	h.Handler.ServeHTTP(rw, req)
+}
+
+// load returns the number of in-flight requests of the handler, relative to its weight.
+func (h *namedHandler) load() float64 {
+	return float64(h.inflight.Load()) / h.weight
+}
+
+// Balancer is a least-connections load balancer.
+// Each pick selects, among the healthy handlers, the one with the fewest in-flight requests relative to its weight.
+// Ties are broken in a round-robin fashion, so that handlers with equal load share the traffic evenly.
+type Balancer struct {
+	wantsHealthCheck bool
+
+	handlersMu sync.RWMutex
+	handlers   []*namedHandler
+	// index is the position, in handlers, from which the next pick starts looking for the least loaded handler.
+	index int
+	// status is a record of which child services of the Balancer are healthy.
+	// A service is initially marked as healthy when it is created via Add,
+	// and its status is later updated through the SetStatus method.
+	status *loadbalancer.Status
+
+	sticky *loadbalancer.Sticky
+}
+
+// New creates a new least-connections load balancer.
+func New(sticky *dynamic.Sticky, wantHealthCheck bool) *Balancer {
+	return &Balancer{
+		status:           loadbalancer.NewStatus(),
+		wantsHealthCheck: wantHealthCheck,
+		sticky:           loadbalancer.NewSticky(sticky),
+	}
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	b.status.Set(ctx, childName, up)
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this least-connections service")
+	}
+	b.status.RegisterUpdater(fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer() (*namedHandler, error) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	if len(b.handlers) == 0 || !b.status.AnyUp() {
+		return nil, errNoAvailableServer
+	}
+
+	var (
+		selected     *namedHandler
+		selectedLoad float64
+		selectedIdx  int
+	)
+	for i := range b.handlers {
+		idx := (b.index + i) % len(b.handlers)
+		handler := b.handlers[idx]
+		if !b.status.IsUp(handler.name) {
+			continue
+		}
+
+		load := handler.load()
+		if selected == nil || load < selectedLoad {
+			selected = handler
+			selectedLoad = load
+			selectedIdx = idx
+		}
+	}
+
+	if selected == nil {
+		return nil, errNoAvailableServer
+	}
+
+	b.index = (selectedIdx + 1) % len(b.handlers)
+
+	log.Debug().Msgf("Service selected by least-connections: %s", selected.name)
+	return selected, nil
+}
+
+// isUp returns whether the given child is healthy.
+func (b *Balancer) isUp(name string) bool {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	return b.status.IsUp(name)
+}
+
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if b.sticky != nil {
+		h, err := b.sticky.StickyHandler(req, b.isUp)
+		if err != nil {
+			log.Warn().Err(err).Msg("Error while getting sticky handler")
+		}
+
+		if h != nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	server, err := b.nextServer()
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(w, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if b.sticky != nil {
+		b.sticky.WriteStickyCookie(w, server.name)
+	}
+
+	server.ServeHTTP(w, req)
+}
+
+// Add adds a handler.
+// A handler with a non-positive weight is ignored.
+func (b *Balancer) Add(name string, handler http.Handler, weight *int) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
+
+	b.handlersMu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.status.Add(name)
+	b.handlersMu.Unlock()
+
+	if b.sticky != nil {
+		// Registering the counting handler makes sticky requests count as in-flight requests too.
+		b.sticky.AddHandler(name, h, w)
+	}
+}
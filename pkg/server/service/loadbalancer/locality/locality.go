@@ -0,0 +1,230 @@
+package locality
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Tiers of the servers, ordered by locality.
+const (
+	// TierZone holds the servers of the same zone as Traefik.
+	TierZone = iota
+	// TierRegion holds the servers of the same region as Traefik.
+	TierRegion
+	// TierOther holds the other servers.
+	TierOther
+
+	tierCount
+)
+
+// Child is a load-balancer of the servers of a tier.
+type Child interface {
+	http.Handler
+
+	Add(name string, handler http.Handler, weight *int)
+	SetStatus(ctx context.Context, childName string, up bool)
+}
+
+type tier struct {
+	child Child
+	// weights holds the weight of the servers of the tier, keyed by server name.
+	weights     map[string]float64
+	totalWeight float64
+	// up holds the healthy servers of the tier.
+	up            map[string]struct{}
+	healthyWeight float64
+}
+
+// Balancer is a load-balancer which prefers the servers of the same zone as Traefik, then of the same region.
+// The requests spill over to the next tier of servers in proportion to the healthy capacity
+// of the preferred tier below the minimum healthy percentage:
+// with a minimum of 70%, a tier with 35% of healthy capacity receives half of the requests it is offered.
+type Balancer struct {
+	wantsHealthCheck bool
+	minHealthy       float64
+
+	mu    sync.RWMutex
+	tiers [tierCount]*tier
+	// tierOf holds the tier of the servers, keyed by server name.
+	tierOf func(name string) int
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+
+	// rand can be overridden for testing purposes.
+	rand func() float64
+}
+
+// New creates a new locality-aware load-balancer.
+// newChild creates the load-balancer of the servers of a tier, and tierOf returns the tier of the server with the given name.
+func New(minHealthyPercent int, wantsHealthCheck bool, newChild func() (Child, error), tierOf func(name string) int) (*Balancer, error) {
+	b := &Balancer{
+		wantsHealthCheck: wantsHealthCheck,
+		minHealthy:       float64(minHealthyPercent) / 100,
+		tierOf:           tierOf,
+		rand:             rand.Float64,
+	}
+
+	for i := range b.tiers {
+		child, err := newChild()
+		if err != nil {
+			return nil, err
+		}
+
+		b.tiers[i] = &tier{
+			child:   child,
+			weights: make(map[string]float64),
+			up:      make(map[string]struct{}),
+		}
+	}
+
+	return b, nil
+}
+
+// Add adds a handler to the tier of the given server.
+// A handler with a non-positive weight is ignored.
+func (b *Balancer) Add(name string, handler http.Handler, weight *int) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	t := b.tiers[b.tierIndex(name)]
+	t.child.Add(name, handler, weight)
+
+	b.mu.Lock()
+	t.weights[name] = float64(w)
+	t.totalWeight += float64(w)
+	t.up[name] = struct{}{}
+	t.healthyWeight += float64(w)
+	b.mu.Unlock()
+}
+
+func (b *Balancer) tierIndex(name string) int {
+	index := b.tierOf(name)
+	if index < 0 || index >= tierCount {
+		return TierOther
+	}
+
+	return index
+}
+
+// SetStatus sets on the balancer that its given child is now of the given status.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	t := b.tiers[b.tierIndex(childName)]
+	t.child.SetStatus(ctx, childName, up)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	weight, ok := t.weights[childName]
+	if !ok {
+		return
+	}
+
+	upBefore := b.isUp()
+
+	_, wasUp := t.up[childName]
+	switch {
+	case up && !wasUp:
+		t.up[childName] = struct{}{}
+		t.healthyWeight += weight
+	case !up && wasUp:
+		delete(t.up, childName)
+		t.healthyWeight -= weight
+	}
+
+	upAfter := b.isUp()
+	if upBefore == upAfter {
+		return
+	}
+
+	status := "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// isUp returns whether at least one server is healthy.
+func (b *Balancer) isUp() bool {
+	for _, t := range b.tiers {
+		if len(t.up) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this locality service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	t := b.nextTier()
+	if t == nil {
+		http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	t.child.ServeHTTP(rw, req)
+}
+
+// nextTier returns the tier which handles the next request, or nil if no server is healthy.
+func (b *Balancer) nextTier() *tier {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var last int
+	for i, t := range b.tiers {
+		if t.healthyWeight > 0 {
+			last = i
+		}
+	}
+
+	for i, t := range b.tiers {
+		if t.healthyWeight <= 0 {
+			continue
+		}
+
+		// The last tier with healthy servers receives all the requests spilling over.
+		if i == last || b.rand() < b.availability(t) {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// availability returns the share of the requests offered to the given tier that it receives.
+func (b *Balancer) availability(t *tier) float64 {
+	healthy := t.healthyWeight / t.totalWeight
+	if healthy >= b.minHealthy {
+		return 1
+	}
+
+	return healthy / b.minHealthy
+}
@@ -0,0 +1,138 @@
+package locality
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
+)
+
+func TestBalancer(t *testing.T) {
+	tiers := map[string]int{
+		"zone1":   TierZone,
+		"zone2":   TierZone,
+		"region1": TierRegion,
+		"other1":  TierOther,
+	}
+
+	testCases := []struct {
+		desc     string
+		down     []string
+		expected map[string]int
+	}{
+		{
+			desc:     "all servers up",
+			expected: map[string]int{"zone1": 500, "zone2": 500},
+		},
+		{
+			desc:     "zone capacity above the minimum",
+			down:     []string{"zone2"},
+			expected: map[string]int{"zone1": 1000},
+		},
+		{
+			desc:     "zone servers down",
+			down:     []string{"zone1", "zone2"},
+			expected: map[string]int{"region1": 1000},
+		},
+		{
+			desc:     "zone and region servers down",
+			down:     []string{"zone1", "zone2", "region1"},
+			expected: map[string]int{"other1": 1000},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := newBalancer(t, 50, tiers)
+
+			for _, name := range test.down {
+				balancer.SetStatus(context.Background(), name, false)
+			}
+
+			assert.Equal(t, test.expected, serve(balancer, 1000))
+		})
+	}
+}
+
+func TestBalancer_spillOver(t *testing.T) {
+	balancer := newBalancer(t, 100, map[string]int{
+		"zone1":   TierZone,
+		"zone2":   TierZone,
+		"region1": TierRegion,
+	})
+
+	// With half of its capacity healthy, the zone receives half of the requests.
+	balancer.SetStatus(context.Background(), "zone2", false)
+
+	counts := serve(balancer, 10000)
+	assert.InDelta(t, 5000, counts["zone1"], 300)
+	assert.InDelta(t, 5000, counts["region1"], 300)
+	assert.Zero(t, counts["zone2"])
+}
+
+func TestBalancer_noServerUp(t *testing.T) {
+	balancer := newBalancer(t, 70, map[string]int{"zone1": TierZone})
+	balancer.SetStatus(context.Background(), "zone1", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestBalancer_propagate(t *testing.T) {
+	balancer := newBalancer(t, 70, map[string]int{"zone1": TierZone, "other1": TierOther})
+
+	var statuses []bool
+	err := balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "zone1", false)
+	balancer.SetStatus(context.Background(), "other1", false)
+	balancer.SetStatus(context.Background(), "other1", true)
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func newBalancer(t *testing.T, minHealthyPercent int, tiers map[string]int) *Balancer {
+	t.Helper()
+
+	newChild := func() (Child, error) {
+		return wrr.New(nil, true), nil
+	}
+
+	balancer, err := New(minHealthyPercent, true, newChild, func(name string) int {
+		return tiers[name]
+	})
+	require.NoError(t, err)
+
+	for name := range tiers {
+		name := name
+		balancer.Add(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), nil)
+	}
+
+	return balancer
+}
+
+func serve(balancer *Balancer, requests int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < requests; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		counts[recorder.Header().Get("server")]++
+	}
+
+	return counts
+}
@@ -0,0 +1,214 @@
+package p2c
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+)
+
+type namedHandler struct {
+	http.Handler
+	name     string
+	weight   float64
+	inflight atomic.Int64
+}
+
+func (h *namedHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.inflight.Add(1)
+	defer h.inflight.Add(-1)
+
+	h.Handler.ServeHTTP(rw, req)
+}
+
+// load returns the number of in-flight requests of the handler, relative to its weight.
+func (h *namedHandler) load() float64 {
+	return float64(h.inflight.Load()) / h.weight
+}
+
+// Balancer implements the power-of-two-random-choices algorithm for load balancing.
+// For each pick, two distinct healthy handlers are randomly drawn, with a probability proportional to their weights,
+// and the one with the fewest in-flight requests relative to its weight is selected.
+// (https://www.eecs.harvard.edu/~michaelm/postscripts/handbook2001.pdf)
+type Balancer struct {
+	wantsHealthCheck bool
+
+	handlersMu sync.RWMutex
+	handlers   []*namedHandler
+	// status is a record of which child services of the Balancer are healthy.
+	// A service is initially marked as healthy when it is created via Add,
+	// and its status is later updated through the SetStatus method.
+	status *loadbalancer.Status
+	// healthy is the list of the healthy handlers, and totalWeight the sum of their weights.
+	// They are kept up to date by Add and SetStatus, so that the picks do not allocate.
+	healthy     []*namedHandler
+	totalWeight float64
+
+	sticky *loadbalancer.Sticky
+
+	// rand is only accessed under the handlersMu write lock.
+	rand *rand.Rand
+}
+
+// New creates a new power-of-two-random-choices load balancer.
+func New(sticky *dynamic.Sticky, wantHealthCheck bool) *Balancer {
+	return &Balancer{
+		status:           loadbalancer.NewStatus(),
+		wantsHealthCheck: wantHealthCheck,
+		sticky:           loadbalancer.NewSticky(sticky),
+		rand:             rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	b.status.Set(ctx, childName, up)
+	b.updateHealthy()
+}
+
+// updateHealthy rebuilds the list of the healthy handlers.
+// It must be called with the handlersMu write lock held.
+func (b *Balancer) updateHealthy() {
+	b.healthy = b.healthy[:0]
+	b.totalWeight = 0
+	for _, handler := range b.handlers {
+		if b.status.IsUp(handler.name) {
+			b.healthy = append(b.healthy, handler)
+			b.totalWeight += handler.weight
+		}
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this power-of-two-choices service")
+	}
+	b.status.RegisterUpdater(fn)
+	return nil
+}
+
+var errNoAvailableServer = errors.New("no available server")
+
+func (b *Balancer) nextServer() (*namedHandler, error) {
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	switch len(b.healthy) {
+	case 0:
+		return nil, errNoAvailableServer
+	case 1:
+		return b.healthy[0], nil
+	}
+
+	first := b.pick(b.totalWeight, nil)
+	second := b.pick(b.totalWeight-first.weight, first)
+
+	selected := first
+	if second.load() < first.load() {
+		selected = second
+	}
+
+	log.Debug().Msgf("Service selected by P2C: %s", selected.name)
+	return selected, nil
+}
+
+// pick draws a random handler among the healthy ones, with a probability proportional to its weight.
+// The excluded handler, if any, is never drawn, and totalWeight must not account for it.
+func (b *Balancer) pick(totalWeight float64, excluded *namedHandler) *namedHandler {
+	target := b.rand.Float64() * totalWeight
+
+	var last *namedHandler
+	for _, handler := range b.healthy {
+		if handler == excluded {
+			continue
+		}
+
+		last = handler
+		target -= handler.weight
+		if target < 0 {
+			return handler
+		}
+	}
+
+	// Rounding errors may leave a tiny remainder, in which case the last candidate is the right pick.
+	return last
+}
+
+// isUp returns whether the given child is healthy.
+func (b *Balancer) isUp(name string) bool {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	return b.status.IsUp(name)
+}
+
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if b.sticky != nil {
+		h, err := b.sticky.StickyHandler(req, b.isUp)
+		if err != nil {
+			log.Warn().Err(err).Msg("Error while getting sticky handler")
+		}
+
+		if h != nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	server, err := b.nextServer()
+	if err != nil {
+		if errors.Is(err, errNoAvailableServer) {
+			http.Error(w, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if b.sticky != nil {
+		b.sticky.WriteStickyCookie(w, server.name)
+	}
+
+	server.ServeHTTP(w, req)
+}
+
+// Add adds a handler.
+// A handler with a non-positive weight is ignored.
+func (b *Balancer) Add(name string, handler http.Handler, weight *int) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	if w <= 0 { // non-positive weight is meaningless
+		return
+	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
+
+	b.handlersMu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.status.Add(name)
+	b.healthy = append(b.healthy, h)
+	b.totalWeight += h.weight
+	b.handlersMu.Unlock()
+
+	if b.sticky != nil {
+		// Registering the counting handler makes sticky requests count as in-flight requests too.
+		b.sticky.AddHandler(name, h, w)
+	}
+}
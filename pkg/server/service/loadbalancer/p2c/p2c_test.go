@@ -0,0 +1,242 @@
+package p2c
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestBalancerLeastLoaded(t *testing.T) {
+	balancer := New(nil, false)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	balancer.Add("slow", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+		rw.Header().Set("server", "slow")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("fast", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "fast")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	// Make sure that the blocking request lands on the slow server.
+	balancer.SetStatus(context.Background(), "fast", false)
+
+	done := make(chan struct{})
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("block", "true")
+		balancer.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+	<-started
+
+	balancer.SetStatus(context.Background(), "fast", true)
+
+	// With only two servers, both are always drawn, and the least loaded one is selected.
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 10; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	close(release)
+	<-done
+
+	assert.Equal(t, 0, recorder.save["slow"])
+	assert.Equal(t, 10, recorder.save["fast"])
+}
+
+func TestBalancerWeights(t *testing.T) {
+	balancer := New(nil, false)
+	balancer.rand = rand.New(rand.NewSource(1))
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(3))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 1000; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	// Without load, the first drawn server is selected, and it is drawn proportionally to its weight.
+	assert.InDelta(t, 750, recorder.save["first"], 50)
+	assert.InDelta(t, 250, recorder.save["second"], 50)
+}
+
+func TestBalancerHealthyHandlers(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(1))
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(2))
+	balancer.Add("third", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(3))
+
+	healthyNames := func() []string {
+		var names []string
+		for _, h := range balancer.healthy {
+			names = append(names, h.name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"first", "second", "third"}, healthyNames())
+	assert.InDelta(t, 6, balancer.totalWeight, 0)
+
+	balancer.SetStatus(context.Background(), "second", false)
+	assert.Equal(t, []string{"first", "third"}, healthyNames())
+	assert.InDelta(t, 4, balancer.totalWeight, 0)
+
+	balancer.SetStatus(context.Background(), "second", true)
+	assert.Equal(t, []string{"first", "second", "third"}, healthyNames())
+	assert.InDelta(t, 6, balancer.totalWeight, 0)
+}
+
+func TestBalancerNoService(t *testing.T) {
+	balancer := New(nil, false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+}
+
+func TestBalancerOneServerZeroWeight(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(0))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 3; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 3, recorder.save["first"])
+}
+
+func TestBalancerOneServerDown(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}), Int(1))
+
+	balancer.Add("third", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "third")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+	balancer.SetStatus(context.Background(), "second", false)
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 10; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	assert.Equal(t, 0, recorder.save[""])
+	assert.Equal(t, 10, recorder.save["first"]+recorder.save["third"])
+}
+
+func TestBalancerNoServiceUp(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}), Int(1))
+
+	balancer.SetStatus(context.Background(), "first", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+}
+
+func TestBalancerPropagate(t *testing.T) {
+	balancer := New(nil, true)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(1))
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(1))
+
+	var statuses []bool
+	err := balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "first", false)
+	balancer.SetStatus(context.Background(), "second", false)
+	balancer.SetStatus(context.Background(), "first", true)
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func TestSticky(t *testing.T) {
+	balancer := New(&dynamic.Sticky{
+		Cookie: &dynamic.Cookie{Name: "test"},
+	}, false)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for i := 0; i < 10; i++ {
+		for _, cookie := range recorder.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		recorder.ResponseRecorder = httptest.NewRecorder()
+
+		balancer.ServeHTTP(recorder, req)
+	}
+
+	// All the requests go to the server selected by the first one.
+	assert.Contains(t, []int{0, 10}, recorder.save["first"])
+	assert.Equal(t, 10, recorder.save["first"]+recorder.save["second"])
+}
+
+func Int(v int) *int { return &v }
+
+type responseRecorder struct {
+	*httptest.ResponseRecorder
+	save map[string]int
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.save[r.Header().Get("server")]++
+	r.ResponseRecorder.WriteHeader(statusCode)
+}
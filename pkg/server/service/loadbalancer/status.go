@@ -0,0 +1,88 @@
+package loadbalancer
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Status is a record of which children of a load balancer are healthy, keyed by child name.
+// It runs the hooks updating the parents of the load balancer whenever the load balancer status changes,
+// the load balancer being up as long as one of its children is.
+// Status is not thread safe, and is guarded by the lock of its load balancer.
+type Status struct {
+	up map[string]struct{}
+	// updaters is the list of hooks that are run (to update the load balancer
+	// parent(s)), whenever the load balancer status changes.
+	updaters []func(bool)
+}
+
+// NewStatus creates a new Status, without any healthy child.
+func NewStatus() *Status {
+	return &Status{up: make(map[string]struct{})}
+}
+
+// Set sets the status of the given child, and propagates the new status of the load balancer if it changed.
+// It returns whether the child has recovered, i.e. it was down and is now up.
+func (s *Status) Set(ctx context.Context, childName string, up bool) bool {
+	upBefore := len(s.up) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	_, wasUp := s.up[childName]
+	recovered := up && !wasUp
+
+	if up {
+		s.up[childName] = struct{}{}
+	} else {
+		delete(s.up, childName)
+	}
+
+	upAfter := len(s.up) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return recovered
+	}
+
+	// Status Change
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range s.updaters {
+		fn(upAfter)
+	}
+
+	return recovered
+}
+
+// Add records the given child as healthy, without propagating the status of the load balancer,
+// as the children are added before the load balancer parents are updated.
+func (s *Status) Add(childName string) {
+	s.up[childName] = struct{}{}
+}
+
+// IsUp returns whether the given child is healthy.
+func (s *Status) IsUp(childName string) bool {
+	_, ok := s.up[childName]
+	return ok
+}
+
+// AnyUp returns whether at least one child is healthy.
+func (s *Status) AnyUp() bool {
+	return len(s.up) > 0
+}
+
+// RegisterUpdater adds fn to the list of hooks that are run when the status of the load balancer changes.
+func (s *Status) RegisterUpdater(fn func(up bool)) {
+	s.updaters = append(s.updaters, fn)
+}
@@ -0,0 +1,36 @@
+package loadbalancer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	status := NewStatus()
+
+	var updates []bool
+	status.RegisterUpdater(func(up bool) {
+		updates = append(updates, up)
+	})
+
+	status.Add("first")
+	status.Add("second")
+	assert.True(t, status.AnyUp())
+
+	assert.False(t, status.Set(context.Background(), "first", false))
+	assert.False(t, status.IsUp("first"))
+	assert.True(t, status.AnyUp())
+
+	assert.False(t, status.Set(context.Background(), "second", false))
+	assert.False(t, status.AnyUp())
+
+	// Only the first recovery of a child is reported.
+	assert.True(t, status.Set(context.Background(), "first", true))
+	assert.False(t, status.Set(context.Background(), "first", true))
+	assert.True(t, status.IsUp("first"))
+
+	// The updaters are only run when the status of the load balancer changes.
+	assert.Equal(t, []bool{false, true}, updates)
+}
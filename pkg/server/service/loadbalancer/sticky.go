@@ -0,0 +1,357 @@
+package loadbalancer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
+)
+
+// NamedHandler is a handler with a name, as registered on a Sticky.
+type NamedHandler struct {
+	http.Handler
+	Name   string
+	weight int
+}
+
+type stickyCookie struct {
+	name     string
+	secure   bool
+	httpOnly bool
+	sameSite string
+	maxAge   int
+
+	// signingKey is the key used to sign the cookie value, if not nil.
+	signingKey []byte
+	// aead encrypts the cookie value, if not nil.
+	aead cipher.AEAD
+}
+
+// Sticky ensures that client consistently interacts with the same HTTP handler.
+// In the cookie mode, a cookie containing the hashed name of the handler is added to the response,
+// and is used on subsequent requests to route the client to the same handler.
+// In the header and client IP modes, the handler is selected by hashing the request key,
+// using rendezvous hashing, so that only the keys of an unavailable handler are remapped.
+type Sticky struct {
+	cookie *stickyCookie
+	// key extracts the request key in the header and client IP modes.
+	key func(req *http.Request) string
+
+	handlersMu sync.RWMutex
+	// References all the handlers by name and also by the hashed value of the name.
+	handlers map[string]*NamedHandler
+	// named references all the handlers once, in the header and client IP modes.
+	named []*NamedHandler
+}
+
+// ValidateSticky returns an error if the given sticky configuration is invalid.
+func ValidateSticky(sticky *dynamic.Sticky) error {
+	if sticky == nil {
+		return nil
+	}
+
+	var count int
+	for _, set := range []bool{sticky.Cookie != nil, sticky.Header != nil, sticky.ClientIP != nil} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return errors.New("sticky cookie, header and clientIP are mutually exclusive")
+	}
+
+	switch {
+	case sticky.Cookie != nil:
+		if sticky.Cookie.Encrypt && sticky.Cookie.Secret == "" {
+			return errors.New("sticky cookie encryption requires a secret")
+		}
+
+	case sticky.Header != nil:
+		if sticky.Header.Name == "" {
+			return errors.New("sticky header name is required")
+		}
+
+	case sticky.ClientIP != nil:
+		if _, err := sticky.ClientIP.IPStrategy.Get(); err != nil {
+			return fmt.Errorf("sticky client IP strategy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// NewSticky creates a new Sticky instance.
+// It returns nil if the given sticky configuration does not enable stickiness.
+// The configuration is expected to be valid, as checked by ValidateSticky.
+func NewSticky(sticky *dynamic.Sticky) *Sticky {
+	if sticky == nil {
+		return nil
+	}
+
+	switch {
+	case sticky.Cookie != nil:
+		return &Sticky{
+			cookie:   newStickyCookie(sticky.Cookie),
+			handlers: make(map[string]*NamedHandler),
+		}
+
+	case sticky.Header != nil:
+		name := sticky.Header.Name
+		return &Sticky{
+			key: func(req *http.Request) string {
+				return req.Header.Get(name)
+			},
+			handlers: make(map[string]*NamedHandler),
+		}
+
+	case sticky.ClientIP != nil:
+		strategy, err := sticky.ClientIP.IPStrategy.Get()
+		if err != nil {
+			log.Error().Err(err).Msg("Invalid sticky client IP strategy, using the remote address")
+			strategy = &ip.RemoteAddrStrategy{}
+		}
+		return &Sticky{
+			key:      strategy.GetIP,
+			handlers: make(map[string]*NamedHandler),
+		}
+
+	default:
+		return nil
+	}
+}
+
+func newStickyCookie(config *dynamic.Cookie) *stickyCookie {
+	cookie := &stickyCookie{
+		name:     config.Name,
+		secure:   config.Secure,
+		httpOnly: config.HTTPOnly,
+		sameSite: config.SameSite,
+		maxAge:   config.MaxAge,
+	}
+
+	if config.Secret == "" {
+		return cookie
+	}
+
+	// The secret is hashed to get a key of the size expected by AES-256 and HMAC-SHA256.
+	key := sha256.Sum256([]byte(config.Secret))
+
+	if !config.Encrypt {
+		cookie.signingKey = key[:]
+		return cookie
+	}
+
+	// NewCipher and NewGCM never fail with a 32 bytes key.
+	block, _ := aes.NewCipher(key[:])
+	cookie.aead, _ = cipher.NewGCM(block)
+
+	return cookie
+}
+
+// AddHandler registers the given handler, with the given weight, under its name and the hashed value of its name.
+func (s *Sticky) AddHandler(name string, h http.Handler, weight int) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	sticky := &NamedHandler{
+		Handler: h,
+		Name:    name,
+		weight:  weight,
+	}
+
+	s.handlers[name] = sticky
+	s.handlers[hash(name)] = sticky
+
+	if s.cookie == nil {
+		s.named = append(s.named, sticky)
+	}
+}
+
+// StickyHandler returns the NamedHandler the given request sticks to, among the handlers for which isUp returns true,
+// or nil if the request does not stick to any available handler.
+func (s *Sticky) StickyHandler(req *http.Request, isUp func(name string) bool) (*NamedHandler, error) {
+	if s.cookie == nil {
+		return s.hashedHandler(s.key(req), isUp), nil
+	}
+
+	cookie, err := req.Cookie(s.cookie.name)
+	if err != nil {
+		if errors.Is(err, http.ErrNoCookie) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading cookie: %w", err)
+	}
+
+	value, err := s.cookie.decode(cookie.Value)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cookie: %w", err)
+	}
+
+	s.handlersMu.RLock()
+	h := s.handlers[value]
+	s.handlersMu.RUnlock()
+
+	if h == nil || !isUp(h.Name) {
+		return nil, nil
+	}
+
+	return h, nil
+}
+
+// hashedHandler returns the available handler with the highest weighted rendezvous hashing score for the given key.
+// (https://en.wikipedia.org/wiki/Rendezvous_hashing)
+func (s *Sticky) hashedHandler(key string, isUp func(name string) bool) *NamedHandler {
+	if key == "" {
+		return nil
+	}
+
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	var selected *NamedHandler
+	bestScore := math.Inf(-1)
+
+	for _, h := range s.named {
+		if h.weight <= 0 || !isUp(h.Name) {
+			continue
+		}
+
+		// The hash is mapped to ]0, 1[, and the score of the handler is -weight/ln(hash),
+		// so that the share of the keys of each handler is proportional to its weight.
+		u := (float64(xxhash.Sum64String(key+"\x00"+h.Name)) + 1) / (math.MaxUint64 + 2)
+		score := -float64(h.weight) / math.Log(u)
+
+		if score > bestScore {
+			selected = h
+			bestScore = score
+		}
+	}
+
+	return selected
+}
+
+// WriteStickyCookie writes the sticky cookie, referencing the given handler name, to the response.
+// It does nothing outside of the cookie mode.
+func (s *Sticky) WriteStickyCookie(rw http.ResponseWriter, name string) {
+	if s.cookie == nil {
+		return
+	}
+
+	value, err := s.cookie.encode(name)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while encoding sticky cookie")
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:     s.cookie.name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: s.cookie.httpOnly,
+		Secure:   s.cookie.secure,
+		SameSite: convertSameSite(s.cookie.sameSite),
+		MaxAge:   s.cookie.maxAge,
+	}
+
+	http.SetCookie(rw, cookie)
+}
+
+// encode returns the cookie value referencing the given handler name.
+func (c *stickyCookie) encode(name string) (string, error) {
+	switch {
+	case c.aead != nil:
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("generating nonce: %w", err)
+		}
+
+		return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, []byte(name), nil)), nil
+
+	case c.signingKey != nil:
+		value := hash(name)
+		return value + "." + c.sign(value), nil
+
+	default:
+		return hash(name), nil
+	}
+}
+
+// decode returns the handler reference carried by the given cookie value.
+func (c *stickyCookie) decode(value string) (string, error) {
+	switch {
+	case c.aead != nil:
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return "", err
+		}
+
+		if len(data) < c.aead.NonceSize() {
+			return "", errors.New("invalid encrypted value")
+		}
+
+		nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+
+		name, err := c.aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return "", fmt.Errorf("decrypting value: %w", err)
+		}
+
+		return string(name), nil
+
+	case c.signingKey != nil:
+		value, signature, ok := strings.Cut(value, ".")
+		if !ok || !hmac.Equal([]byte(signature), []byte(c.sign(value))) {
+			return "", errors.New("invalid signature")
+		}
+
+		return value, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func (c *stickyCookie) sign(value string) string {
+	mac := hmac.New(sha256.New, c.signingKey)
+	// We purposely ignore the error because the implementation always returns nil.
+	_, _ = mac.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func convertSameSite(sameSite string) http.SameSite {
+	switch sameSite {
+	case "none":
+		return http.SameSiteNoneMode
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
+
+func hash(input string) string {
+	hasher := fnv.New64()
+	// We purposely ignore the error because the implementation always returns nil.
+	_, _ = hasher.Write([]byte(input))
+
+	return strconv.FormatUint(hasher.Sum64(), 16)
+}
@@ -0,0 +1,95 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestValidateSticky(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		sticky      *dynamic.Sticky
+		expectError bool
+	}{
+		{
+			desc: "no sticky",
+		},
+		{
+			desc:   "cookie",
+			sticky: &dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "foo"}},
+		},
+		{
+			desc:   "encrypted cookie",
+			sticky: &dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "foo", Secret: "secret", Encrypt: true}},
+		},
+		{
+			desc:        "encrypted cookie without secret",
+			sticky:      &dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "foo", Encrypt: true}},
+			expectError: true,
+		},
+		{
+			desc:   "header",
+			sticky: &dynamic.Sticky{Header: &dynamic.StickyHeader{Name: "X-Session"}},
+		},
+		{
+			desc:        "header without name",
+			sticky:      &dynamic.Sticky{Header: &dynamic.StickyHeader{}},
+			expectError: true,
+		},
+		{
+			desc:   "client IP",
+			sticky: &dynamic.Sticky{ClientIP: &dynamic.StickyClientIP{}},
+		},
+		{
+			desc:        "client IP with invalid excluded IPs",
+			sticky:      &dynamic.Sticky{ClientIP: &dynamic.StickyClientIP{IPStrategy: &dynamic.IPStrategy{ExcludedIPs: []string{"foo"}}}},
+			expectError: true,
+		},
+		{
+			desc: "cookie and header",
+			sticky: &dynamic.Sticky{
+				Cookie: &dynamic.Cookie{Name: "foo"},
+				Header: &dynamic.StickyHeader{Name: "X-Session"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateSticky(test.sticky)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSticky_hashedHandlerWeights(t *testing.T) {
+	sticky := NewSticky(&dynamic.Sticky{Header: &dynamic.StickyHeader{Name: "X-Session"}})
+	sticky.AddHandler("first", http.NotFoundHandler(), 3)
+	sticky.AddHandler("second", http.NotFoundHandler(), 1)
+	sticky.AddHandler("third", http.NotFoundHandler(), 0)
+
+	isUp := func(string) bool { return true }
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[sticky.hashedHandler("session-"+strconv.Itoa(i), isUp).Name]++
+	}
+
+	// The share of the keys of each handler is proportional to its weight.
+	assert.InDelta(t, 7500, counts["first"], 300)
+	assert.InDelta(t, 2500, counts["second"], 300)
+	assert.Zero(t, counts["third"])
+}
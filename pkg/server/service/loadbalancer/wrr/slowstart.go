@@ -0,0 +1,92 @@
+package wrr
+
+import (
+	"sync"
+	"time"
+)
+
+// SlowStartTable records the start of the slow start of the servers of a service,
+// in order to carry on their slow start across the dynamic configuration reloads.
+type SlowStartTable struct {
+	mu sync.Mutex
+	// rampStarts holds the start of the slow start of the servers, keyed by server name.
+	rampStarts map[string]time.Time
+}
+
+// NewSlowStartTable creates a new SlowStartTable.
+func NewSlowStartTable() *SlowStartTable {
+	return &SlowStartTable{rampStarts: make(map[string]time.Time)}
+}
+
+// rampStart returns the start of the slow start of the given server, starting it at the given time if the server is unknown.
+func (t *SlowStartTable) rampStart(name string, now time.Time) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rampStart, ok := t.rampStarts[name]
+	if !ok {
+		rampStart = now
+		t.rampStarts[name] = rampStart
+	}
+
+	return rampStart
+}
+
+// restart starts a new slow start of the given server at the given time, e.g. when it recovers.
+func (t *SlowStartTable) restart(name string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rampStarts[name] = now
+}
+
+// Retain drops the servers which are not in the given list,
+// for them to start a new slow start if they come back.
+func (t *SlowStartTable) Retain(serverNames map[string]struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name := range t.rampStarts {
+		if _, ok := serverNames[name]; !ok {
+			delete(t.rampStarts, name)
+		}
+	}
+}
+
+// SlowStartTables holds the slow start tables of the services, keyed by service name,
+// in order to keep the slow start of the servers across the dynamic configuration reloads.
+type SlowStartTables struct {
+	mu     sync.Mutex
+	tables map[string]*SlowStartTable
+}
+
+// NewSlowStartTables creates a new SlowStartTables.
+func NewSlowStartTables() *SlowStartTables {
+	return &SlowStartTables{tables: make(map[string]*SlowStartTable)}
+}
+
+// Get returns the slow start table of the given service, creating it if needed.
+func (s *SlowStartTables) Get(serviceName string) *SlowStartTable {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.tables[serviceName]
+	if !ok {
+		table = NewSlowStartTable()
+		s.tables[serviceName] = table
+	}
+
+	return table
+}
+
+// Retain drops the slow start tables of the services which are not in the given list.
+func (s *SlowStartTables) Retain(serviceNames map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.tables {
+		if _, ok := serviceNames[name]; !ok {
+			delete(s.tables, name)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package wrr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowStartTable(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	table := NewSlowStartTable()
+	assert.Equal(t, now, table.rampStart("server1", now))
+
+	// The servers keep the start of their slow start across the reloads.
+	assert.Equal(t, now, table.rampStart("server1", later))
+	assert.Equal(t, later, table.rampStart("server2", later))
+
+	// A recovered server starts a new slow start.
+	table.restart("server2", later.Add(time.Minute))
+	assert.Equal(t, later.Add(time.Minute), table.rampStart("server2", later))
+
+	// A removed server starts a new slow start when it comes back.
+	table.Retain(map[string]struct{}{"server2": {}})
+	assert.Equal(t, later, table.rampStart("server1", later))
+	assert.Equal(t, later.Add(time.Minute), table.rampStart("server2", later))
+}
+
+func TestSlowStartTables(t *testing.T) {
+	tables := NewSlowStartTables()
+
+	table := tables.Get("foo@file")
+	assert.Same(t, table, tables.Get("foo@file"))
+
+	// The tables of the services without slow start are dropped.
+	tables.Retain(map[string]struct{}{"bar@file": {}})
+	assert.NotSame(t, table, tables.Get("foo@file"))
+}
@@ -4,13 +4,14 @@ import (
 	"container/heap"
 	"context"
 	"errors"
-	"hash/fnv"
+	"math"
 	"net/http"
-	"strconv"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
 )
 
 type namedHandler struct {
@@ -18,27 +19,17 @@ type namedHandler struct {
 	name     string
 	weight   float64
 	deadline float64
+	// rampStart is the start of the slow start of the handler.
+	rampStart time.Time
 }
 
-type stickyCookie struct {
-	name     string
-	secure   bool
-	httpOnly bool
-	sameSite string
-	maxAge   int
-}
-
-func convertSameSite(sameSite string) http.SameSite {
-	switch sameSite {
-	case "none":
-		return http.SameSiteNoneMode
-	case "lax":
-		return http.SameSiteLaxMode
-	case "strict":
-		return http.SameSiteStrictMode
-	default:
-		return http.SameSiteDefaultMode
-	}
+// slowStart holds the slow start parameters of a Balancer.
+type slowStart struct {
+	duration   time.Duration
+	aggression float64
+	minFactor  float64
+	// table keeps the start of the slow start of the handlers across the reloads, if not nil.
+	table *SlowStartTable
 }
 
 // Balancer is a WeightedRoundRobin load balancer based on Earliest Deadline First (EDF).
@@ -47,42 +38,65 @@ func convertSameSite(sameSite string) http.SameSite {
 // Entries have deadlines set at currentDeadline + 1 / weight,
 // providing weighted round-robin behavior with floating point weights and an O(log n) pick time.
 type Balancer struct {
-	stickyCookie     *stickyCookie
 	wantsHealthCheck bool
 
-	handlersMu sync.RWMutex
-	// References all the handlers by name and also by the hashed value of the name.
-	handlerMap  map[string]*namedHandler
+	handlersMu  sync.RWMutex
 	handlers    []*namedHandler
 	curDeadline float64
-	// status is a record of which child services of the Balancer are healthy, keyed
-	// by name of child service. A service is initially added to the map when it is
-	// created via Add, and it is later removed or added to the map as needed,
-	// through the SetStatus method.
-	status map[string]struct{}
-	// updaters is the list of hooks that are run (to update the Balancer
-	// parent(s)), whenever the Balancer status changes.
-	updaters []func(bool)
+	// status is a record of which child services of the Balancer are healthy.
+	// A service is initially marked as healthy when it is created via Add,
+	// and its status is later updated through the SetStatus method.
+	status *loadbalancer.Status
+
+	sticky *loadbalancer.Sticky
+
+	slowStart *slowStart
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
 }
 
 // New creates a new load balancer.
 func New(sticky *dynamic.Sticky, wantHealthCheck bool) *Balancer {
-	balancer := &Balancer{
-		status:           make(map[string]struct{}),
-		handlerMap:       make(map[string]*namedHandler),
+	return &Balancer{
+		status:           loadbalancer.NewStatus(),
 		wantsHealthCheck: wantHealthCheck,
+		sticky:           loadbalancer.NewSticky(sticky),
+		timeNow:          time.Now,
 	}
-	if sticky != nil && sticky.Cookie != nil {
-		balancer.stickyCookie = &stickyCookie{
-			name:     sticky.Cookie.Name,
-			secure:   sticky.Cookie.Secure,
-			httpOnly: sticky.Cookie.HTTPOnly,
-			sameSite: sticky.Cookie.SameSite,
-			maxAge:   sticky.Cookie.MaxAge,
-		}
+}
+
+// SetSlowStart enables the slow start of the handlers added from now on, and of the recovered ones.
+// The table keeps the start of the slow start of the handlers across the balancers built for each configuration,
+// the slow start of a handler starting when it is added if the table is nil.
+// Not thread safe.
+func (b *Balancer) SetSlowStart(config *dynamic.SlowStart, table *SlowStartTable) {
+	if config == nil || config.Duration <= 0 {
+		b.slowStart = nil
+		return
+	}
+
+	b.slowStart = &slowStart{
+		duration:   time.Duration(config.Duration),
+		aggression: config.Aggression,
+		minFactor:  float64(config.MinWeightPercent) / 100,
+		table:      table,
 	}
+}
+
+// effectiveWeight returns the weight of the given handler, lowered during its slow start.
+func (b *Balancer) effectiveWeight(h *namedHandler) float64 {
+	if b.slowStart == nil {
+		return h.weight
+	}
+
+	elapsed := b.timeNow().Sub(h.rampStart)
+	if elapsed >= b.slowStart.duration {
+		return h.weight
+	}
+
+	factor := math.Pow(max(float64(elapsed), 0)/float64(b.slowStart.duration), 1/b.slowStart.aggression)
 
-	return balancer
+	return h.weight * max(factor, b.slowStart.minFactor)
 }
 
 // Len implements heap.Interface/sort.Interface.
@@ -122,38 +136,21 @@ func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
 	b.handlersMu.Lock()
 	defer b.handlersMu.Unlock()
 
-	upBefore := len(b.status) > 0
-
-	status := "DOWN"
-	if up {
-		status = "UP"
-	}
-
-	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
-
-	if up {
-		b.status[childName] = struct{}{}
-	} else {
-		delete(b.status, childName)
-	}
-
-	upAfter := len(b.status) > 0
-	status = "DOWN"
-	if upAfter {
-		status = "UP"
+	recovered := b.status.Set(ctx, childName, up)
+	if !recovered || b.slowStart == nil {
+		return
 	}
 
-	// No Status Change
-	if upBefore == upAfter {
-		// We're still with the same status, no need to propagate
-		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
-		return
+	// The recovered child starts a new slow start.
+	now := b.timeNow()
+	for _, h := range b.handlers {
+		if h.name == childName {
+			h.rampStart = now
+		}
 	}
 
-	// Status Change
-	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
-	for _, fn := range b.updaters {
-		fn(upAfter)
+	if b.slowStart.table != nil {
+		b.slowStart.table.restart(childName, now)
 	}
 }
 
@@ -164,7 +161,7 @@ func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
 	if !b.wantsHealthCheck {
 		return errors.New("healthCheck not enabled in config for this weighted service")
 	}
-	b.updaters = append(b.updaters, fn)
+	b.status.RegisterUpdater(fn)
 	return nil
 }
 
@@ -174,7 +171,7 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	b.handlersMu.Lock()
 	defer b.handlersMu.Unlock()
 
-	if len(b.handlers) == 0 || len(b.status) == 0 {
+	if len(b.handlers) == 0 || !b.status.AnyUp() {
 		return nil, errNoAvailableServer
 	}
 
@@ -185,10 +182,10 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 
 		// curDeadline should be handler's deadline so that new added entry would have a fair competition environment with the old ones.
 		b.curDeadline = handler.deadline
-		handler.deadline += 1 / handler.weight
+		handler.deadline += 1 / b.effectiveWeight(handler)
 
 		heap.Push(b, handler)
-		if _, ok := b.status[handler.name]; ok {
+		if b.status.IsUp(handler.name) {
 			break
 		}
 	}
@@ -197,28 +194,24 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	return handler, nil
 }
 
-func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	if b.stickyCookie != nil {
-		cookie, err := req.Cookie(b.stickyCookie.name)
+// isUp returns whether the given child is healthy.
+func (b *Balancer) isUp(name string) bool {
+	b.handlersMu.RLock()
+	defer b.handlersMu.RUnlock()
+
+	return b.status.IsUp(name)
+}
 
-		if err != nil && !errors.Is(err, http.ErrNoCookie) {
-			log.Warn().Err(err).Msg("Error while reading cookie")
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if b.sticky != nil {
+		h, err := b.sticky.StickyHandler(req, b.isUp)
+		if err != nil {
+			log.Warn().Err(err).Msg("Error while getting sticky handler")
 		}
 
-		if err == nil && cookie != nil {
-			b.handlersMu.RLock()
-			handler, ok := b.handlerMap[cookie.Value]
-			b.handlersMu.RUnlock()
-
-			if ok && handler != nil {
-				b.handlersMu.RLock()
-				_, isHealthy := b.status[handler.name]
-				b.handlersMu.RUnlock()
-				if isHealthy {
-					handler.ServeHTTP(w, req)
-					return
-				}
-			}
+		if h != nil {
+			h.ServeHTTP(w, req)
+			return
 		}
 	}
 
@@ -232,17 +225,8 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if b.stickyCookie != nil {
-		cookie := &http.Cookie{
-			Name:     b.stickyCookie.name,
-			Value:    hash(server.name),
-			Path:     "/",
-			HttpOnly: b.stickyCookie.httpOnly,
-			Secure:   b.stickyCookie.secure,
-			SameSite: convertSameSite(b.stickyCookie.sameSite),
-			MaxAge:   b.stickyCookie.maxAge,
-		}
-		http.SetCookie(w, cookie)
+	if b.sticky != nil {
+		b.sticky.WriteStickyCookie(w, server.name)
 	}
 
 	server.ServeHTTP(w, req)
@@ -260,21 +244,18 @@ func (b *Balancer) Add(name string, handler http.Handler, weight *int) {
 		return
 	}
 
-	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
+	h := &namedHandler{Handler: handler, name: name, weight: float64(w), rampStart: b.timeNow()}
+	if b.slowStart != nil && b.slowStart.table != nil {
+		h.rampStart = b.slowStart.table.rampStart(name, h.rampStart)
+	}
 
 	b.handlersMu.Lock()
-	h.deadline = b.curDeadline + 1/h.weight
+	h.deadline = b.curDeadline + 1/b.effectiveWeight(h)
 	heap.Push(b, h)
-	b.status[name] = struct{}{}
-	b.handlerMap[name] = h
-	b.handlerMap[hash(name)] = h
+	b.status.Add(name)
 	b.handlersMu.Unlock()
-}
 
-func hash(input string) string {
-	hasher := fnv.New64()
-	// We purposely ignore the error because the implementation always returns nil.
-	_, _ = hasher.Write([]byte(input))
-
-	return strconv.FormatUint(hasher.Sum64(), 16)
+	if b.sticky != nil {
+		b.sticky.AddHandler(name, handler, w)
+	}
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
@@ -294,6 +297,145 @@ func TestSticky_FallBack(t *testing.T) {
 	assert.Equal(t, 3, recorder.save["second"])
 }
 
+func TestSticky_SignedCookie(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		encrypt bool
+	}{
+		{
+			desc: "signed",
+		},
+		{
+			desc:    "encrypted",
+			encrypt: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := New(&dynamic.Sticky{
+				Cookie: &dynamic.Cookie{Name: "test", Secret: "secret", Encrypt: test.encrypt},
+			}, false)
+
+			balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", "first")
+				rw.WriteHeader(http.StatusOK)
+			}), Int(1))
+
+			balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", "second")
+				rw.WriteHeader(http.StatusOK)
+			}), Int(2))
+
+			recorder := &responseRecorder{
+				ResponseRecorder: httptest.NewRecorder(),
+				save:             map[string]int{},
+				cookies:          make(map[string]*http.Cookie),
+			}
+
+			// A forged cookie is ignored, and replaced by a valid one.
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "test", Value: "first"})
+			balancer.ServeHTTP(recorder, req)
+
+			assert.Equal(t, 1, recorder.save["second"])
+			assert.NotEqual(t, "first", recorder.cookies["test"].Value)
+
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(recorder.cookies["test"])
+			for i := 0; i < 3; i++ {
+				recorder.ResponseRecorder = httptest.NewRecorder()
+
+				balancer.ServeHTTP(recorder, req)
+			}
+
+			assert.Equal(t, 0, recorder.save["first"])
+			assert.Equal(t, 4, recorder.save["second"])
+		})
+	}
+}
+
+func TestSticky_Header(t *testing.T) {
+	balancer := New(&dynamic.Sticky{
+		Header: &dynamic.StickyHeader{Name: "X-Session"},
+	}, true)
+
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		balancer.Add(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), Int(1))
+	}
+
+	serve := func(session string) string {
+		recorder := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Session", session)
+		balancer.ServeHTTP(recorder, req)
+
+		assert.Empty(t, recorder.Result().Cookies())
+		return recorder.Header().Get("server")
+	}
+
+	servers := make(map[string]string)
+	for i := 0; i < 30; i++ {
+		session := "session-" + strconv.Itoa(i)
+		servers[session] = serve(session)
+
+		// The requests of a session always go to the same server.
+		assert.Equal(t, servers[session], serve(session))
+	}
+
+	// Only the sessions of the server going down are remapped.
+	balancer.SetStatus(context.Background(), "second", false)
+
+	for session, server := range servers {
+		if server == "second" {
+			assert.NotEqual(t, "second", serve(session))
+			continue
+		}
+		assert.Equal(t, server, serve(session))
+	}
+}
+
+func TestSticky_ClientIP(t *testing.T) {
+	balancer := New(&dynamic.Sticky{
+		ClientIP: &dynamic.StickyClientIP{IPStrategy: &dynamic.IPStrategy{Depth: 1}},
+	}, false)
+
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		balancer.Add(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), Int(1))
+	}
+
+	serve := func(remoteAddr, forwardedFor string) string {
+		recorder := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		balancer.ServeHTTP(recorder, req)
+
+		return recorder.Header().Get("server")
+	}
+
+	for i := 0; i < 10; i++ {
+		clientIP := "10.0.0." + strconv.Itoa(i)
+		expected := serve("192.168.0.1:1234", clientIP)
+
+		// The client IP is read from the X-Forwarded-For header, whatever the proxy in front of Traefik.
+		assert.Equal(t, expected, serve("192.168.0.2:1234", clientIP))
+	}
+}
+
 // TestBalancerBias makes sure that the WRR algorithm spreads elements evenly right from the start,
 // and that it does not "over-favor" the high-weighted ones with a biased start-up regime.
 func TestBalancerBias(t *testing.T) {
@@ -320,6 +462,123 @@ func TestBalancerBias(t *testing.T) {
 	assert.Equal(t, wantSequence, recorder.sequence)
 }
 
+func TestBalancerSlowStart(t *testing.T) {
+	now := time.Now()
+
+	balancer := New(nil, false)
+	balancer.timeNow = func() time.Time { return now }
+	table := NewSlowStartTable()
+	table.restart("first", now.Add(-time.Minute))
+	balancer.SetSlowStart(&dynamic.SlowStart{Duration: ptypes.Duration(10 * time.Second), Aggression: 1, MinWeightPercent: 10}, table)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	// The second server starts with 10% of its weight.
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 110; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.InDelta(t, 100, recorder.save["first"], 1)
+	assert.InDelta(t, 10, recorder.save["second"], 1)
+
+	// Halfway through the slow start, the second server has half of its weight.
+	// The deadline of the second server, set with its previous weight, slightly delays its next pick.
+	now = now.Add(5 * time.Second)
+	recorder = &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 150; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.InDelta(t, 100, recorder.save["first"], 5)
+	assert.InDelta(t, 50, recorder.save["second"], 5)
+
+	// After the slow start, the second server has its full weight.
+	now = now.Add(5 * time.Second)
+	recorder = &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 200; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.InDelta(t, 100, recorder.save["first"], 2)
+	assert.InDelta(t, 100, recorder.save["second"], 2)
+}
+
+func TestBalancerSlowStart_Recovered(t *testing.T) {
+	now := time.Now()
+
+	balancer := New(nil, true)
+	balancer.timeNow = func() time.Time { return now }
+	balancer.SetSlowStart(&dynamic.SlowStart{Duration: ptypes.Duration(10 * time.Second), Aggression: 1, MinWeightPercent: 10}, nil)
+
+	balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	now = now.Add(time.Minute)
+	balancer.SetStatus(context.WithValue(context.Background(), serviceName, "parent"), "second", false)
+	balancer.SetStatus(context.WithValue(context.Background(), serviceName, "parent"), "second", true)
+
+	// The recovered server starts a new slow start.
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 110; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.InDelta(t, 100, recorder.save["first"], 1)
+	assert.InDelta(t, 10, recorder.save["second"], 1)
+}
+
+func TestBalancerSlowStart_RecoveredThenReloaded(t *testing.T) {
+	now := time.Now()
+	config := &dynamic.SlowStart{Duration: ptypes.Duration(10 * time.Second), Aggression: 1, MinWeightPercent: 10}
+	table := NewSlowStartTable()
+
+	newBalancer := func() *Balancer {
+		balancer := New(nil, true)
+		balancer.timeNow = func() time.Time { return now }
+		balancer.SetSlowStart(config, table)
+
+		balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", "first")
+			rw.WriteHeader(http.StatusOK)
+		}), Int(1))
+
+		balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", "second")
+			rw.WriteHeader(http.StatusOK)
+		}), Int(1))
+
+		return balancer
+	}
+
+	balancer := newBalancer()
+
+	now = now.Add(time.Minute)
+	balancer.SetStatus(context.WithValue(context.Background(), serviceName, "parent"), "second", false)
+	balancer.SetStatus(context.WithValue(context.Background(), serviceName, "parent"), "second", true)
+
+	// The configuration is reloaded during the slow start of the recovered server.
+	balancer = newBalancer()
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 110; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.InDelta(t, 100, recorder.save["first"], 1)
+	assert.InDelta(t, 10, recorder.save["second"], 1)
+}
+
 func Int(v int) *int { return &v }
 
 type responseRecorder struct {
@@ -11,6 +11,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
 )
 
 // ManagerFactory a factory of service manager.
@@ -18,6 +19,7 @@ type ManagerFactory struct {
 	observabilityMgr *middleware.ObservabilityMgr
 
 	roundTripperManager *RoundTripperManager
+	locality            *static.Locality
 
 	api              func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
@@ -35,6 +37,7 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 		observabilityMgr:    observabilityMgr,
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
+		locality:            staticConfiguration.Locality,
 		acmeHTTPHandler:     acmeHTTPHandler,
 	}
 
@@ -72,8 +75,20 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 }
 
 // Build creates a service manager.
-func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
+// The slowStartTables hold the slow start of the servers, across the managers built for each configuration.
+func (f *ManagerFactory) Build(configuration *runtime.Configuration, slowStartTables *wrr.SlowStartTables) *InternalHandlers {
+	// The slow start of the services which do not have one anymore is dropped.
+	slowStartServices := make(map[string]struct{})
+	for name, service := range configuration.Services {
+		if service.LoadBalancer != nil && service.LoadBalancer.SlowStart != nil {
+			slowStartServices[name] = struct{}{}
+		}
+	}
+	slowStartTables.Retain(slowStartServices)
+
 	svcManager := NewManager(configuration.Services, f.observabilityMgr, f.routinesPool, f.roundTripperManager)
+	svcManager.slowStartTables = slowStartTables
+	svcManager.locality = f.locality
 
 	var apiHandler http.Handler
 	if f.api != nil {
@@ -116,6 +116,12 @@ func computeStatusCode(err error) int {
 	case errors.Is(err, context.Canceled):
 		return StatusClientClosedRequest
 	default:
+		// The request body exceeds the limit of a middleware, e.g. the decompress middleware.
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return http.StatusRequestEntityTooLarge
+		}
+
 		var netErr net.Error
 		if errors.As(err, &netErr) {
 			if netErr.Timeout() {
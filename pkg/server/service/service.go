@@ -18,6 +18,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
@@ -27,8 +28,13 @@ import (
 	"github.com/traefik/traefik/v3/pkg/server/cookie"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/chash"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/failover"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/leastconn"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/locality"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/mirror"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/p2c"
 	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/wrr"
 )
 
@@ -49,7 +55,11 @@ type Manager struct {
 	services       map[string]http.Handler
 	configs        map[string]*runtime.ServiceInfo
 	healthCheckers map[string]*healthcheck.ServiceHealthChecker
-	rand           *rand.Rand // For the initial shuffling of load-balancers.
+	// slowStartTables keep the slow start of the servers across the reloads, if not nil.
+	slowStartTables *wrr.SlowStartTables
+	// locality is the locality of Traefik, if configured.
+	locality *static.Locality
+	rand     *rand.Rand // For the initial shuffling of load-balancers.
 }
 
 // NewManager creates a new Manager.
@@ -220,6 +230,10 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		config.Sticky.Cookie.Name = cookie.GetName(config.Sticky.Cookie.Name, serviceName)
 	}
 
+	if err := loadbalancer.ValidateSticky(config.Sticky); err != nil {
+		return nil, err
+	}
+
 	balancer := wrr.New(config.Sticky, config.HealthCheck != nil)
 	for _, service := range shuffle(config.Services, m.rand) {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
@@ -283,14 +297,33 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		return nil, err
 	}
 
-	lb := wrr.New(service.Sticky, service.HealthCheck != nil)
+	var slowStartTable *wrr.SlowStartTable
+	if service.SlowStart != nil && m.slowStartTables != nil {
+		slowStartTable = m.slowStartTables.Get(serviceName)
+	}
+
+	lb, err := m.newLocalityBalancer(service, slowStartTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var passiveHealthChecker *healthcheck.PassiveHealthChecker
+	if service.PassiveHealthCheck != nil {
+		passiveHealthChecker = healthcheck.NewPassiveHealthChecker(
+			ctx,
+			m.observabilityMgr.MetricsRegistry(),
+			service.PassiveHealthCheck,
+			serviceName,
+			lb,
+			info,
+		)
+	}
+
 	healthCheckTargets := make(map[string]*url.URL)
+	serverNames := make(map[string]struct{})
 
 	for _, server := range shuffle(service.Servers, m.rand) {
-		hasher := fnv.New64a()
-		_, _ = hasher.Write([]byte(server.URL)) // this will never return an error.
-
-		proxyName := hex.EncodeToString(hasher.Sum(nil))
+		proxyName := serverName(server.URL)
 
 		target, err := url.Parse(server.URL)
 		if err != nil {
@@ -300,7 +333,12 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		logger.Debug().Str(logs.ServerName, proxyName).Stringer("target", target).
 			Msg("Creating server")
 
-		proxy := buildSingleHostProxy(target, passHostHeader, time.Duration(flushInterval), roundTripper, m.bufferPool)
+		serverRoundTripper := roundTripper
+		if passiveHealthChecker != nil {
+			serverRoundTripper = passiveHealthChecker.WrapRoundTripper(proxyName, target, roundTripper)
+		}
+
+		proxy := buildSingleHostProxy(target, passHostHeader, time.Duration(flushInterval), serverRoundTripper, m.bufferPool)
 
 		// Prevents from enabling observability for internal resources.
 
@@ -332,6 +370,11 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		info.UpdateServerStatus(target.String(), runtime.StatusUp)
 
 		healthCheckTargets[proxyName] = target
+		serverNames[proxyName] = struct{}{}
+	}
+
+	if slowStartTable != nil {
+		slowStartTable.Retain(serverNames)
 	}
 
 	if service.HealthCheck != nil {
@@ -349,6 +392,117 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 	return lb, nil
 }
 
+// serverName returns the name of the server with the given URL, as registered on the load-balancer.
+func serverName(serverURL string) string {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(serverURL)) // this will never return an error.
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// newLocalityBalancer creates the load-balancer of the given service configuration,
+// preferring the servers of the same locality as Traefik if its locality is configured and the servers have one.
+// The sticky sessions and the chash strategy are not locality-aware, as they have to select the server from all of them.
+func (m *Manager) newLocalityBalancer(service *dynamic.ServersLoadBalancer, slowStartTable *wrr.SlowStartTable) (serversBalancer, error) {
+	if m.locality == nil || service.Sticky != nil || service.Strategy == dynamic.BalancerStrategyConsistentHash || !hasLocality(service.Servers) {
+		return newServersBalancer(service, slowStartTable)
+	}
+
+	tiers := make(map[string]int)
+	for _, server := range service.Servers {
+		tiers[serverName(server.URL)] = localityTier(m.locality, server)
+	}
+
+	wantHealthCheck := service.HealthCheck != nil || service.PassiveHealthCheck != nil
+
+	newChild := func() (locality.Child, error) {
+		return newServersBalancer(service, slowStartTable)
+	}
+
+	return locality.New(m.locality.MinHealthyPercent, wantHealthCheck, newChild, func(name string) int {
+		return tiers[name]
+	})
+}
+
+// hasLocality returns whether at least one of the given servers has a locality.
+func hasLocality(servers []dynamic.Server) bool {
+	for _, server := range servers {
+		if server.Zone != "" || server.Region != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// localityTier returns the locality tier of the given server, relatively to the given locality of Traefik.
+func localityTier(traefikLocality *static.Locality, server dynamic.Server) int {
+	sameRegion := traefikLocality.Region != "" && server.Region == traefikLocality.Region
+
+	switch {
+	case traefikLocality.Zone != "" && server.Zone == traefikLocality.Zone && (sameRegion || server.Region == "" || traefikLocality.Region == ""):
+		return locality.TierZone
+	case sameRegion:
+		return locality.TierRegion
+	default:
+		return locality.TierOther
+	}
+}
+
+// serversBalancer is a load-balancer of the servers of a ServersLoadBalancer service.
+type serversBalancer interface {
+	http.Handler
+	healthcheck.StatusSetter
+	healthcheck.StatusUpdater
+
+	Add(name string, handler http.Handler, weight *int)
+}
+
+// newServersBalancer creates the load-balancer matching the strategy of the given service configuration.
+// The defaulted strategy is written back to the configuration, for it to be reported by the API.
+// The slowStartTable keeps the slow start of the servers across the reloads, if not nil.
+func newServersBalancer(service *dynamic.ServersLoadBalancer, slowStartTable *wrr.SlowStartTable) (serversBalancer, error) {
+	if service.Strategy == "" {
+		service.Strategy = dynamic.BalancerStrategyWRR
+	}
+
+	if err := loadbalancer.ValidateSticky(service.Sticky); err != nil {
+		return nil, err
+	}
+
+	if service.SlowStart != nil {
+		if service.Strategy != dynamic.BalancerStrategyWRR {
+			return nil, fmt.Errorf("slow start is not supported by the %q load-balancer strategy", service.Strategy)
+		}
+
+		if service.SlowStart.Aggression <= 0 {
+			return nil, errors.New("slow start aggression must be positive")
+		}
+
+		if service.SlowStart.MinWeightPercent < 0 || service.SlowStart.MinWeightPercent > 100 {
+			return nil, errors.New("slow start minWeightPercent must be between 0 and 100")
+		}
+	}
+
+	// The status of the servers can be changed by the active and the passive health checks.
+	wantHealthCheck := service.HealthCheck != nil || service.PassiveHealthCheck != nil
+
+	switch service.Strategy {
+	case dynamic.BalancerStrategyWRR:
+		balancer := wrr.New(service.Sticky, wantHealthCheck)
+		balancer.SetSlowStart(service.SlowStart, slowStartTable)
+		return balancer, nil
+	case dynamic.BalancerStrategyLeastConn:
+		return leastconn.New(service.Sticky, wantHealthCheck), nil
+	case dynamic.BalancerStrategyP2C:
+		return p2c.New(service.Sticky, wantHealthCheck), nil
+	case dynamic.BalancerStrategyConsistentHash:
+		return chash.New(service.ConsistentHash, wantHealthCheck)
+	default:
+		return nil, fmt.Errorf("unsupported load-balancer strategy %q", service.Strategy)
+	}
+}
+
 // LaunchHealthCheck launches the health checks.
 func (m *Manager) LaunchHealthCheck(ctx context.Context) {
 	for serviceName, hc := range m.healthCheckers {
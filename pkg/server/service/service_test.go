@@ -9,12 +9,16 @@ import (
 	"net/textproto"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
+	"github.com/traefik/traefik/v3/pkg/server/service/loadbalancer/locality"
 	"github.com/traefik/traefik/v3/pkg/testhelpers"
 )
 
@@ -59,6 +63,84 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Succeeds when strategy is leastconn",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.BalancerStrategyLeastConn,
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Succeeds when strategy is p2c",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.BalancerStrategyP2C,
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Succeeds when strategy is chash",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:       dynamic.BalancerStrategyConsistentHash,
+				ConsistentHash: &dynamic.ConsistentHash{RequestHeaderName: "X-User"},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when chash has several criteria",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.BalancerStrategyConsistentHash,
+				ConsistentHash: &dynamic.ConsistentHash{
+					RequestHeaderName: "X-User",
+					CookieName:        "user",
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds when slowStart is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: &dynamic.SlowStart{Duration: ptypes.Duration(time.Minute), Aggression: 1, MinWeightPercent: 10},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when slowStart is set with the leastconn strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:  dynamic.BalancerStrategyLeastConn,
+				SlowStart: &dynamic.SlowStart{Duration: ptypes.Duration(time.Minute), Aggression: 1, MinWeightPercent: 10},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails when slowStart aggression is not positive",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: &dynamic.SlowStart{Duration: ptypes.Duration(time.Minute), MinWeightPercent: 10},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails when strategy is unknown",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: "foobar",
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -454,6 +536,55 @@ func Test1xxResponses(t *testing.T) {
 	}
 }
 
+func TestLocalityTier(t *testing.T) {
+	traefikLocality := &static.Locality{Zone: "eu-west-1a", Region: "eu-west-1"}
+
+	testCases := []struct {
+		desc     string
+		server   dynamic.Server
+		expected int
+	}{
+		{
+			desc:     "same zone",
+			server:   dynamic.Server{Zone: "eu-west-1a", Region: "eu-west-1"},
+			expected: locality.TierZone,
+		},
+		{
+			desc:     "same zone without region",
+			server:   dynamic.Server{Zone: "eu-west-1a"},
+			expected: locality.TierZone,
+		},
+		{
+			desc:     "same zone name in another region",
+			server:   dynamic.Server{Zone: "eu-west-1a", Region: "us-east-1"},
+			expected: locality.TierOther,
+		},
+		{
+			desc:     "same region",
+			server:   dynamic.Server{Zone: "eu-west-1b", Region: "eu-west-1"},
+			expected: locality.TierRegion,
+		},
+		{
+			desc:     "other region",
+			server:   dynamic.Server{Zone: "us-east-1a", Region: "us-east-1"},
+			expected: locality.TierOther,
+		},
+		{
+			desc:     "no locality",
+			expected: locality.TierOther,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, localityTier(traefikLocality, test.server))
+		})
+	}
+}
+
 func TestManager_Build(t *testing.T) {
 	testCases := []struct {
 		desc         string
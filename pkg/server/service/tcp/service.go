@@ -2,15 +2,20 @@ package tcp
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"net"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 	"golang.org/x/net/proxy"
@@ -18,17 +23,34 @@ import (
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	dialerManager *tcp.DialerManager
-	configs       map[string]*runtime.TCPServiceInfo
-	rand          *rand.Rand // For the initial shuffling of load-balancers.
+	dialerManager   *tcp.DialerManager
+	metricsRegistry metrics.Registry
+	configs         map[string]*runtime.TCPServiceInfo
+	healthCheckers  map[string]*healthcheck.ServiceTCPHealthChecker
+	stickyTables    *tcp.StickyTables
+	rand            *rand.Rand // For the initial shuffling of load-balancers.
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration, dialerManager *tcp.DialerManager) *Manager {
+// The stickyTables hold the client bindings of the sticky services, across the managers built for each configuration.
+func NewManager(conf *runtime.Configuration, dialerManager *tcp.DialerManager, metricsRegistry metrics.Registry, stickyTables *tcp.StickyTables) *Manager {
+	// The bindings of the services which are not sticky anymore are dropped.
+	stickyServices := make(map[string]struct{})
+	for name, service := range conf.TCPServices {
+		if service.LoadBalancer != nil && service.LoadBalancer.Sticky != nil ||
+			service.Weighted != nil && service.Weighted.Sticky != nil {
+			stickyServices[name] = struct{}{}
+		}
+	}
+	stickyTables.Retain(stickyServices)
+
 	return &Manager{
-		dialerManager: dialerManager,
-		configs:       conf.TCPServices,
-		rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		dialerManager:   dialerManager,
+		metricsRegistry: metricsRegistry,
+		configs:         conf.TCPServices,
+		healthCheckers:  make(map[string]*healthcheck.ServiceTCPHealthChecker),
+		stickyTables:    stickyTables,
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -52,7 +74,8 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		loadBalancer := tcp.NewWRRLoadBalancer(m.getStickyTable(ctx, serviceQualifiedName, conf.LoadBalancer.Sticky), conf.LoadBalancer.HealthCheck != nil)
+		healthCheckTargets := make(map[string]*healthcheck.TCPHealthCheckTarget)
 
 		if conf.LoadBalancer.TerminationDelay != nil {
 			log.Ctx(ctx).Warn().Msgf("Service %q load balancer uses `TerminationDelay`, but this option is deprecated, please use ServersTransport configuration instead.", serviceName)
@@ -91,14 +114,38 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			hasher := fnv.New64a()
+			_, _ = hasher.Write([]byte(server.Address)) // this will never return an error.
+
+			proxyName := hex.EncodeToString(hasher.Sum(nil))
+
+			loadBalancer.Add(proxyName, handler, nil)
 			logger.Debug().Msg("Creating TCP server")
+
+			// servers are considered UP by default.
+			conf.UpdateServerStatus(server.Address, runtime.StatusUp)
+
+			healthCheckTargets[proxyName] = &healthcheck.TCPHealthCheckTarget{
+				Address: server.Address,
+				Dialer:  dialer,
+			}
+		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			m.healthCheckers[serviceQualifiedName] = healthcheck.NewServiceTCPHealthChecker(
+				ctx,
+				m.metricsRegistry,
+				conf.LoadBalancer.HealthCheck,
+				loadBalancer,
+				conf,
+				healthCheckTargets,
+			)
 		}
 
 		return loadBalancer, nil
 
 	case conf.Weighted != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		loadBalancer := tcp.NewWRRLoadBalancer(m.getStickyTable(ctx, serviceQualifiedName, conf.Weighted.Sticky), conf.Weighted.HealthCheck != nil)
 
 		for _, service := range shuffle(conf.Weighted.Services, m.rand) {
 			handler, err := m.BuildTCP(ctx, service.Name)
@@ -107,7 +154,26 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				return nil, err
 			}
 
-			loadBalancer.AddWeightServer(handler, service.Weight)
+			loadBalancer.Add(service.Name, handler, service.Weight)
+
+			if conf.Weighted.HealthCheck == nil {
+				continue
+			}
+
+			childName := service.Name
+			updater, ok := handler.(healthcheck.StatusUpdater)
+			if !ok {
+				return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", childName, serviceName, handler)
+			}
+
+			if err := updater.RegisterStatusUpdater(func(up bool) {
+				loadBalancer.SetStatus(ctx, childName, up)
+			}); err != nil {
+				return nil, fmt.Errorf("cannot register %v as updater for %v: %w", childName, serviceName, err)
+			}
+
+			logger.Debug().Str("parent", serviceName).Str("child", childName).
+				Msg("Child service will update parent on status change")
 		}
 
 		return loadBalancer, nil
@@ -119,6 +185,29 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 }
 
+// LaunchHealthCheck launches the health checks.
+func (m *Manager) LaunchHealthCheck(ctx context.Context) {
+	for serviceName, hc := range m.healthCheckers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		go hc.Launch(logger.WithContext(ctx))
+	}
+}
+
+// getStickyTable returns the sticky table of the given service, or nil if the service is not sticky.
+func (m *Manager) getStickyTable(ctx context.Context, serviceName string, sticky *dynamic.TCPSticky) *tcp.StickyTable {
+	if sticky == nil {
+		return nil
+	}
+
+	ttl := time.Duration(sticky.TTL)
+	if ttl <= 0 {
+		log.Ctx(ctx).Error().Msg("Sticky TTL smaller than zero")
+		ttl = time.Duration(dynamic.DefaultTCPStickyTTL)
+	}
+
+	return m.stickyTables.Get(serviceName, ttl)
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
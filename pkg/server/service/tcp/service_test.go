@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -234,6 +235,106 @@ func TestManager_BuildTCP(t *testing.T) {
 			providerName:  "provider-1",
 			expectedError: "TCP dialer not found myServersTransport@provider-1",
 		},
+		{
+			desc:        "health check",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+							HealthCheck: &dynamic.TCPServerHealthCheck{},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "sticky load balancer",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+							Sticky: &dynamic.TCPSticky{TTL: dynamic.DefaultTCPStickyTTL},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "weighted service with health check",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Weighted: &dynamic.TCPWeightedRoundRobin{
+							Services: []dynamic.TCPWRRService{
+								{Name: "child"},
+							},
+							HealthCheck: &dynamic.HealthCheck{},
+						},
+					},
+				},
+				"child@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+							HealthCheck: &dynamic.TCPServerHealthCheck{},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "weighted service with health check on a child without health check",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Weighted: &dynamic.TCPWeightedRoundRobin{
+							Services: []dynamic.TCPWRRService{
+								{Name: "child"},
+							},
+							HealthCheck: &dynamic.HealthCheck{},
+						},
+					},
+				},
+				"child@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "cannot register child as updater for serviceName: healthCheck not enabled in config for this weighted service",
+		},
 	}
 
 	for _, test := range testCases {
@@ -248,7 +349,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			}, dialerManager)
+			}, dialerManager, metrics.NewVoidRegistry(), tcp.NewStickyTables())
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
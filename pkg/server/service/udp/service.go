@@ -2,30 +2,52 @@ package udp
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"net"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
 // Manager handles UDP services creation.
 type Manager struct {
-	configs map[string]*runtime.UDPServiceInfo
-	rand    *rand.Rand // For the initial shuffling of load-balancers.
+	metricsRegistry metrics.Registry
+	configs         map[string]*runtime.UDPServiceInfo
+	healthCheckers  map[string]*healthcheck.ServiceUDPHealthChecker
+	stickyTables    *udp.StickyTables
+	rand            *rand.Rand // For the initial shuffling of load-balancers.
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+// The stickyTables hold the client bindings of the sticky services, across the managers built for each configuration.
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry, stickyTables *udp.StickyTables) *Manager {
+	// The bindings of the services which are not sticky anymore are dropped.
+	stickyServices := make(map[string]struct{})
+	for name, service := range conf.UDPServices {
+		if service.LoadBalancer != nil && service.LoadBalancer.Sticky != nil ||
+			service.Weighted != nil && service.Weighted.Sticky != nil {
+			stickyServices[name] = struct{}{}
+		}
+	}
+	stickyTables.Retain(stickyServices)
+
 	return &Manager{
-		configs: conf.UDPServices,
-		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		metricsRegistry: metricsRegistry,
+		configs:         conf.UDPServices,
+		healthCheckers:  make(map[string]*healthcheck.ServiceUDPHealthChecker),
+		stickyTables:    stickyTables,
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -49,7 +71,8 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := udp.NewWRRLoadBalancer()
+		loadBalancer := udp.NewWRRLoadBalancer(m.getStickyTable(ctx, serviceQualifiedName, conf.LoadBalancer.Sticky), conf.LoadBalancer.HealthCheck != nil)
+		healthCheckTargets := make(map[string]string)
 
 		for index, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
 			srvLogger := logger.With().
@@ -67,14 +90,35 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			hasher := fnv.New64a()
+			_, _ = hasher.Write([]byte(server.Address)) // this will never return an error.
+
+			proxyName := hex.EncodeToString(hasher.Sum(nil))
+
+			loadBalancer.Add(proxyName, handler, nil)
 			srvLogger.Debug().Msg("Creating UDP server")
+
+			// servers are considered UP by default.
+			conf.UpdateServerStatus(server.Address, runtime.StatusUp)
+
+			healthCheckTargets[proxyName] = server.Address
+		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			m.healthCheckers[serviceQualifiedName] = healthcheck.NewServiceUDPHealthChecker(
+				ctx,
+				m.metricsRegistry,
+				conf.LoadBalancer.HealthCheck,
+				loadBalancer,
+				conf,
+				healthCheckTargets,
+			)
 		}
 
 		return loadBalancer, nil
 
 	case conf.Weighted != nil:
-		loadBalancer := udp.NewWRRLoadBalancer()
+		loadBalancer := udp.NewWRRLoadBalancer(m.getStickyTable(ctx, serviceQualifiedName, conf.Weighted.Sticky), conf.Weighted.HealthCheck != nil)
 
 		for _, service := range shuffle(conf.Weighted.Services, m.rand) {
 			handler, err := m.BuildUDP(ctx, service.Name)
@@ -83,7 +127,26 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				return nil, err
 			}
 
-			loadBalancer.AddWeightedServer(handler, service.Weight)
+			loadBalancer.Add(service.Name, handler, service.Weight)
+
+			if conf.Weighted.HealthCheck == nil {
+				continue
+			}
+
+			childName := service.Name
+			updater, ok := handler.(healthcheck.StatusUpdater)
+			if !ok {
+				return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", childName, serviceName, handler)
+			}
+
+			if err := updater.RegisterStatusUpdater(func(up bool) {
+				loadBalancer.SetStatus(ctx, childName, up)
+			}); err != nil {
+				return nil, fmt.Errorf("cannot register %v as updater for %v: %w", childName, serviceName, err)
+			}
+
+			logger.Debug().Str("parent", serviceName).Str("child", childName).
+				Msg("Child service will update parent on status change")
 		}
 
 		return loadBalancer, nil
@@ -95,6 +158,29 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	}
 }
 
+// LaunchHealthCheck launches the health checks.
+func (m *Manager) LaunchHealthCheck(ctx context.Context) {
+	for serviceName, hc := range m.healthCheckers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		go hc.Launch(logger.WithContext(ctx))
+	}
+}
+
+// getStickyTable returns the sticky table of the given service, or nil if the service is not sticky.
+func (m *Manager) getStickyTable(ctx context.Context, serviceName string, sticky *dynamic.UDPSticky) *udp.StickyTable {
+	if sticky == nil {
+		return nil
+	}
+
+	ttl := time.Duration(sticky.TTL)
+	if ttl <= 0 {
+		log.Ctx(ctx).Error().Msg("Sticky TTL smaller than zero")
+		ttl = time.Duration(dynamic.DefaultUDPStickyTTL)
+	}
+
+	return m.stickyTables.Get(serviceName, ttl, sticky.SourcePort)
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
+	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
 func TestManager_BuildUDP(t *testing.T) {
@@ -171,6 +173,102 @@ func TestManager_BuildUDP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "health check",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:53",
+								},
+							},
+							HealthCheck: &dynamic.UDPServerHealthCheck{},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "sticky load balancer",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:53",
+								},
+							},
+							Sticky: &dynamic.UDPSticky{TTL: dynamic.DefaultUDPStickyTTL, SourcePort: true},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "weighted service with health check",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						Weighted: &dynamic.UDPWeightedRoundRobin{
+							Services: []dynamic.UDPWRRService{
+								{Name: "child"},
+							},
+							HealthCheck: &dynamic.HealthCheck{},
+						},
+					},
+				},
+				"child@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:53",
+								},
+							},
+							HealthCheck: &dynamic.UDPServerHealthCheck{},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "weighted service with health check on a child without health check",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						Weighted: &dynamic.UDPWeightedRoundRobin{
+							Services: []dynamic.UDPWRRService{
+								{Name: "child"},
+							},
+							HealthCheck: &dynamic.HealthCheck{},
+						},
+					},
+				},
+				"child@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:53",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "cannot register child as updater for serviceName: healthCheck not enabled in config for this weighted service",
+		},
 	}
 
 	for _, test := range testCases {
@@ -180,7 +278,7 @@ func TestManager_BuildUDP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				UDPServices: test.configs,
-			})
+			}, metrics.NewVoidRegistry(), udp.NewStickyTables())
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
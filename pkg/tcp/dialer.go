@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -35,6 +36,16 @@ func (d tcpDialer) TerminationDelay() time.Duration {
 	return d.terminationDelay
 }
 
+// DialContext connects to the address on the named network using the provided context.
+// The context is only honored if the underlying dialer supports it.
+func (d tcpDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if dialer, ok := d.Dialer.(proxy.ContextDialer); ok {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return d.Dial(network, address)
+}
+
 // SpiffeX509Source allows to retrieve a x509 SVID and bundle.
 type SpiffeX509Source interface {
 	x509svid.Source
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,11 +14,16 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
+// defaultTLSHandshakeTimeout is the maximum duration of the TLS handshake
+// performed to build the PROXY protocol TLVs, before dialing the backend.
+const defaultTLSHandshakeTimeout = 10 * time.Second
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
-	address       string
-	proxyProtocol *dynamic.ProxyProtocol
-	dialer        Dialer
+	address             string
+	proxyProtocol       *dynamic.ProxyProtocol
+	dialer              Dialer
+	tlsHandshakeTimeout time.Duration
 }
 
 // NewProxy creates a new Proxy.
@@ -26,10 +32,15 @@ func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, dialer Diale
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
+	if proxyProtocol != nil && proxyProtocol.TLVs != nil && proxyProtocol.Version != 2 {
+		return nil, fmt.Errorf("proxyProtocol TLVs are not supported by version %d", proxyProtocol.Version)
+	}
+
 	return &Proxy{
-		address:       address,
-		proxyProtocol: proxyProtocol,
-		dialer:        dialer,
+		address:             address,
+		proxyProtocol:       proxyProtocol,
+		dialer:              dialer,
+		tlsHandshakeTimeout: defaultTLSHandshakeTimeout,
 	}, nil
 }
 
@@ -43,6 +54,18 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	// The header is built before dialing the backend,
+	// so that no backend connection is opened for the clients failing the TLS handshake.
+	var header *proxyproto.Header
+	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
+		var err error
+		header, err = p.proxyProtocolHeader(conn)
+		if err != nil {
+			log.Debug().Err(err).Msg("Error while building TCP proxy protocol headers")
+			return
+		}
+	}
+
 	connBackend, err := p.dialBackend()
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
@@ -53,8 +76,7 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	defer connBackend.Close()
 	errChan := make(chan error)
 
-	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
-		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+	if header != nil {
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.Error().Err(err).Msg("Error while writing TCP proxy protocol headers to backend connection")
 			return
@@ -79,6 +101,39 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	<-errChan
 }
 
+// proxyProtocolHeader builds the PROXY protocol header sent to the backend,
+// with the TLVs describing the TLS connection terminated by Traefik, if configured.
+func (p Proxy) proxyProtocolHeader(conn WriteCloser) (*proxyproto.Header, error) {
+	header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+	if p.proxyProtocol.TLVs == nil {
+		return header, nil
+	}
+
+	tlsConn := getTLSConn(conn)
+	if tlsConn == nil {
+		return header, nil
+	}
+
+	// The handshake is usually not done yet, as nothing has been read from the connection.
+	ctx, cancel := context.WithTimeout(context.Background(), p.tlsHandshakeTimeout)
+	defer cancel()
+
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+
+	tlvs, err := buildProxyProtocolTLVs(p.proxyProtocol.TLVs, tlsConn.ConnectionState())
+	if err != nil {
+		return nil, fmt.Errorf("building TLVs: %w", err)
+	}
+
+	if err := header.SetTLVs(tlvs); err != nil {
+		return nil, fmt.Errorf("setting TLVs: %w", err)
+	}
+
+	return header, nil
+}
+
 func (p Proxy) dialBackend() (WriteCloser, error) {
 	conn, err := p.dialer.Dial("tcp", p.address)
 	if err != nil {
@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"io"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -173,3 +175,188 @@ func TestProxyProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestNewProxy_proxyProtocolTLVs(t *testing.T) {
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	_, err := NewProxy(":80", &dynamic.ProxyProtocol{Version: 1, TLVs: &dynamic.ProxyProtocolTLVs{SSL: true}}, dialer)
+	assert.Error(t, err)
+
+	_, err = NewProxy(":80", &dynamic.ProxyProtocol{Version: 2, TLVs: &dynamic.ProxyProtocolTLVs{SSL: true}}, dialer)
+	assert.NoError(t, err)
+}
+
+func TestProxyProtocol_TLVs(t *testing.T) {
+	serverCert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	clientCertPEM, clientKeyPEM, err := generate.KeyPair("client.localhost", time.Time{})
+	require.NoError(t, err)
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc       string
+		tlvs       *dynamic.ProxyProtocolTLVs
+		noTLS      bool
+		clientCert bool
+		expected   ProxyProtocolInfo
+	}{
+		{
+			desc: "no TLVs",
+		},
+		{
+			desc:     "all TLVs",
+			tlvs:     &dynamic.ProxyProtocolTLVs{Authority: true, ALPN: true, SSL: true},
+			expected: ProxyProtocolInfo{Authority: "foo.localhost", ALPN: "h2", TLSVersion: "TLSv1.2", TLSCipher: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			desc:       "all TLVs with a client certificate",
+			tlvs:       &dynamic.ProxyProtocolTLVs{Authority: true, ALPN: true, SSL: true},
+			clientCert: true,
+			expected:   ProxyProtocolInfo{Authority: "foo.localhost", ALPN: "h2", TLSVersion: "TLSv1.2", TLSCipher: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", TLSClientCN: generate.DefaultDomain},
+		},
+		{
+			desc:     "authority only",
+			tlvs:     &dynamic.ProxyProtocolTLVs{Authority: true},
+			expected: ProxyProtocolInfo{Authority: "foo.localhost"},
+		},
+		{
+			desc:  "not a TLS connection",
+			tlvs:  &dynamic.ProxyProtocolTLVs{Authority: true, ALPN: true, SSL: true},
+			noTLS: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			proxyBackendListener := &proxyproto.Listener{Listener: backendListener}
+			t.Cleanup(func() { _ = proxyBackendListener.Close() })
+
+			infoCh := make(chan ProxyProtocolInfo, 1)
+			go func() {
+				conn, err := proxyBackendListener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				info, _ := GetProxyProtocolInfo(conn)
+				infoCh <- info
+
+				_, _ = conn.Write([]byte("PONG"))
+			}()
+
+			dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+			proxy, err := NewProxy(backendListener.Addr().String(), &dynamic.ProxyProtocol{Version: 2, TLVs: test.tlvs}, dialer)
+			require.NoError(t, err)
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = proxyListener.Close() })
+
+			go func() {
+				conn, err := proxyListener.Accept()
+				if err != nil {
+					return
+				}
+
+				if test.noTLS {
+					proxy.ServeTCP(conn.(*net.TCPConn))
+					return
+				}
+
+				proxy.ServeTCP(tls.Server(conn, &tls.Config{
+					Certificates: []tls.Certificate{*serverCert},
+					NextProtos:   []string{"h2", "http/1.1"},
+					ClientAuth:   tls.RequestClientCert,
+					// The cipher suites are not configurable with TLS 1.3.
+					MaxVersion:   tls.VersionTLS12,
+					CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+				}))
+			}()
+
+			var conn net.Conn
+			if test.noTLS {
+				conn, err = net.Dial("tcp", proxyListener.Addr().String())
+			} else {
+				config := &tls.Config{
+					ServerName:         "foo.localhost",
+					NextProtos:         []string{"h2"},
+					InsecureSkipVerify: true,
+				}
+				if test.clientCert {
+					config.Certificates = []tls.Certificate{clientCert}
+				}
+
+				conn, err = tls.Dial("tcp", proxyListener.Addr().String(), config)
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			buf := make([]byte, 4)
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+			assert.Equal(t, "PONG", string(buf))
+
+			assert.Equal(t, test.expected, <-infoCh)
+		})
+	}
+}
+
+func TestProxyProtocol_TLVs_handshakeTimeout(t *testing.T) {
+	serverCert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	accepted := make(chan struct{}, 1)
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+
+		accepted <- struct{}{}
+	}()
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	proxy, err := NewProxy(backendListener.Addr().String(), &dynamic.ProxyProtocol{Version: 2, TLVs: &dynamic.ProxyProtocolTLVs{SSL: true}}, dialer)
+	require.NoError(t, err)
+	proxy.tlsHandshakeTimeout = 50 * time.Millisecond
+
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		proxy.ServeTCP(tls.Server(server, &tls.Config{Certificates: []tls.Certificate{*serverCert}}))
+	}()
+
+	// The client never sends its ClientHello.
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the TLS handshake did not time out")
+	}
+
+	select {
+	case <-accepted:
+		t.Fatal("the backend was dialed before the TLS handshake")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,167 @@
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+)
+
+type proxyProtocolInfoKey struct{}
+
+// ProxyProtocolInfo holds the information carried by the TLVs of the PROXY protocol header received with a connection.
+type ProxyProtocolInfo struct {
+	// Authority is the host name requested by the client, usually the SNI (PP2_TYPE_AUTHORITY).
+	Authority string
+	// ALPN is the application protocol negotiated with the client (PP2_TYPE_ALPN).
+	ALPN string
+	// TLSVersion is the TLS version used by the client (PP2_SUBTYPE_SSL_VERSION).
+	TLSVersion string
+	// TLSCipher is the TLS cipher used by the client (PP2_SUBTYPE_SSL_CIPHER).
+	TLSCipher string
+	// TLSClientCN is the common name of the client certificate (PP2_SUBTYPE_SSL_CN).
+	TLSClientCN string
+}
+
+// GetProxyProtocolInfo returns the information carried by the TLVs of the PROXY protocol header received with the given connection.
+// It returns false if the connection was not received with a PROXY protocol version 2 header.
+func GetProxyProtocolInfo(conn net.Conn) (ProxyProtocolInfo, bool) {
+	header := getProxyHeader(conn)
+	if header == nil || header.Version != 2 {
+		return ProxyProtocolInfo{}, false
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		return ProxyProtocolInfo{}, false
+	}
+
+	var info ProxyProtocolInfo
+	for _, tlv := range tlvs {
+		switch tlv.Type {
+		case proxyproto.PP2_TYPE_AUTHORITY:
+			info.Authority = string(tlv.Value)
+		case proxyproto.PP2_TYPE_ALPN:
+			info.ALPN = string(tlv.Value)
+		}
+	}
+
+	if ssl, ok := tlvparse.FindSSL(tlvs); ok {
+		for _, tlv := range ssl.TLV {
+			switch tlv.Type {
+			case proxyproto.PP2_SUBTYPE_SSL_VERSION:
+				info.TLSVersion = string(tlv.Value)
+			case proxyproto.PP2_SUBTYPE_SSL_CIPHER:
+				info.TLSCipher = string(tlv.Value)
+			case proxyproto.PP2_SUBTYPE_SSL_CN:
+				info.TLSClientCN = string(tlv.Value)
+			}
+		}
+	}
+
+	return info, true
+}
+
+// AddProxyProtocolInfoOnContext adds the given PROXY protocol information on the context.
+func AddProxyProtocolInfoOnContext(ctx context.Context, info ProxyProtocolInfo) context.Context {
+	return context.WithValue(ctx, proxyProtocolInfoKey{}, info)
+}
+
+// ProxyProtocolInfoFromContext returns the PROXY protocol information stored on the context, if any.
+func ProxyProtocolInfoFromContext(ctx context.Context) (ProxyProtocolInfo, bool) {
+	info, ok := ctx.Value(proxyProtocolInfoKey{}).(ProxyProtocolInfo)
+	return info, ok
+}
+
+// getProxyHeader returns the PROXY protocol header received with the given connection,
+// going through the connections wrapping the one accepted by the entry point.
+func getProxyHeader(conn net.Conn) *proxyproto.Header {
+	for conn != nil {
+		switch c := conn.(type) {
+		case interface{ ProxyHeader() *proxyproto.Header }:
+			return c.ProxyHeader()
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// getTLSConn returns the TLS connection terminated by Traefik, if any,
+// going through the connections wrapping it.
+func getTLSConn(conn net.Conn) *tls.Conn {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			return c
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// buildProxyProtocolTLVs builds the TLVs describing the TLS connection, as configured.
+func buildProxyProtocolTLVs(config *dynamic.ProxyProtocolTLVs, state tls.ConnectionState) ([]proxyproto.TLV, error) {
+	var tlvs []proxyproto.TLV
+
+	if config.Authority && state.ServerName != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte(state.ServerName)})
+	}
+
+	if config.ALPN && state.NegotiatedProtocol != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte(state.NegotiatedProtocol)})
+	}
+
+	if config.SSL {
+		ssl := tlvparse.PP2SSL{
+			Client: tlvparse.PP2_BITFIELD_CLIENT_SSL,
+			// Verify is zero only if the client presented a certificate which was successfully verified.
+			Verify: 1,
+			TLV: []proxyproto.TLV{
+				{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte("TLSv" + traefiktls.GetVersion(&state))},
+				{Type: proxyproto.PP2_SUBTYPE_SSL_CIPHER, Value: []byte(traefiktls.GetCipherName(&state))},
+			},
+		}
+
+		if len(state.PeerCertificates) > 0 {
+			ssl.Client |= tlvparse.PP2_BITFIELD_CLIENT_CERT_SESS
+			if !state.DidResume {
+				ssl.Client |= tlvparse.PP2_BITFIELD_CLIENT_CERT_CONN
+			}
+
+			if len(state.VerifiedChains) > 0 {
+				ssl.Verify = 0
+			}
+
+			cert := state.PeerCertificates[0]
+			if cert.Subject.CommonName != "" {
+				ssl.TLV = append(ssl.TLV, proxyproto.TLV{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte(cert.Subject.CommonName)})
+			}
+
+			ssl.TLV = append(ssl.TLV,
+				proxyproto.TLV{Type: proxyproto.PP2_SUBTYPE_SSL_SIG_ALG, Value: []byte(cert.SignatureAlgorithm.String())},
+				proxyproto.TLV{Type: proxyproto.PP2_SUBTYPE_SSL_KEY_ALG, Value: []byte(cert.PublicKeyAlgorithm.String())},
+			)
+		}
+
+		tlv, err := ssl.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		tlvs = append(tlvs, tlv)
+	}
+
+	return tlvs, nil
+}
@@ -0,0 +1,107 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wrappedConn struct {
+	net.Conn
+}
+
+func (c wrappedConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func TestGetProxyProtocolInfo(t *testing.T) {
+	ssl, err := tlvparse.PP2SSL{
+		Client: tlvparse.PP2_BITFIELD_CLIENT_SSL | tlvparse.PP2_BITFIELD_CLIENT_CERT_CONN,
+		TLV: []proxyproto.TLV{
+			{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte("TLSv1.3")},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CIPHER, Value: []byte("TLS_AES_128_GCM_SHA256")},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte("client")},
+		},
+	}.Marshal()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		version  byte
+		tlvs     []proxyproto.TLV
+		expected ProxyProtocolInfo
+		found    bool
+	}{
+		{
+			desc:    "version 1",
+			version: 1,
+		},
+		{
+			desc:    "version 2 without TLVs",
+			version: 2,
+			found:   true,
+		},
+		{
+			desc:    "version 2 with TLVs",
+			version: 2,
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.localhost")},
+				{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")},
+				ssl,
+			},
+			expected: ProxyProtocolInfo{
+				Authority:   "foo.localhost",
+				ALPN:        "h2",
+				TLSVersion:  "TLSv1.3",
+				TLSCipher:   "TLS_AES_128_GCM_SHA256",
+				TLSClientCN: "client",
+			},
+			found: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client, server := net.Pipe()
+			t.Cleanup(func() {
+				_ = client.Close()
+				_ = server.Close()
+			})
+
+			header := proxyproto.HeaderProxyFromAddrs(test.version,
+				&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+				&net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443},
+			)
+			require.NoError(t, header.SetTLVs(test.tlvs))
+
+			go func() {
+				_, _ = header.WriteTo(client)
+			}()
+
+			// The connection accepted by the entry point is usually wrapped.
+			conn := wrappedConn{Conn: wrappedConn{Conn: proxyproto.NewConn(server)}}
+
+			info, found := GetProxyProtocolInfo(conn)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.expected, info)
+		})
+	}
+}
+
+func TestGetProxyProtocolInfo_noHeader(t *testing.T) {
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	_, found := GetProxyProtocolInfo(wrappedConn{Conn: server})
+	assert.False(t, found)
+}
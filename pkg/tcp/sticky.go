@@ -0,0 +1,133 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// StickyTable binds client IPs to the name of the server which handled their last connection.
+// A binding expires once the TTL has elapsed since the last connection of the client.
+type StickyTable struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]stickyEntry
+	lastSweep time.Time
+
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
+}
+
+type stickyEntry struct {
+	server    string
+	expiresAt time.Time
+}
+
+// NewStickyTable creates a new StickyTable.
+func NewStickyTable(ttl time.Duration) *StickyTable {
+	return &StickyTable{
+		ttl:     ttl,
+		entries: make(map[string]stickyEntry),
+		timeNow: time.Now,
+	}
+}
+
+// SetTTL updates the TTL of the bindings.
+// It applies to the bindings refreshed from now on.
+func (t *StickyTable) SetTTL(ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ttl = ttl
+}
+
+// get returns the name of the server bound to the given client IP, if the binding is not expired.
+func (t *StickyTable) get(clientIP string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[clientIP]
+	if !ok {
+		return "", false
+	}
+
+	if !t.timeNow().Before(entry.expiresAt) {
+		delete(t.entries, clientIP)
+		return "", false
+	}
+
+	return entry.server, true
+}
+
+// set binds the given client IP to the given server name, and refreshes the binding expiration.
+func (t *StickyTable) set(clientIP, server string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.timeNow()
+	t.entries[clientIP] = stickyEntry{server: server, expiresAt: now.Add(t.ttl)}
+
+	// Expired bindings of clients which do not come back are swept at most once per TTL.
+	if now.Sub(t.lastSweep) < t.ttl {
+		return
+	}
+
+	t.lastSweep = now
+	for ip, entry := range t.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(t.entries, ip)
+		}
+	}
+}
+
+// StickyTables holds the sticky tables of the services, keyed by service name,
+// in order to keep the client bindings across the dynamic configuration reloads.
+type StickyTables struct {
+	mu     sync.Mutex
+	tables map[string]*StickyTable
+}
+
+// NewStickyTables creates a new StickyTables.
+func NewStickyTables() *StickyTables {
+	return &StickyTables{tables: make(map[string]*StickyTable)}
+}
+
+// Get returns the sticky table of the given service, creating it if needed, with the given TTL.
+func (s *StickyTables) Get(serviceName string, ttl time.Duration) *StickyTable {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.tables[serviceName]
+	if !ok {
+		table = NewStickyTable(ttl)
+		s.tables[serviceName] = table
+		return table
+	}
+
+	table.SetTTL(ttl)
+	return table
+}
+
+// Retain drops the sticky tables of the services which are not in the given list.
+func (s *StickyTables) Retain(serviceNames map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.tables {
+		if _, ok := serviceNames[name]; !ok {
+			delete(s.tables, name)
+		}
+	}
+}
+
+// clientIP returns the IP of the remote peer of the given connection.
+func clientIP(conn WriteCloser) string {
+	addr := conn.RemoteAddr().String()
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
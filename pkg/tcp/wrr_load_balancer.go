@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -9,6 +10,7 @@ import (
 
 type server struct {
 	Handler
+	name   string
 	weight int
 }
 
@@ -18,19 +20,37 @@ type WRRLoadBalancer struct {
 	lock          sync.Mutex
 	currentWeight int
 	index         int
+
+	// sticky binds the client IPs to the server which handled their last connection, if not nil.
+	sticky *StickyTable
+
+	wantsHealthCheck bool
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via Add, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
-func NewWRRLoadBalancer() *WRRLoadBalancer {
+// If sticky is not nil, the connections of a client IP are forwarded to the same server,
+// as long as it is available.
+func NewWRRLoadBalancer(sticky *StickyTable, wantsHealthCheck bool) *WRRLoadBalancer {
 	return &WRRLoadBalancer{
-		index: -1,
+		index:            -1,
+		sticky:           sticky,
+		wantsHealthCheck: wantsHealthCheck,
+		status:           make(map[string]struct{}),
 	}
 }
 
 // ServeTCP forwards the connection to the right service.
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 	b.lock.Lock()
-	next, err := b.next()
+	next, err := b.nextServer(conn)
 	b.lock.Unlock()
 
 	if err != nil {
@@ -42,14 +62,8 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 	next.ServeTCP(conn)
 }
 
-// AddServer appends a server to the existing list.
-func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
-	w := 1
-	b.AddWeightServer(serverHandler, &w)
-}
-
-// AddWeightServer appends a server to the existing list with a weight.
-func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+// Add appends a server to the existing list with a name and a weight.
+func (b *WRRLoadBalancer) Add(name string, serverHandler Handler, weight *int) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
@@ -57,22 +71,81 @@ func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
 	if weight != nil {
 		w = *weight
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+	b.servers = append(b.servers, server{Handler: serverHandler, name: name, weight: w})
+	b.status[name] = struct{}{}
+}
+
+// SetStatus sets on the balancer that its given child is now of the given
+// status. balancerName is only needed for logging purposes.
+func (b *WRRLoadBalancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *WRRLoadBalancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this weighted service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
 }
 
+// maxWeight returns the maximum weight across all healthy servers.
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
-		if s.weight > max {
+		if _, ok := b.status[s.name]; ok && s.weight > max {
 			max = s.weight
 		}
 	}
 	return max
 }
 
+// weightGcd returns the GCD of the weights across all healthy servers.
 func (b *WRRLoadBalancer) weightGcd() int {
 	divisor := -1
 	for _, s := range b.servers {
+		if _, ok := b.status[s.name]; !ok {
+			continue
+		}
+
 		if divisor == -1 {
 			divisor = s.weight
 		} else {
@@ -89,8 +162,39 @@ func gcd(a, b int) int {
 	return a
 }
 
+// nextServer returns the server bound to the client IP of the given connection if it is still available,
+// or the next server of the rotation otherwise.
+func (b *WRRLoadBalancer) nextServer(conn WriteCloser) (Handler, error) {
+	if b.sticky == nil {
+		return b.next()
+	}
+
+	ip := clientIP(conn)
+
+	if name, ok := b.sticky.get(ip); ok {
+		if _, ok := b.status[name]; ok {
+			for _, srv := range b.servers {
+				if srv.name == name {
+					b.sticky.set(ip, name)
+					return srv, nil
+				}
+			}
+		}
+
+		log.Debug().Msgf("Sticky server %s is not available anymore, choosing a new one", name)
+	}
+
+	next, err := b.next()
+	if err != nil {
+		return nil, err
+	}
+
+	b.sticky.set(ip, next.(server).name)
+	return next, nil
+}
+
 func (b *WRRLoadBalancer) next() (Handler, error) {
-	if len(b.servers) == 0 {
+	if len(b.servers) == 0 || len(b.status) == 0 {
 		return nil, errors.New("no servers in the pool")
 	}
 
@@ -100,7 +204,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 
 	// Maximum weight across all enabled servers
 	max := b.maxWeight()
-	if max == 0 {
+	if max <= 0 {
 		return nil, errors.New("all servers have 0 weight")
 	}
 
@@ -116,6 +220,9 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
+		if _, ok := b.status[srv.name]; !ok {
+			continue
+		}
 		if srv.weight >= b.currentWeight {
 			return srv, nil
 		}
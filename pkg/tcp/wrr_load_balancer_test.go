@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"net"
 	"testing"
 	"time"
@@ -10,8 +11,9 @@ import (
 )
 
 type fakeConn struct {
-	writeCall map[string]int
-	closeCall int
+	writeCall  map[string]int
+	closeCall  int
+	remoteAddr net.Addr
 }
 
 func (f *fakeConn) Read(b []byte) (n int, err error) {
@@ -33,7 +35,10 @@ func (f *fakeConn) LocalAddr() net.Addr {
 }
 
 func (f *fakeConn) RemoteAddr() net.Addr {
-	panic("implement me")
+	if f.remoteAddr == nil {
+		panic("implement me")
+	}
+	return f.remoteAddr
 }
 
 func (f *fakeConn) SetDeadline(t time.Time) error {
@@ -125,10 +130,10 @@ func TestLoadBalancing(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			balancer := NewWRRLoadBalancer()
+			balancer := NewWRRLoadBalancer(nil, false)
 			for server, weight := range test.serversWeight {
 				server := server
-				balancer.AddWeightServer(HandlerFunc(func(conn WriteCloser) {
+				balancer.Add(server, HandlerFunc(func(conn WriteCloser) {
 					_, err := conn.Write([]byte(server))
 					require.NoError(t, err)
 				}), &weight)
@@ -144,3 +149,114 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancingWithServerDown(t *testing.T) {
+	balancer := NewWRRLoadBalancer(nil, false)
+	for _, server := range []string{"h1", "h2", "h3"} {
+		server := server
+		balancer.Add(server, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}), nil)
+	}
+
+	balancer.SetStatus(context.Background(), "h2", false)
+
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	assert.Equal(t, map[string]int{"h1": 2, "h3": 2}, conn.writeCall)
+
+	balancer.SetStatus(context.Background(), "h1", false)
+	balancer.SetStatus(context.Background(), "h3", false)
+
+	balancer.ServeTCP(conn)
+	assert.Equal(t, 1, conn.closeCall)
+
+	balancer.SetStatus(context.Background(), "h2", true)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 2; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	assert.Equal(t, map[string]int{"h2": 2}, conn.writeCall)
+}
+
+func TestLoadBalancingStatusPropagation(t *testing.T) {
+	balancer := NewWRRLoadBalancer(nil, false)
+	err := balancer.RegisterStatusUpdater(func(up bool) {})
+	require.Error(t, err)
+
+	balancer = NewWRRLoadBalancer(nil, true)
+	balancer.Add("h1", HandlerFunc(func(conn WriteCloser) {}), nil)
+	balancer.Add("h2", HandlerFunc(func(conn WriteCloser) {}), nil)
+
+	var statuses []bool
+	err = balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "h1", false)
+	balancer.SetStatus(context.Background(), "h2", false)
+	balancer.SetStatus(context.Background(), "h1", true)
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func TestLoadBalancingSticky(t *testing.T) {
+	table := NewStickyTable(time.Minute)
+
+	now := time.Now()
+	table.timeNow = func() time.Time { return now }
+
+	newBalancer := func(servers ...string) *WRRLoadBalancer {
+		balancer := NewWRRLoadBalancer(table, false)
+		for _, server := range servers {
+			server := server
+			balancer.Add(server, HandlerFunc(func(conn WriteCloser) {
+				_, err := conn.Write([]byte(server))
+				require.NoError(t, err)
+			}), nil)
+		}
+		return balancer
+	}
+
+	serve := func(balancer *WRRLoadBalancer, ip string, calls int) map[string]int {
+		conn := &fakeConn{
+			writeCall:  make(map[string]int),
+			remoteAddr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000},
+		}
+		for i := 0; i < calls; i++ {
+			balancer.ServeTCP(conn)
+		}
+		return conn.writeCall
+	}
+
+	balancer := newBalancer("h1", "h2", "h3")
+
+	// The connections of a client go to the same server, while the clients are spread across the servers.
+	assert.Equal(t, map[string]int{"h1": 3}, serve(balancer, "10.0.0.1", 3))
+	assert.Equal(t, map[string]int{"h2": 3}, serve(balancer, "10.0.0.2", 3))
+
+	// The client is bound to a new server when its server is down.
+	balancer.SetStatus(context.Background(), "h1", false)
+	assert.Equal(t, map[string]int{"h3": 2}, serve(balancer, "10.0.0.1", 2))
+
+	balancer.SetStatus(context.Background(), "h1", true)
+	assert.Equal(t, map[string]int{"h3": 1}, serve(balancer, "10.0.0.1", 1))
+
+	// The bindings survive a new balancer, built with the same table,
+	// and the client is bound to a new server when its server has been removed.
+	balancer = newBalancer("h1", "h2")
+	assert.Equal(t, map[string]int{"h2": 1}, serve(balancer, "10.0.0.2", 1))
+	assert.Equal(t, map[string]int{"h1": 2}, serve(balancer, "10.0.0.1", 2))
+
+	// The binding expires after the TTL.
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, map[string]int{"h2": 1}, serve(balancer, "10.0.0.1", 1))
+	assert.Len(t, table.entries, 1)
+}
@@ -0,0 +1,148 @@
+package udp
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// StickyTable binds clients to the name of the server which handled their last session.
+// The clients are identified by their source IP, or by their source IP and port.
+// A binding expires once the TTL has elapsed since the last session of the client.
+type StickyTable struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	sourcePort bool
+	entries    map[string]stickyEntry
+	lastSweep  time.Time
+
+	// timeNow can be overridden for testing purposes.
+	timeNow func() time.Time
+}
+
+type stickyEntry struct {
+	server    string
+	expiresAt time.Time
+}
+
+// NewStickyTable creates a new StickyTable.
+// If sourcePort is true, the clients are identified by their source IP and port.
+func NewStickyTable(ttl time.Duration, sourcePort bool) *StickyTable {
+	return &StickyTable{
+		ttl:        ttl,
+		sourcePort: sourcePort,
+		entries:    make(map[string]stickyEntry),
+		timeNow:    time.Now,
+	}
+}
+
+// Update updates the TTL of the bindings, and the identification of the clients.
+// The TTL applies to the bindings refreshed from now on.
+func (t *StickyTable) Update(ttl time.Duration, sourcePort bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ttl = ttl
+
+	// The existing bindings do not match the new identification of the clients.
+	if t.sourcePort != sourcePort {
+		t.sourcePort = sourcePort
+		t.entries = make(map[string]stickyEntry)
+	}
+}
+
+// get returns the name of the server bound to the given client, if the binding is not expired.
+func (t *StickyTable) get(client net.Addr) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := t.key(client)
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if !t.timeNow().Before(entry.expiresAt) {
+		delete(t.entries, key)
+		return "", false
+	}
+
+	return entry.server, true
+}
+
+// set binds the given client to the given server name, and refreshes the binding expiration.
+func (t *StickyTable) set(client net.Addr, server string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.timeNow()
+	t.entries[t.key(client)] = stickyEntry{server: server, expiresAt: now.Add(t.ttl)}
+
+	// Expired bindings of clients which do not come back are swept at most once per TTL.
+	if now.Sub(t.lastSweep) < t.ttl {
+		return
+	}
+
+	t.lastSweep = now
+	for key, entry := range t.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(t.entries, key)
+		}
+	}
+}
+
+// key returns the key identifying the given client in the table.
+func (t *StickyTable) key(client net.Addr) string {
+	addr := client.String()
+	if t.sourcePort {
+		return addr
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+// StickyTables holds the sticky tables of the services, keyed by service name,
+// in order to keep the client bindings across the dynamic configuration reloads.
+type StickyTables struct {
+	mu     sync.Mutex
+	tables map[string]*StickyTable
+}
+
+// NewStickyTables creates a new StickyTables.
+func NewStickyTables() *StickyTables {
+	return &StickyTables{tables: make(map[string]*StickyTable)}
+}
+
+// Get returns the sticky table of the given service, creating it if needed, with the given options.
+func (s *StickyTables) Get(serviceName string, ttl time.Duration, sourcePort bool) *StickyTable {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, ok := s.tables[serviceName]
+	if !ok {
+		table = NewStickyTable(ttl, sourcePort)
+		s.tables[serviceName] = table
+		return table
+	}
+
+	table.Update(ttl, sourcePort)
+	return table
+}
+
+// Retain drops the sticky tables of the services which are not in the given list.
+func (s *StickyTables) Retain(serviceNames map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.tables {
+		if _, ok := serviceNames[name]; !ok {
+			delete(s.tables, name)
+		}
+	}
+}
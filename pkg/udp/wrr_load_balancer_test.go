@@ -0,0 +1,210 @@
+package udp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBalancing(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		serversWeight map[string]int
+		serversDown   []string
+		totalCall     int
+		expected      map[string]int
+		expectError   bool
+	}{
+		{
+			desc:          "RoundRobin",
+			serversWeight: map[string]int{"h1": 1, "h2": 1},
+			totalCall:     4,
+			expected:      map[string]int{"h1": 2, "h2": 2},
+		},
+		{
+			desc:          "WeighedRoundRobin",
+			serversWeight: map[string]int{"h1": 3, "h2": 1},
+			totalCall:     16,
+			expected:      map[string]int{"h1": 12, "h2": 4},
+		},
+		{
+			desc:          "WeighedRoundRobin with one server down",
+			serversWeight: map[string]int{"h1": 3, "h2": 1, "h3": 2},
+			serversDown:   []string{"h1"},
+			totalCall:     6,
+			expected:      map[string]int{"h2": 2, "h3": 4},
+		},
+		{
+			desc:          "all servers down",
+			serversWeight: map[string]int{"h1": 1, "h2": 1},
+			serversDown:   []string{"h1", "h2"},
+			totalCall:     1,
+			expectError:   true,
+		},
+		{
+			desc:          "all servers with 0 weight",
+			serversWeight: map[string]int{"h1": 0, "h2": 0},
+			totalCall:     1,
+			expectError:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewWRRLoadBalancer(nil, false)
+			for name, weight := range test.serversWeight {
+				balancer.Add(name, HandlerFunc(func(conn *Conn) {}), &weight)
+			}
+
+			for _, name := range test.serversDown {
+				balancer.SetStatus(context.Background(), name, false)
+			}
+
+			calls := make(map[string]int)
+			for i := 0; i < test.totalCall; i++ {
+				handler, err := balancer.next()
+				if test.expectError {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				calls[handler.(server).name]++
+			}
+
+			assert.Equal(t, test.expected, calls)
+		})
+	}
+}
+
+func TestLoadBalancingStatusPropagation(t *testing.T) {
+	balancer := NewWRRLoadBalancer(nil, false)
+	err := balancer.RegisterStatusUpdater(func(up bool) {})
+	require.Error(t, err)
+
+	balancer = NewWRRLoadBalancer(nil, true)
+	balancer.Add("h1", HandlerFunc(func(conn *Conn) {}), nil)
+	balancer.Add("h2", HandlerFunc(func(conn *Conn) {}), nil)
+
+	var statuses []bool
+	err = balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "h1", false)
+	balancer.SetStatus(context.Background(), "h2", false)
+	balancer.SetStatus(context.Background(), "h1", true)
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func TestLoadBalancingSticky(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		sourcePort bool
+		expected   map[string]string
+	}{
+		{
+			desc: "source IP",
+			expected: map[string]string{
+				"10.0.0.1:5000": "h1",
+				"10.0.0.1:5001": "h1",
+				"10.0.0.2:5000": "h2",
+			},
+		},
+		{
+			desc:       "source IP and port",
+			sourcePort: true,
+			expected: map[string]string{
+				"10.0.0.1:5000": "h1",
+				"10.0.0.1:5001": "h2",
+				"10.0.0.2:5000": "h3",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			table := NewStickyTable(time.Minute, test.sourcePort)
+
+			balancer := NewWRRLoadBalancer(table, false)
+			for _, name := range []string{"h1", "h2", "h3"} {
+				balancer.Add(name, HandlerFunc(func(conn *Conn) {}), nil)
+			}
+
+			for _, addr := range []string{"10.0.0.1:5000", "10.0.0.1:5001", "10.0.0.2:5000"} {
+				// Each client opens several successive sessions.
+				for i := 0; i < 3; i++ {
+					handler, err := balancer.nextServer(&Conn{rAddr: mustResolveUDPAddr(t, addr)})
+					require.NoError(t, err)
+
+					assert.Equal(t, test.expected[addr], handler.(server).name, addr)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBalancingStickyFallback(t *testing.T) {
+	table := NewStickyTable(time.Minute, false)
+
+	now := time.Now()
+	table.timeNow = func() time.Time { return now }
+
+	newBalancer := func(names ...string) *WRRLoadBalancer {
+		balancer := NewWRRLoadBalancer(table, false)
+		for _, name := range names {
+			balancer.Add(name, HandlerFunc(func(conn *Conn) {}), nil)
+		}
+		return balancer
+	}
+
+	next := func(balancer *WRRLoadBalancer, addr string) string {
+		handler, err := balancer.nextServer(&Conn{rAddr: mustResolveUDPAddr(t, addr)})
+		require.NoError(t, err)
+
+		return handler.(server).name
+	}
+
+	balancer := newBalancer("h1", "h2", "h3")
+	assert.Equal(t, "h1", next(balancer, "10.0.0.1:5000"))
+	assert.Equal(t, "h2", next(balancer, "10.0.0.2:5000"))
+
+	// The client is bound to a new server when its server is down.
+	balancer.SetStatus(context.Background(), "h1", false)
+	assert.Equal(t, "h3", next(balancer, "10.0.0.1:5000"))
+
+	balancer.SetStatus(context.Background(), "h1", true)
+	assert.Equal(t, "h3", next(balancer, "10.0.0.1:5000"))
+
+	// The bindings survive a new balancer, built with the same table,
+	// and the client is bound to a new server when its server has been removed.
+	balancer = newBalancer("h1", "h2")
+	assert.Equal(t, "h2", next(balancer, "10.0.0.2:5000"))
+	assert.Equal(t, "h1", next(balancer, "10.0.0.1:5000"))
+
+	// The binding expires after the TTL.
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, "h2", next(balancer, "10.0.0.1:5000"))
+	assert.Len(t, table.entries, 1)
+}
+
+func mustResolveUDPAddr(t *testing.T, addr string) *net.UDPAddr {
+	t.Helper()
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	require.NoError(t, err)
+
+	return udpAddr
+}